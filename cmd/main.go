@@ -3,29 +3,98 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/DarRo9/Tenders/internal/config"
+	graphqlhandler "github.com/DarRo9/Tenders/internal/handlers/graphql"
 	httphandler "github.com/DarRo9/Tenders/internal/handlers/http"
+	"github.com/DarRo9/Tenders/internal/health"
+	"github.com/DarRo9/Tenders/internal/metrics"
 	"github.com/DarRo9/Tenders/internal/repository/postgres"
+	"github.com/DarRo9/Tenders/internal/scanning"
+	"github.com/DarRo9/Tenders/internal/sealing"
 	"github.com/DarRo9/Tenders/internal/server"
 	service "github.com/DarRo9/Tenders/internal/services"
+	"github.com/DarRo9/Tenders/internal/storage"
+	"github.com/DarRo9/Tenders/internal/tracing"
+	"github.com/sirupsen/logrus"
 )
 
+const usage = `usage: tenders [command] [flags]
+
+commands:
+  serve                 run the HTTP server (default)
+  migrate up            apply all pending migrations
+  migrate down N        roll back the last N migrations
+  migrate goto V        migrate up or down to version V
+  migrate status        print the current and the latest schema version
+  migrate force V       set version V without migrating and clear the dirty flag
+  seed                  fill the database with demo data, sized by the seed.* flags
+
+Run tenders -h to list the configuration flags.`
+
 func main() {
-	log := server.SetupLogger()
+	command, args := splitCommand(os.Args[1:])
 
-	cfg, err := config.New()
+	cfg, err := config.New(args)
 	if err != nil {
-		log.Fatal(err)
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	log, err := server.SetupLogger(&cfg.Log)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	
+	switch {
+	case len(command) == 0 || command[0] == "serve" && len(command) == 1:
+		serve(cfg, log)
+	case command[0] == "migrate":
+		if err := runMigrate(&cfg.PG, command[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case command[0] == "seed" && len(command) == 1:
+		if err := runSeed(cfg, os.Stdout, log); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
 
-	if err := server.Migrate(&cfg.PG, log); err != nil {
+// splitCommand separates the leading command words from the flags.
+func splitCommand(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i], args[i:]
+		}
+	}
+
+	return args, nil
+}
+
+func serve(cfg *config.Config, log *logrus.Logger) {
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	schemaVersion, err := server.Migrate(&cfg.PG, log)
+	if err != nil {
 		log.Fatal(err)
 	}
 
@@ -34,10 +103,64 @@ func main() {
 		log.Fatalf("pool connection error: %v", err)
 	}
 
-	srv := service.New(repo, log)
-	handler := httphandler.New(srv, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	probe := health.NewProbe()
+	probe.Register("postgres", repo.Ping)
+	probe.Register("migrations", server.MigrationCheck(repo, schemaVersion))
+
+	sealer, err := sealing.New(&cfg.Sealing)
+	if err != nil {
+		log.Fatalf("sealing setup error: %v", err)
+	}
+
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		log.Fatalf("storage setup error: %v", err)
+	}
+
+	scanner, err := scanning.New(&cfg.Scanning)
+	if err != nil {
+		log.Fatalf("scanning setup error: %v", err)
+	}
+	if cfg.Scanning.Driver != config.ScanningClamAV {
+		log.Warnf("scanning driver %q is meant for development, attachments are not checked for malware", cfg.Scanning.Driver)
+	}
+
+	var m *metrics.Metrics
+	var srv *service.Service
+	if cfg.Features.Metrics {
+		m = metrics.New()
+		m.MustRegister(metrics.NewPoolCollector(repo.DB))
+
+		refresher := health.NewHeartbeat(3 * cfg.Metrics.RefreshInterval)
+		go m.RefreshOpenTenders(ctx, repo, cfg.Metrics.RefreshInterval, refresher.Beat, log)
+		probe.Register("open_tenders_refresher", refresher.Check)
 
-	app := server.New(handler.CreateRoutes(), &cfg.Server)
+		srv = service.New(repo, m, &cfg.Bids, &cfg.Platform, sealer, store, &cfg.Storage, scanner, log)
+	} else {
+		srv = service.New(repo, nil, &cfg.Bids, &cfg.Platform, sealer, store, &cfg.Storage, scanner, log)
+	}
+
+	closer := health.NewHeartbeat(3 * cfg.Auction.CloseInterval)
+	go srv.RunAuctionCloser(ctx, cfg.Auction.CloseInterval, closer.Beat)
+	probe.Register("auction_closer", closer.Check)
+
+	if store != nil {
+		scans := health.NewHeartbeat(3 * cfg.Scanning.Interval)
+		go srv.RunAttachmentScanner(ctx, cfg.Scanning.Interval, scans.Beat)
+		probe.Register("attachment_scanner", scans.Check)
+	}
+
+	var gql http.Handler
+	if cfg.Features.GraphQL {
+		gql = graphqlhandler.New(srv, repo, log)
+	}
+
+	handler := httphandler.New(srv, gql, m, probe, cfg.Server.TransferTimeout, log)
+
+	app := server.New(handler.CreateRoutes(), &cfg.Server, probe)
 	go func() {
 		log.Infof("start server on %v", cfg.Server.Address)
 
@@ -54,5 +177,11 @@ func main() {
 		log.Errorf("server shutting down error: %s", err)
 	}
 
+	cancel()
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Errorf("tracing shutting down error: %s", err)
+	}
+
 	log.Info("the server stopped")
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/server"
+)
+
+func runMigrate(cfg *config.PGConfig, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	m, err := server.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = m.Up()
+	case args[0] == "down" && len(args) == 2:
+		var n int
+		if n, err = strconv.Atoi(args[1]); err == nil {
+			err = m.Down(n)
+		}
+	case args[0] == "goto" && len(args) == 2:
+		var version uint64
+		if version, err = strconv.ParseUint(args[1], 10, 64); err == nil {
+			err = m.Goto(uint(version))
+		}
+	case args[0] == "force" && len(args) == 2:
+		var version int
+		if version, err = strconv.Atoi(args[1]); err == nil {
+			err = m.Force(version)
+		}
+	case args[0] == "status" && len(args) == 1:
+	default:
+		return errors.New(usage)
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", args[0], err)
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+
+	return nil
+}
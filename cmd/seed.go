@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/repository/postgres"
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/DarRo9/Tenders/internal/server"
+	"github.com/sirupsen/logrus"
+)
+
+func runSeed(cfg *config.Config, out io.Writer, log *logrus.Logger) error {
+	if _, err := server.Migrate(&cfg.PG, log); err != nil {
+		return err
+	}
+
+	repo, err := postgres.New(&cfg.PG)
+	if err != nil {
+		return fmt.Errorf("pool connection error: %w", err)
+	}
+	defer repo.DB.Close()
+
+	data := seed.Generate(&cfg.Seed)
+	if err := repo.Seed(context.Background(), data, cfg.Seed.Reset); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "seed %d: %d organizations, %d employees, %d tenders (%d versions), %d bids (%d versions), %d decisions, %d messages\n",
+		cfg.Seed.RandomSeed, len(data.Organizations), len(data.Employees), len(data.Tenders), len(data.TenderVersions),
+		len(data.Bids), len(data.BidVersions), len(data.Decisions), len(data.Messages))
+
+	return nil
+}
@@ -1,40 +1,273 @@
 package config
 
 import (
-	"os"
+	"time"
 )
 
 type PGConfig struct {
-	Conn     string
-	Username string
-	Password string
-	Host     string
-	Port     string
-	Database string
+	// Conn is the connection URL. When it is empty, it is built from the
+	// separate parts below.
+	Conn     string `yaml:"conn"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Database string `yaml:"database"`
+	SSLMode  string `yaml:"sslmode"`
+
+	MaxConns        int32         `yaml:"max_conns"`
+	MinConns        int32         `yaml:"min_conns"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+
+	// AutoMigrate applies pending migrations on boot. Disable it when several
+	// replicas start at once and run the migrate command instead.
+	AutoMigrate bool `yaml:"auto_migrate"`
 }
 
 type Config struct {
-	Server ServerConfig
-	PG     PGConfig
+	Server   ServerConfig   `yaml:"server"`
+	PG       PGConfig       `yaml:"postgres"`
+	Log      LogConfig      `yaml:"log"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Features FeaturesConfig `yaml:"features"`
+	Bids     BidsConfig     `yaml:"bids"`
+	Sealing  SealingConfig  `yaml:"sealing"`
+	Auction  AuctionConfig  `yaml:"auction"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Scanning ScanningConfig `yaml:"scanning"`
+	Platform PlatformConfig `yaml:"platform"`
+	Seed     SeedConfig     `yaml:"seed"`
 }
 
 type ServerConfig struct {
-	Address string
+	Address           string        `yaml:"address"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownDelay is how long the server keeps serving while reporting
+	// not ready, ShutdownTimeout is how long it then waits for requests.
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// TransferTimeout replaces the read and write timeouts for attachment
+	// uploads and downloads, which carry up to storage.max_size bytes.
+	TransferTimeout time.Duration `yaml:"transfer_timeout"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	// SampleInitial and SampleThereafter control log sampling: per second
+	// the first SampleInitial identical entries are written, then every
+	// SampleThereafter-th. Zero SampleInitial disables sampling.
+	SampleInitial    int `yaml:"sample_initial"`
+	SampleThereafter int `yaml:"sample_thereafter"`
+}
+
+type MetricsConfig struct {
+	// RefreshInterval is how often the open tenders gauge is recomputed.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of none, otlp or stdout.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the OTLP/HTTP collector URL. When empty the standard
+	// OTEL_EXPORTER_OTLP_* variables apply.
+	Endpoint    string  `yaml:"endpoint"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// SeedConfig sizes the demo dataset written by the seed command. The same
+// RandomSeed always produces the same data, identifiers included.
+type SeedConfig struct {
+	RandomSeed               uint64 `yaml:"random_seed"`
+	OrganizationsPerType     int    `yaml:"organizations_per_type"`
+	EmployeesPerOrganization int    `yaml:"employees_per_organization"`
+	TendersPerOrganization   int    `yaml:"tenders_per_organization"`
+	BidsPerTender            int    `yaml:"bids_per_tender"`
+	// Reset truncates all tables before seeding.
+	Reset bool `yaml:"reset"`
+}
+
+// FeaturesConfig switches optional parts of the API on and off.
+type FeaturesConfig struct {
+	GraphQL bool `yaml:"graphql"`
+	Metrics bool `yaml:"metrics"`
+}
+
+// Resubmission policies decide whether an author may bid again on a tender
+// after the previous bid was closed.
+const (
+	// ResubmissionNever allows a single bid per author and tender.
+	ResubmissionNever = "never"
+	// ResubmissionAfterWithdrawal allows a new bid after the author withdrew
+	// the previous one, but not after a rejection.
+	ResubmissionAfterWithdrawal = "after_withdrawal"
+	// ResubmissionAlways allows a new bid after a withdrawal or a rejection.
+	ResubmissionAlways = "always"
+)
+
+type BidsConfig struct {
+	// Resubmission is one of never, after_withdrawal or always.
+	Resubmission string `yaml:"resubmission"`
 }
 
+type SealingConfig struct {
+	// MasterKey is the base64 encoded 32 byte key that wraps the data keys
+	// of sealed tenders. Sealed tenders are disabled without it.
+	MasterKey string `yaml:"master_key"`
+}
 
-func New() (*Config, error) {
+type AuctionConfig struct {
+	// CloseInterval is how often ended auctions are looked for and awarded.
+	CloseInterval time.Duration `yaml:"close_interval"`
+}
+
+// Storage drivers keep the contents of attachments.
+const (
+	// StorageNone disables attachments.
+	StorageNone = "none"
+	// StorageLocal keeps the files in a directory.
+	StorageLocal = "local"
+	// StorageS3 keeps the files in a bucket of an S3-compatible service.
+	StorageS3 = "s3"
+)
+
+type StorageConfig struct {
+	// Driver is one of none, local or s3.
+	Driver string `yaml:"driver"`
+	// Dir is the directory of the local driver.
+	Dir string `yaml:"dir"`
+	// MaxSize is the largest attachment accepted, in bytes.
+	MaxSize int64 `yaml:"max_size"`
+	// AllowedTypes are the media types files may be attached with.
+	AllowedTypes []string `yaml:"allowed_types"`
+	S3           S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	// Endpoint is the URL of the service, e.g. https://s3.eu-central-1.amazonaws.com.
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	// PathStyle addresses the bucket in the path rather than in the host
+	// name, as MinIO and most self-hosted services expect.
+	PathStyle bool `yaml:"path_style"`
+}
+
+// Scanning drivers check attachments for malware before they can be
+// downloaded.
+const (
+	// ScanningNoop declares every file clean. Meant for development.
+	ScanningNoop = "noop"
+	// ScanningEICAR only detects the EICAR test file. Meant for development.
+	ScanningEICAR = "eicar"
+	// ScanningClamAV streams the files to a clamd daemon.
+	ScanningClamAV = "clamav"
+)
+
+type ScanningConfig struct {
+	// Driver is one of noop, eicar or clamav.
+	Driver string `yaml:"driver"`
+	// Interval is how often quarantined attachments are looked for.
+	Interval time.Duration `yaml:"interval"`
+	ClamAV   ClamAVConfig  `yaml:"clamav"`
+}
+
+type ClamAVConfig struct {
+	// Address is the host:port of the TCP socket of clamd.
+	Address string `yaml:"address"`
+	// Timeout bounds the scan of a single file.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type PlatformConfig struct {
+	// Admins are the usernames of the platform admins, who manage the
+	// global debarments of suppliers.
+	Admins []string `yaml:"admins"`
+}
+
+func defaults() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Address: os.Getenv("SERVER_ADDRESS"),
+			Address:           "0.0.0.0:8080",
+			ReadTimeout:       10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownDelay:     5 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+			TransferTimeout:   5 * time.Minute,
 		},
 		PG: PGConfig{
-			Conn:     os.Getenv("POSTGRES_CONN"),
-			Username: os.Getenv("POSTGRES_USERNAME"),
-			Password: os.Getenv("POSTGRES_PASSWORD"),
-			Host:     os.Getenv("POSTGRES_HOST"),
-			Port:     os.Getenv("POSTGRES_PORT"),
-			Database: os.Getenv("POSTGRES_DATABASE"),
-		},
-	}, nil
+			Port:            "5432",
+			SSLMode:         "prefer",
+			MaxConns:        10,
+			MinConns:        0,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+			AutoMigrate:     true,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Metrics: MetricsConfig{
+			RefreshInterval: 30 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Features: FeaturesConfig{
+			GraphQL: true,
+			Metrics: true,
+		},
+		Bids: BidsConfig{
+			Resubmission: ResubmissionAfterWithdrawal,
+		},
+		Auction: AuctionConfig{
+			CloseInterval: 10 * time.Second,
+		},
+		Storage: StorageConfig{
+			Driver:  StorageNone,
+			Dir:     "attachments",
+			MaxSize: 20 << 20,
+			AllowedTypes: []string{
+				"application/pdf",
+				"application/zip",
+				"application/msword",
+				"application/vnd.ms-excel",
+				"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+				"image/jpeg",
+				"image/png",
+				"text/csv",
+				"text/plain",
+			},
+			S3: S3Config{
+				Region: "us-east-1",
+			},
+		},
+		Scanning: ScanningConfig{
+			Driver:   ScanningClamAV,
+			Interval: 5 * time.Second,
+			ClamAV: ClamAVConfig{
+				Address: "localhost:3310",
+				Timeout: time.Minute,
+			},
+		},
+		Seed: SeedConfig{
+			RandomSeed:               1,
+			OrganizationsPerType:     2,
+			EmployeesPerOrganization: 3,
+			TendersPerOrganization:   4,
+			BidsPerTender:            3,
+		},
+	}
 }
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileEnv = "CONFIG_FILE"
+
+// binding ties a configuration value to its environment variable and its
+// command line flag. The flag name is the dotted YAML path of the value.
+type binding struct {
+	name  string
+	env   string
+	usage string
+	set   func(string) error
+}
+
+func (c *Config) bindings() []binding {
+	return []binding{
+		{"server.address", "SERVER_ADDRESS", "address the HTTP server listens on", stringValue(&c.Server.Address)},
+		{"server.read_timeout", "SERVER_READ_TIMEOUT", "maximum duration for reading a request", durationValue(&c.Server.ReadTimeout)},
+		{"server.read_header_timeout", "SERVER_READ_HEADER_TIMEOUT", "maximum duration for reading request headers", durationValue(&c.Server.ReadHeaderTimeout)},
+		{"server.write_timeout", "SERVER_WRITE_TIMEOUT", "maximum duration for writing a response", durationValue(&c.Server.WriteTimeout)},
+		{"server.idle_timeout", "SERVER_IDLE_TIMEOUT", "maximum keep-alive idle duration", durationValue(&c.Server.IdleTimeout)},
+		{"server.shutdown_delay", "SERVER_SHUTDOWN_DELAY", "how long to report not ready before draining", durationValue(&c.Server.ShutdownDelay)},
+		{"server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", "how long to wait for in-flight requests", durationValue(&c.Server.ShutdownTimeout)},
+		{"server.transfer_timeout", "SERVER_TRANSFER_TIMEOUT", "maximum duration for an attachment upload or download", durationValue(&c.Server.TransferTimeout)},
+
+		{"postgres.conn", "POSTGRES_CONN", "PostgreSQL connection URL", stringValue(&c.PG.Conn)},
+		{"postgres.username", "POSTGRES_USERNAME", "PostgreSQL user", stringValue(&c.PG.Username)},
+		{"postgres.password", "POSTGRES_PASSWORD", "PostgreSQL password", stringValue(&c.PG.Password)},
+		{"postgres.host", "POSTGRES_HOST", "PostgreSQL host", stringValue(&c.PG.Host)},
+		{"postgres.port", "POSTGRES_PORT", "PostgreSQL port", stringValue(&c.PG.Port)},
+		{"postgres.database", "POSTGRES_DATABASE", "PostgreSQL database", stringValue(&c.PG.Database)},
+		{"postgres.sslmode", "POSTGRES_SSLMODE", "PostgreSQL sslmode", stringValue(&c.PG.SSLMode)},
+		{"postgres.max_conns", "POSTGRES_MAX_CONNS", "maximum size of the connection pool", int32Value(&c.PG.MaxConns)},
+		{"postgres.min_conns", "POSTGRES_MIN_CONNS", "minimum size of the connection pool", int32Value(&c.PG.MinConns)},
+		{"postgres.max_conn_lifetime", "POSTGRES_MAX_CONN_LIFETIME", "maximum lifetime of a connection", durationValue(&c.PG.MaxConnLifetime)},
+		{"postgres.max_conn_idle_time", "POSTGRES_MAX_CONN_IDLE_TIME", "maximum idle time of a connection", durationValue(&c.PG.MaxConnIdleTime)},
+
+		{"postgres.auto_migrate", "POSTGRES_AUTO_MIGRATE", "apply pending migrations on boot", boolValue(&c.PG.AutoMigrate)},
+
+		{"log.level", "LOG_LEVEL", "log level: trace, debug, info, warn, error", stringValue(&c.Log.Level)},
+		{"log.format", "LOG_FORMAT", "log format: json or pretty", stringValue(&c.Log.Format)},
+		{"log.sample_initial", "LOG_SAMPLE_INITIAL", "identical entries per second written before sampling, 0 disables sampling", intValue(&c.Log.SampleInitial)},
+		{"log.sample_thereafter", "LOG_SAMPLE_THEREAFTER", "write every n-th identical entry once sampling", intValue(&c.Log.SampleThereafter)},
+
+		{"metrics.refresh_interval", "METRICS_REFRESH_INTERVAL", "how often the open tenders gauge is refreshed", durationValue(&c.Metrics.RefreshInterval)},
+
+		{"tracing.exporter", "TRACING_EXPORTER", "trace exporter: none, otlp or stdout", stringValue(&c.Tracing.Exporter)},
+		{"tracing.endpoint", "TRACING_ENDPOINT", "OTLP/HTTP collector URL", stringValue(&c.Tracing.Endpoint)},
+		{"tracing.sample_ratio", "TRACING_SAMPLE_RATIO", "share of traces to sample, from 0 to 1", float64Value(&c.Tracing.SampleRatio)},
+
+		{"features.graphql", "FEATURE_GRAPHQL", "expose the GraphQL endpoint", boolValue(&c.Features.GraphQL)},
+		{"features.metrics", "FEATURE_METRICS", "expose Prometheus metrics", boolValue(&c.Features.Metrics)},
+
+		{"bids.resubmission", "BIDS_RESUBMISSION", "when an author may bid again: never, after_withdrawal or always", stringValue(&c.Bids.Resubmission)},
+
+		{"sealing.master_key", "SEALING_MASTER_KEY", "base64 encoded 32 byte key for sealed tenders, empty disables them", stringValue(&c.Sealing.MasterKey)},
+
+		{"auction.close_interval", "AUCTION_CLOSE_INTERVAL", "how often ended auctions are awarded", durationValue(&c.Auction.CloseInterval)},
+
+		{"storage.driver", "STORAGE_DRIVER", "attachment storage: none, local or s3", stringValue(&c.Storage.Driver)},
+		{"storage.dir", "STORAGE_DIR", "directory of the local attachment storage", stringValue(&c.Storage.Dir)},
+		{"storage.max_size", "STORAGE_MAX_SIZE", "largest attachment accepted, in bytes", int64Value(&c.Storage.MaxSize)},
+		{"storage.allowed_types", "STORAGE_ALLOWED_TYPES", "comma separated media types files may be attached with", listValue(&c.Storage.AllowedTypes)},
+		{"storage.s3.endpoint", "STORAGE_S3_ENDPOINT", "URL of the S3-compatible service", stringValue(&c.Storage.S3.Endpoint)},
+		{"storage.s3.region", "STORAGE_S3_REGION", "region of the bucket", stringValue(&c.Storage.S3.Region)},
+		{"storage.s3.bucket", "STORAGE_S3_BUCKET", "bucket of the attachments", stringValue(&c.Storage.S3.Bucket)},
+		{"storage.s3.access_key", "STORAGE_S3_ACCESS_KEY", "access key of the bucket", stringValue(&c.Storage.S3.AccessKey)},
+		{"storage.s3.secret_key", "STORAGE_S3_SECRET_KEY", "secret key of the bucket", stringValue(&c.Storage.S3.SecretKey)},
+		{"storage.s3.path_style", "STORAGE_S3_PATH_STYLE", "address the bucket in the path instead of the host name", boolValue(&c.Storage.S3.PathStyle)},
+
+		{"scanning.driver", "SCANNING_DRIVER", "attachment malware scanner: noop, eicar or clamav", stringValue(&c.Scanning.Driver)},
+		{"scanning.interval", "SCANNING_INTERVAL", "how often quarantined attachments are scanned", durationValue(&c.Scanning.Interval)},
+		{"scanning.clamav.address", "SCANNING_CLAMAV_ADDRESS", "host:port of the clamd TCP socket", stringValue(&c.Scanning.ClamAV.Address)},
+		{"scanning.clamav.timeout", "SCANNING_CLAMAV_TIMEOUT", "maximum duration of the scan of a file", durationValue(&c.Scanning.ClamAV.Timeout)},
+
+		{"platform.admins", "PLATFORM_ADMINS", "comma separated usernames of the platform admins", listValue(&c.Platform.Admins)},
+
+		{"seed.random_seed", "SEED_RANDOM_SEED", "seed of the demo data generator", uint64Value(&c.Seed.RandomSeed)},
+		{"seed.organizations_per_type", "SEED_ORGANIZATIONS_PER_TYPE", "organizations generated per organization type", intValue(&c.Seed.OrganizationsPerType)},
+		{"seed.employees_per_organization", "SEED_EMPLOYEES_PER_ORGANIZATION", "responsible employees generated per organization", intValue(&c.Seed.EmployeesPerOrganization)},
+		{"seed.tenders_per_organization", "SEED_TENDERS_PER_ORGANIZATION", "tenders generated per organization", intValue(&c.Seed.TendersPerOrganization)},
+		{"seed.bids_per_tender", "SEED_BIDS_PER_TENDER", "maximum bids generated per published tender", intValue(&c.Seed.BidsPerTender)},
+		{"seed.reset", "SEED_RESET", "truncate all tables before seeding", boolValue(&c.Seed.Reset)},
+	}
+}
+
+// New loads the configuration from, in increasing order of precedence, the
+// defaults, the YAML file given by --config or CONFIG_FILE, the environment
+// and the command line flags in args. The result is validated.
+func New(args []string) (*Config, error) {
+	cfg := defaults()
+	bindings := cfg.bindings()
+
+	fs := flag.NewFlagSet("tenders", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFile := fs.String("config", os.Getenv(configFileEnv), "path to a YAML configuration file")
+	flagValues := make(map[string]*string, len(bindings))
+	for _, b := range bindings {
+		flagValues[b.name] = fs.String(b.name, "", fmt.Sprintf("%s (env %s)", b.usage, b.env))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var problems []string
+	for _, b := range bindings {
+		if value, ok := os.LookupEnv(b.env); ok && value != "" {
+			if err := b.set(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", b.env, err))
+			}
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if value, ok := flagValues[f.Name]; ok {
+			for _, b := range bindings {
+				if b.name == f.Name {
+					if err := b.set(*value); err != nil {
+						problems = append(problems, fmt.Sprintf("--%s: %v", b.name, err))
+					}
+				}
+			}
+		}
+	})
+
+	if len(problems) != 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	if cfg.PG.Conn == "" {
+		cfg.PG.Conn = cfg.PG.buildDSN()
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func stringValue(target *string) func(string) error {
+	return func(value string) error {
+		*target = value
+		return nil
+	}
+}
+
+func intValue(target *int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*target = n
+		return nil
+	}
+}
+
+func int32Value(target *int32) func(string) error {
+	return func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*target = int32(n)
+		return nil
+	}
+}
+
+func int64Value(target *int64) func(string) error {
+	return func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*target = n
+		return nil
+	}
+}
+
+func uint64Value(target *uint64) func(string) error {
+	return func(value string) error {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a non-negative integer", value)
+		}
+		*target = n
+		return nil
+	}
+}
+
+func float64Value(target *float64) func(string) error {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*target = f
+		return nil
+	}
+}
+
+func boolValue(target *bool) func(string) error {
+	return func(value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		*target = b
+		return nil
+	}
+}
+
+func listValue(target *[]string) func(string) error {
+	return func(value string) error {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*target = items
+		return nil
+	}
+}
+
+func durationValue(target *time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", value)
+		}
+		*target = d
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationError lists every problem found in the configuration at once, so
+// that a broken deployment can be fixed in one go.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// buildDSN assembles the connection URL from the separate parts. It returns
+// an empty string when the parts are missing, which validate reports.
+func (c *PGConfig) buildDSN() string {
+	if c.Host == "" || c.Username == "" || c.Database == "" {
+		return ""
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     c.Database,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+
+	return dsn.String()
+}
+
+func (c *Config) validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Address != "", "server.address is required")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout must be positive")
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout must be positive")
+	check(c.Server.ShutdownDelay >= 0, "server.shutdown_delay must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.TransferTimeout > 0, "server.transfer_timeout must be positive")
+
+	check(c.PG.Conn != "", "postgres.conn or postgres.host, postgres.username and postgres.database are required")
+	if c.PG.Conn != "" {
+		_, err := url.Parse(c.PG.Conn)
+		check(err == nil, "postgres.conn is not a valid URL")
+	}
+	check(c.PG.MaxConns > 0, "postgres.max_conns must be positive")
+	check(c.PG.MinConns >= 0, "postgres.min_conns must not be negative")
+	check(c.PG.MinConns <= c.PG.MaxConns, "postgres.min_conns must not exceed postgres.max_conns")
+
+	_, err := logrus.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level %q is unknown", c.Log.Level)
+	check(oneOf(c.Log.Format, "json", "pretty"), "log.format must be json or pretty, got %q", c.Log.Format)
+	check(c.Log.SampleInitial >= 0, "log.sample_initial must not be negative")
+	check(c.Log.SampleThereafter >= 0, "log.sample_thereafter must not be negative")
+
+	check(c.Metrics.RefreshInterval > 0, "metrics.refresh_interval must be positive")
+
+	check(oneOf(c.Tracing.Exporter, "none", "otlp", "stdout"), "tracing.exporter must be none, otlp or stdout, got %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	check(oneOf(c.Bids.Resubmission, ResubmissionNever, ResubmissionAfterWithdrawal, ResubmissionAlways),
+		"bids.resubmission must be never, after_withdrawal or always, got %q", c.Bids.Resubmission)
+
+	if c.Sealing.MasterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(c.Sealing.MasterKey)
+		check(err == nil && len(key) == 32, "sealing.master_key must be 32 bytes encoded in base64")
+	}
+
+	check(c.Auction.CloseInterval > 0, "auction.close_interval must be positive")
+
+	check(oneOf(c.Storage.Driver, StorageNone, StorageLocal, StorageS3), "storage.driver must be none, local or s3, got %q", c.Storage.Driver)
+	check(c.Storage.MaxSize > 0, "storage.max_size must be positive")
+	check(len(c.Storage.AllowedTypes) > 0, "storage.allowed_types must not be empty")
+	switch c.Storage.Driver {
+	case StorageLocal:
+		check(c.Storage.Dir != "", "storage.dir is required by the local driver")
+	case StorageS3:
+		endpoint, err := url.Parse(c.Storage.S3.Endpoint)
+		check(err == nil && endpoint.Host != "" && oneOf(endpoint.Scheme, "http", "https"), "storage.s3.endpoint must be an http or https URL")
+		check(c.Storage.S3.Region != "", "storage.s3.region is required by the s3 driver")
+		check(c.Storage.S3.Bucket != "", "storage.s3.bucket is required by the s3 driver")
+		check(c.Storage.S3.AccessKey != "" && c.Storage.S3.SecretKey != "", "storage.s3.access_key and storage.s3.secret_key are required by the s3 driver")
+	}
+
+	check(oneOf(c.Scanning.Driver, ScanningNoop, ScanningEICAR, ScanningClamAV), "scanning.driver must be noop, eicar or clamav, got %q", c.Scanning.Driver)
+	check(c.Scanning.Interval > 0, "scanning.interval must be positive")
+	if c.Scanning.Driver == ScanningClamAV {
+		_, _, err := net.SplitHostPort(c.Scanning.ClamAV.Address)
+		check(err == nil, "scanning.clamav.address must be host:port")
+		check(c.Scanning.ClamAV.Timeout > 0, "scanning.clamav.timeout must be positive")
+	}
+
+	check(c.Seed.OrganizationsPerType >= 0, "seed.organizations_per_type must not be negative")
+	check(c.Seed.EmployeesPerOrganization > 0, "seed.employees_per_organization must be positive")
+	check(c.Seed.TendersPerOrganization >= 0, "seed.tenders_per_organization must not be negative")
+	check(c.Seed.BidsPerTender >= 0, "seed.bids_per_tender must not be negative")
+
+	if len(problems) != 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+
+	return false
+}
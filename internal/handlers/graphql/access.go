@@ -0,0 +1,103 @@
+package graphqlhandler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// access applies the same permission checks as the REST handlers and
+// memoizes them for the lifetime of a single GraphQL request, so that a list
+// of bids on one tender costs one responsibility check instead of one per bid.
+type access struct {
+	repo     repository.Repository
+	username string
+
+	mu    sync.Mutex
+	cache map[string]error
+}
+
+func newAccess(repo repository.Repository, username string) *access {
+	return &access{
+		repo:     repo,
+		username: username,
+		cache:    make(map[string]error),
+	}
+}
+
+func (a *access) memo(key string, check func() error) error {
+	a.mu.Lock()
+	err, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok {
+		return err
+	}
+
+	err = check()
+
+	a.mu.Lock()
+	a.cache[key] = err
+	a.mu.Unlock()
+
+	return err
+}
+
+// canSeeTender mirrors GetStatusOfTender: published and closed tenders are
+// visible to everybody they are not hidden from, the rest to the
+// organization responsibles only.
+func (a *access) canSeeTender(ctx context.Context, tender *models.TenderResponse) error {
+	readable := tender.Status == models.TenderStatusPublished || tender.Status == models.TenderStatusClosed
+	if readable && tender.Visibility == models.TenderVisibilityPublic {
+		return nil
+	}
+
+	err := a.memo("organization:"+string(tender.OrganizationID), func() error {
+		return a.repo.ControlOrganizationPermission(ctx, &tender.OrganizationID, a.username)
+	})
+	if !errors.Is(err, repository.ErrRelationNotExist) || !readable {
+		return err
+	}
+
+	return a.memo("visibility:"+tender.ID, func() error {
+		userID, err := a.repo.ControlBidCreationByID(ctx, a.username)
+		if err != nil {
+			return err
+		}
+
+		return a.repo.ControlTenderVisibility(ctx, tender.ID, userID)
+	})
+}
+
+// canManageTender mirrors GetBidsOfTender and GetCommentsOfBid.
+func (a *access) canManageTender(ctx context.Context, tenderID string) error {
+	return a.memo("tender:"+tenderID, func() error {
+		return a.repo.ControlUserResponsibilityForTender(ctx, tenderID, a.username)
+	})
+}
+
+// canSeeBid mirrors GetStatusOfBids: the author side always sees the bid, the
+// tender responsibles see it once it leaves the Created status.
+func (a *access) canSeeBid(ctx context.Context, bid *models.BidResponse) error {
+	err := a.memo("bid:"+bid.ID, func() error {
+		return a.repo.ControlUserResponsibilityForAuthorBid(ctx, bid.ID, a.username)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, repository.ErrRelationNotExist):
+		return err
+	}
+
+	if err := a.canManageTender(ctx, bid.TenderID); err != nil {
+		return err
+	}
+
+	if bid.Status == models.BidStatusCreated {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package graphqlhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/DarRo9/Tenders/internal/logger"
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxDepth       = 8
+	maxParallelism = 10
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type Service interface {
+	GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, username string, limit, offset int32) ([]*models.TenderResponse, error)
+	GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error)
+	GetInvitedTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error)
+	GetBidsOfUser(ctx context.Context, username string, limit, offset int32) ([]*models.BidResponse, error)
+}
+
+type Handler struct {
+	srv    Service
+	repo   repository.Repository
+	schema *graphql.Schema
+	log    *logrus.Logger
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type requestContext struct {
+	username string
+	loaders  *loaders
+	access   *access
+}
+
+type requestContextKey struct{}
+
+func New(srv Service, repo repository.Repository, log *logrus.Logger) *Handler {
+	h := &Handler{srv: srv, repo: repo, log: log}
+	h.schema = graphql.MustParseSchema(schema, &queryResolver{h: h},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+
+	return h
+}
+
+// ServeHTTP executes a GraphQL query. The caller is identified the same way
+// as in the REST API, by the username query parameter.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	ctx := context.WithValue(r.Context(), requestContextKey{}, &requestContext{
+		username: username,
+		loaders:  newLoaders(h.repo),
+		access:   newAccess(h.repo, username),
+	})
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, err := range response.Errors {
+		logger.FromContext(ctx, h.log).Debug(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.FromContext(ctx, h.log).Error(err)
+	}
+}
+
+func fromContext(ctx context.Context) *requestContext {
+	return ctx.Value(requestContextKey{}).(*requestContext)
+}
+
+func isUUID(s string) bool {
+	return uuidRegexp.MatchString(s)
+}
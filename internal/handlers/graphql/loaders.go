@@ -0,0 +1,106 @@
+package graphqlhandler
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loaders struct {
+	tenders         *dataloader.Loader[string, *models.TenderResponse]
+	tenderVersions  *dataloader.Loader[string, []*models.TenderResponse]
+	bids            *dataloader.Loader[string, *models.BidResponse]
+	bidsByTender    *dataloader.Loader[string, []*models.BidResponse]
+	bidVersions     *dataloader.Loader[string, []*models.BidResponse]
+	decisions       *dataloader.Loader[string, []*models.BidDecisionResponse]
+	feedback        *dataloader.Loader[string, []*models.BidReviewResponse]
+	employees       *dataloader.Loader[string, *models.Employee]
+	employeesByName *dataloader.Loader[string, *models.Employee]
+	organizations   *dataloader.Loader[string, *models.Organization]
+	criteria        *dataloader.Loader[string, []*models.Criterion]
+	attachments     *dataloader.Loader[string, *models.Attachment]
+}
+
+func newLoaders(repo repository.LoaderRepository) *loaders {
+	return &loaders{
+		tenders: newOneLoader(repo.GetTendersByIDs,
+			func(t *models.TenderResponse) string { return t.ID }),
+		tenderVersions: newManyLoader(repo.GetTenderVersionsByTenderIDs,
+			func(t *models.TenderResponse) string { return t.ID }),
+		bids: newOneLoader(repo.GetBidsByIDs,
+			func(b *models.BidResponse) string { return b.ID }),
+		bidsByTender: newManyLoader(repo.GetBidsByTenderIDs,
+			func(b *models.BidResponse) string { return b.TenderID }),
+		bidVersions: newManyLoader(repo.GetBidVersionsByBidIDs,
+			func(b *models.BidResponse) string { return b.ID }),
+		decisions: newManyLoader(repo.GetDecisionsByBidIDs,
+			func(d *models.BidDecisionResponse) string { return d.BidID }),
+		feedback: newManyLoader(repo.GetFeedbackByBidIDs,
+			func(f *models.BidReviewResponse) string { return f.BidID }),
+		employees: newOneLoader(repo.GetEmployeesByIDs,
+			func(e *models.Employee) string { return e.ID }),
+		employeesByName: newOneLoader(repo.GetEmployeesByUsernames,
+			func(e *models.Employee) string { return e.Username }),
+		organizations: newOneLoader(repo.GetOrganizationsByIDs,
+			func(o *models.Organization) string { return string(o.ID) }),
+		criteria: newManyLoader(repo.GetCriteriaByTenderIDs,
+			func(c *models.Criterion) string { return c.TenderID }),
+		attachments: newOneLoader(repo.GetAttachmentsByIDs,
+			func(a *models.Attachment) string { return a.ID }),
+	}
+}
+
+// newOneLoader batches lookups of single entities by key. Keys without a
+// matching entity resolve to nil, which the schema exposes as null.
+func newOneLoader[V any](fetch func(context.Context, []string) ([]V, error), key func(V) string) *dataloader.Loader[string, V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []string) []*dataloader.Result[V] {
+		results := make([]*dataloader.Result[V], len(keys))
+
+		items, err := fetch(ctx, keys)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[V]{Error: err}
+			}
+			return results
+		}
+
+		byKey := make(map[string]V, len(items))
+		for _, item := range items {
+			byKey[key(item)] = item
+		}
+
+		for i, k := range keys {
+			results[i] = &dataloader.Result[V]{Data: byKey[k]}
+		}
+
+		return results
+	})
+}
+
+// newManyLoader batches lookups of entity lists grouped by a parent key.
+func newManyLoader[V any](fetch func(context.Context, []string) ([]V, error), key func(V) string) *dataloader.Loader[string, []V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []string) []*dataloader.Result[[]V] {
+		results := make([]*dataloader.Result[[]V], len(keys))
+
+		items, err := fetch(ctx, keys)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]V]{Error: err}
+			}
+			return results
+		}
+
+		byKey := make(map[string][]V, len(keys))
+		for _, item := range items {
+			byKey[key(item)] = append(byKey[key(item)], item)
+		}
+
+		for i, k := range keys {
+			results[i] = &dataloader.Result[[]V]{Data: byKey[k]}
+		}
+
+		return results
+	})
+}
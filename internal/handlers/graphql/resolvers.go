@@ -0,0 +1,533 @@
+package graphqlhandler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type paginationArgs struct {
+	Limit  int32
+	Offset int32
+}
+
+type queryResolver struct {
+	h *Handler
+}
+
+func (r *queryResolver) Tender(ctx context.Context, args struct{ ID graphql.ID }) (*tenderResolver, error) {
+	if !isUUID(string(args.ID)) {
+		return nil, nil
+	}
+
+	req := fromContext(ctx)
+
+	tender, err := req.loaders.tenders.Load(ctx, string(args.ID))()
+	if err != nil || tender == nil {
+		return nil, err
+	}
+
+	if err := req.access.canSeeTender(ctx, tender); err != nil {
+		return nil, err
+	}
+
+	return &tenderResolver{tender: tender}, nil
+}
+
+func (r *queryResolver) Tenders(ctx context.Context, args struct {
+	ServiceType *[]string
+	Limit       int32
+	Offset      int32
+}) ([]*tenderResolver, error) {
+	var serviceType []models.TenderServiceType
+	if args.ServiceType != nil {
+		for _, stype := range *args.ServiceType {
+			serviceType = append(serviceType, models.TenderServiceType(stype))
+		}
+	}
+
+	tenders, err := r.h.srv.GetAllTenders(ctx, serviceType, fromContext(ctx).username, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTenderResolvers(tenders), nil
+}
+
+func (r *queryResolver) MyTenders(ctx context.Context, args paginationArgs) ([]*tenderResolver, error) {
+	tenders, err := r.h.srv.GetUserTenders(ctx, fromContext(ctx).username, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTenderResolvers(tenders), nil
+}
+
+func (r *queryResolver) InvitedTenders(ctx context.Context, args paginationArgs) ([]*tenderResolver, error) {
+	tenders, err := r.h.srv.GetInvitedTenders(ctx, fromContext(ctx).username, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTenderResolvers(tenders), nil
+}
+
+func (r *queryResolver) Bid(ctx context.Context, args struct{ ID graphql.ID }) (*bidResolver, error) {
+	if !isUUID(string(args.ID)) {
+		return nil, nil
+	}
+
+	req := fromContext(ctx)
+
+	bid, err := req.loaders.bids.Load(ctx, string(args.ID))()
+	if err != nil || bid == nil {
+		return nil, err
+	}
+
+	if err := req.access.canSeeBid(ctx, bid); err != nil {
+		return nil, err
+	}
+
+	return &bidResolver{bid: bid}, nil
+}
+
+func (r *queryResolver) MyBids(ctx context.Context, args paginationArgs) ([]*bidResolver, error) {
+	bids, err := r.h.srv.GetBidsOfUser(ctx, fromContext(ctx).username, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBidResolvers(bids), nil
+}
+
+func (r *queryResolver) Organization(ctx context.Context, args struct{ ID graphql.ID }) (*organizationResolver, error) {
+	if !isUUID(string(args.ID)) {
+		return nil, nil
+	}
+
+	return loadOrganization(ctx, string(args.ID))
+}
+
+type tenderResolver struct {
+	tender *models.TenderResponse
+}
+
+func newTenderResolvers(tenders []*models.TenderResponse) []*tenderResolver {
+	resolvers := make([]*tenderResolver, 0, len(tenders))
+	for _, tender := range tenders {
+		resolvers = append(resolvers, &tenderResolver{tender: tender})
+	}
+
+	return resolvers
+}
+
+func (r *tenderResolver) ID() graphql.ID          { return graphql.ID(r.tender.ID) }
+func (r *tenderResolver) Name() string            { return r.tender.Name }
+func (r *tenderResolver) Description() string     { return r.tender.Description }
+func (r *tenderResolver) ServiceType() string     { return string(r.tender.ServiceType) }
+func (r *tenderResolver) Status() string          { return string(r.tender.Status) }
+func (r *tenderResolver) Version() int32          { return int32(r.tender.Version) }
+func (r *tenderResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.tender.CreatedAt} }
+
+func (r *tenderResolver) CancellationReason() *string {
+	return optional(r.tender.CancellationReason)
+}
+
+func (r *tenderResolver) SubmissionDeadline() *graphql.Time {
+	return optionalTime(r.tender.SubmissionDeadline)
+}
+
+func (r *tenderResolver) Sealed() bool { return r.tender.Sealed }
+
+func (r *tenderResolver) Visibility() string { return string(r.tender.Visibility) }
+
+func (r *tenderResolver) Prequalification() bool { return r.tender.Prequalification }
+
+func (r *tenderResolver) RevealedAt() *graphql.Time {
+	return optionalTime(r.tender.RevealedAt)
+}
+
+func (r *tenderResolver) Auction() *auctionResolver {
+	if r.tender.Auction == nil {
+		return nil
+	}
+
+	return &auctionResolver{auction: r.tender.Auction}
+}
+
+type auctionResolver struct {
+	auction *models.Auction
+}
+
+func (r *auctionResolver) Start() graphql.Time     { return graphql.Time{Time: r.auction.Start} }
+func (r *auctionResolver) Step() float64           { return r.auction.Step }
+func (r *auctionResolver) ExtensionSeconds() int32 { return r.auction.ExtensionSeconds }
+
+func (r *tenderResolver) Criteria(ctx context.Context) ([]*criterionResolver, error) {
+	criteria, err := fromContext(ctx).loaders.criteria.Load(ctx, r.tender.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*criterionResolver, 0, len(criteria))
+	for _, criterion := range criteria {
+		resolvers = append(resolvers, &criterionResolver{criterion: criterion})
+	}
+
+	return resolvers, nil
+}
+
+func (r *tenderResolver) Attachments(ctx context.Context) ([]*attachmentResolver, error) {
+	return loadAttachments(ctx, r.tender.AttachmentIDs)
+}
+
+type criterionResolver struct {
+	criterion *models.Criterion
+}
+
+func (r *criterionResolver) ID() graphql.ID { return graphql.ID(r.criterion.ID) }
+func (r *criterionResolver) Name() string   { return r.criterion.Name }
+func (r *criterionResolver) Weight() int32  { return r.criterion.Weight }
+
+func (r *tenderResolver) Organization(ctx context.Context) (*organizationResolver, error) {
+	return loadOrganization(ctx, string(r.tender.OrganizationID))
+}
+
+func (r *tenderResolver) Creator(ctx context.Context) (*employeeResolver, error) {
+	employee, err := fromContext(ctx).loaders.employeesByName.Load(ctx, r.tender.CreatorUsername)()
+	if err != nil || employee == nil {
+		return nil, err
+	}
+
+	return &employeeResolver{employee: employee}, nil
+}
+
+func (r *tenderResolver) Versions(ctx context.Context) ([]*tenderVersionResolver, error) {
+	req := fromContext(ctx)
+	if err := req.access.canManageTender(ctx, r.tender.ID); err != nil {
+		return nil, err
+	}
+
+	versions, err := req.loaders.tenderVersions.Load(ctx, r.tender.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*tenderVersionResolver, 0, len(versions))
+	for _, version := range versions {
+		resolvers = append(resolvers, &tenderVersionResolver{version: version})
+	}
+
+	return resolvers, nil
+}
+
+func (r *tenderResolver) Bids(ctx context.Context) ([]*bidResolver, error) {
+	req := fromContext(ctx)
+	if err := req.access.canManageTender(ctx, r.tender.ID); err != nil {
+		return nil, err
+	}
+
+	bids, err := req.loaders.bidsByTender.Load(ctx, r.tender.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]*models.BidResponse, 0, len(bids))
+	for _, bid := range bids {
+		if bid.Status != models.BidStatusCreated {
+			published = append(published, bid)
+		}
+	}
+
+	return newBidResolvers(published), nil
+}
+
+type tenderVersionResolver struct {
+	version *models.TenderResponse
+}
+
+func (r *tenderVersionResolver) Version() int32      { return int32(r.version.Version) }
+func (r *tenderVersionResolver) Name() string        { return r.version.Name }
+func (r *tenderVersionResolver) Description() string { return r.version.Description }
+func (r *tenderVersionResolver) ServiceType() string { return string(r.version.ServiceType) }
+func (r *tenderVersionResolver) Status() string      { return string(r.version.Status) }
+func (r *tenderVersionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.version.CreatedAt}
+}
+
+func (r *tenderVersionResolver) CancellationReason() *string {
+	return optional(r.version.CancellationReason)
+}
+
+func (r *tenderVersionResolver) Attachments(ctx context.Context) ([]*attachmentResolver, error) {
+	return loadAttachments(ctx, r.version.AttachmentIDs)
+}
+
+// optional maps an empty string to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// optionalTime maps a nil time to null.
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *t}
+}
+
+type bidResolver struct {
+	bid *models.BidResponse
+}
+
+func newBidResolvers(bids []*models.BidResponse) []*bidResolver {
+	resolvers := make([]*bidResolver, 0, len(bids))
+	for _, bid := range bids {
+		resolvers = append(resolvers, &bidResolver{bid: bid})
+	}
+
+	return resolvers
+}
+
+func (r *bidResolver) ID() graphql.ID          { return graphql.ID(r.bid.ID) }
+func (r *bidResolver) Name() string            { return r.bid.Name }
+func (r *bidResolver) Description() string     { return r.bid.Description }
+func (r *bidResolver) Status() string          { return string(r.bid.Status) }
+func (r *bidResolver) AuthorType() string      { return string(r.bid.AuthorType) }
+func (r *bidResolver) Version() int32          { return int32(r.bid.Version) }
+func (r *bidResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.bid.CreatedAt} }
+
+func (r *bidResolver) WithdrawalReason() *string {
+	return optional(r.bid.WithdrawalReason)
+}
+
+func (r *bidResolver) Sealed() bool { return r.bid.Sealed }
+
+func (r *bidResolver) Price() *float64 { return r.bid.Price }
+
+// Attachments stays empty while the bid is sealed, like its content.
+func (r *bidResolver) Attachments(ctx context.Context) ([]*attachmentResolver, error) {
+	if r.bid.Sealed {
+		return []*attachmentResolver{}, nil
+	}
+
+	if err := fromContext(ctx).access.canSeeBid(ctx, r.bid); err != nil {
+		return nil, err
+	}
+
+	return loadAttachments(ctx, r.bid.AttachmentIDs)
+}
+
+func (r *bidResolver) Tender(ctx context.Context) (*tenderResolver, error) {
+	req := fromContext(ctx)
+
+	tender, err := req.loaders.tenders.Load(ctx, r.bid.TenderID)()
+	if err != nil || tender == nil {
+		return nil, err
+	}
+
+	if err := req.access.canSeeTender(ctx, tender); err != nil {
+		return nil, err
+	}
+
+	return &tenderResolver{tender: tender}, nil
+}
+
+func (r *bidResolver) Author(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.bid.AuthorID)
+}
+
+func (r *bidResolver) Organization(ctx context.Context) (*organizationResolver, error) {
+	if r.bid.OrganizationID == nil {
+		return nil, nil
+	}
+
+	return loadOrganization(ctx, string(*r.bid.OrganizationID))
+}
+
+func (r *bidResolver) Versions(ctx context.Context) ([]*bidVersionResolver, error) {
+	req := fromContext(ctx)
+	if err := req.access.canSeeBid(ctx, r.bid); err != nil {
+		return nil, err
+	}
+
+	versions, err := req.loaders.bidVersions.Load(ctx, r.bid.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*bidVersionResolver, 0, len(versions))
+	for _, version := range versions {
+		resolvers = append(resolvers, &bidVersionResolver{version: version})
+	}
+
+	return resolvers, nil
+}
+
+func (r *bidResolver) Decisions(ctx context.Context) ([]*decisionResolver, error) {
+	req := fromContext(ctx)
+	if err := req.access.canManageTender(ctx, r.bid.TenderID); err != nil {
+		return nil, err
+	}
+
+	decisions, err := req.loaders.decisions.Load(ctx, r.bid.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*decisionResolver, 0, len(decisions))
+	for _, decision := range decisions {
+		resolvers = append(resolvers, &decisionResolver{decision: decision})
+	}
+
+	return resolvers, nil
+}
+
+func (r *bidResolver) Reviews(ctx context.Context) ([]*reviewResolver, error) {
+	req := fromContext(ctx)
+	if err := req.access.canSeeBid(ctx, r.bid); err != nil {
+		return nil, err
+	}
+
+	reviews, err := req.loaders.feedback.Load(ctx, r.bid.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*reviewResolver, 0, len(reviews))
+	for _, review := range reviews {
+		resolvers = append(resolvers, &reviewResolver{review: review})
+	}
+
+	return resolvers, nil
+}
+
+type bidVersionResolver struct {
+	version *models.BidResponse
+}
+
+func (r *bidVersionResolver) Version() int32          { return int32(r.version.Version) }
+func (r *bidVersionResolver) Name() string            { return r.version.Name }
+func (r *bidVersionResolver) Description() string     { return r.version.Description }
+func (r *bidVersionResolver) Status() string          { return string(r.version.Status) }
+func (r *bidVersionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.version.CreatedAt} }
+
+func (r *bidVersionResolver) WithdrawalReason() *string {
+	return optional(r.version.WithdrawalReason)
+}
+
+func (r *bidVersionResolver) Attachments(ctx context.Context) ([]*attachmentResolver, error) {
+	if r.version.Sealed {
+		return []*attachmentResolver{}, nil
+	}
+
+	return loadAttachments(ctx, r.version.AttachmentIDs)
+}
+
+type attachmentResolver struct {
+	attachment *models.Attachment
+}
+
+func loadAttachments(ctx context.Context, attachmentIDs []string) ([]*attachmentResolver, error) {
+	attachments, errs := fromContext(ctx).loaders.attachments.LoadMany(ctx, attachmentIDs)()
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	resolvers := make([]*attachmentResolver, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment != nil {
+			resolvers = append(resolvers, &attachmentResolver{attachment: attachment})
+		}
+	}
+
+	return resolvers, nil
+}
+
+func (r *attachmentResolver) ID() graphql.ID      { return graphql.ID(r.attachment.ID) }
+func (r *attachmentResolver) Name() string        { return r.attachment.Name }
+func (r *attachmentResolver) ContentType() string { return r.attachment.ContentType }
+func (r *attachmentResolver) Size() float64       { return float64(r.attachment.Size) }
+func (r *attachmentResolver) Sha256() string      { return r.attachment.SHA256 }
+func (r *attachmentResolver) ScanStatus() string  { return string(r.attachment.ScanStatus) }
+func (r *attachmentResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.attachment.CreatedAt}
+}
+
+type decisionResolver struct {
+	decision *models.BidDecisionResponse
+}
+
+func (r *decisionResolver) ID() graphql.ID   { return graphql.ID(r.decision.ID) }
+func (r *decisionResolver) Decision() string { return string(r.decision.Decision) }
+
+func (r *decisionResolver) InvalidatedAt() *graphql.Time {
+	return optionalTime(r.decision.InvalidatedAt)
+}
+
+func (r *decisionResolver) User(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.decision.UserID)
+}
+
+type reviewResolver struct {
+	review *models.BidReviewResponse
+}
+
+func (r *reviewResolver) ID() graphql.ID          { return graphql.ID(r.review.ID) }
+func (r *reviewResolver) Description() string     { return string(r.review.Description) }
+func (r *reviewResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.review.CreatedAt} }
+
+type employeeResolver struct {
+	employee *models.Employee
+}
+
+func loadEmployee(ctx context.Context, userID string) (*employeeResolver, error) {
+	employee, err := fromContext(ctx).loaders.employees.Load(ctx, userID)()
+	if err != nil || employee == nil {
+		return nil, err
+	}
+
+	return &employeeResolver{employee: employee}, nil
+}
+
+func (r *employeeResolver) ID() graphql.ID    { return graphql.ID(r.employee.ID) }
+func (r *employeeResolver) Username() string  { return r.employee.Username }
+func (r *employeeResolver) FirstName() string { return r.employee.FirstName }
+func (r *employeeResolver) LastName() string  { return r.employee.LastName }
+
+type organizationResolver struct {
+	organization *models.Organization
+}
+
+func loadOrganization(ctx context.Context, organizationID string) (*organizationResolver, error) {
+	organization, err := fromContext(ctx).loaders.organizations.Load(ctx, organizationID)()
+	if err != nil || organization == nil {
+		return nil, err
+	}
+
+	return &organizationResolver{organization: organization}, nil
+}
+
+func (r *organizationResolver) ID() graphql.ID      { return graphql.ID(r.organization.ID) }
+func (r *organizationResolver) Name() string        { return r.organization.Name }
+func (r *organizationResolver) Description() string { return r.organization.Description }
+func (r *organizationResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.organization.CreatedAt}
+}
+
+func (r *organizationResolver) Type() *string {
+	if r.organization.Type == "" {
+		return nil
+	}
+
+	t := string(r.organization.Type)
+	return &t
+}
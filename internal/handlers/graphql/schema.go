@@ -0,0 +1,185 @@
+package graphqlhandler
+
+const schema = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+enum TenderServiceType {
+	Construction
+	Delivery
+	Manufacture
+}
+
+enum TenderStatus {
+	Created
+	Published
+	Closed
+	Cancelled
+}
+
+enum TenderVisibility {
+	Public
+	Invitation
+	Internal
+}
+
+enum BidStatus {
+	Created
+	Published
+	Canceled
+	Approved
+	Rejected
+	TenderCancelled
+	Withdrawn
+}
+
+enum BidAuthorType {
+	Organization
+	User
+}
+
+enum BidDecision {
+	Approved
+	Rejected
+}
+
+enum ScanStatus {
+	Quarantined
+	Clean
+	Infected
+	Failed
+}
+
+enum OrganizationType {
+	IE
+	LLC
+	JSC
+}
+
+type Query {
+	tender(id: ID!): Tender
+	tenders(serviceType: [TenderServiceType!], limit: Int = 5, offset: Int = 0): [Tender!]!
+	myTenders(limit: Int = 5, offset: Int = 0): [Tender!]!
+	invitedTenders(limit: Int = 5, offset: Int = 0): [Tender!]!
+	bid(id: ID!): Bid
+	myBids(limit: Int = 5, offset: Int = 0): [Bid!]!
+	organization(id: ID!): Organization
+}
+
+type Tender {
+	id: ID!
+	name: String!
+	description: String!
+	serviceType: TenderServiceType!
+	status: TenderStatus!
+	cancellationReason: String
+	submissionDeadline: Time
+	sealed: Boolean!
+	revealedAt: Time
+	auction: Auction
+	visibility: TenderVisibility!
+	prequalification: Boolean!
+	criteria: [Criterion!]!
+	attachments: [Attachment!]!
+	version: Int!
+	createdAt: Time!
+	organization: Organization
+	creator: Employee
+	versions: [TenderVersion!]!
+	bids: [Bid!]!
+}
+
+type Auction {
+	start: Time!
+	step: Float!
+	extensionSeconds: Int!
+}
+
+type Criterion {
+	id: ID!
+	name: String!
+	weight: Int!
+}
+
+type TenderVersion {
+	version: Int!
+	name: String!
+	description: String!
+	serviceType: TenderServiceType!
+	status: TenderStatus!
+	cancellationReason: String
+	attachments: [Attachment!]!
+	createdAt: Time!
+}
+
+type Bid {
+	id: ID!
+	name: String!
+	description: String!
+	status: BidStatus!
+	withdrawalReason: String
+	sealed: Boolean!
+	price: Float
+	attachments: [Attachment!]!
+	authorType: BidAuthorType!
+	version: Int!
+	createdAt: Time!
+	tender: Tender
+	author: Employee
+	organization: Organization
+	versions: [BidVersion!]!
+	decisions: [Decision!]!
+	reviews: [Review!]!
+}
+
+type BidVersion {
+	version: Int!
+	name: String!
+	description: String!
+	status: BidStatus!
+	withdrawalReason: String
+	attachments: [Attachment!]!
+	createdAt: Time!
+}
+
+type Attachment {
+	id: ID!
+	name: String!
+	contentType: String!
+	size: Float!
+	sha256: String!
+	scanStatus: ScanStatus!
+	createdAt: Time!
+}
+
+type Decision {
+	id: ID!
+	decision: BidDecision!
+	invalidatedAt: Time
+	user: Employee
+}
+
+type Review {
+	id: ID!
+	description: String!
+	createdAt: Time!
+}
+
+type Employee {
+	id: ID!
+	username: String!
+	firstName: String!
+	lastName: String!
+}
+
+type Organization {
+	id: ID!
+	name: String!
+	description: String!
+	type: OrganizationType
+	createdAt: Time!
+}
+`
@@ -0,0 +1,222 @@
+package httphandler
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AttachToTender(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query uploadRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	upload, err := newUpload(c, query.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	attachment, err := h.srv.AttachToTender(c.Request.Context(), uri.ID, query.Username, upload, c.Request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *Handler) GetTenderAttachments(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	attachments, err := h.srv.GetTenderAttachments(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *Handler) DownloadTenderAttachment(c *gin.Context) {
+	var uri tenderAttachmentURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	attachment, content, err := h.srv.OpenTenderAttachment(c.Request.Context(), uri.ID, uri.AttachmentID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	sendAttachment(c, attachment, content)
+}
+
+func (h *Handler) DetachFromTender(c *gin.Context) {
+	var uri tenderAttachmentURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	tender, err := h.srv.DetachFromTender(c.Request.Context(), uri.ID, uri.AttachmentID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
+
+func (h *Handler) AttachToBid(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query uploadRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	upload, err := newUpload(c, query.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	attachment, err := h.srv.AttachToBid(c.Request.Context(), uri.ID, query.Username, upload, c.Request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *Handler) GetBidAttachments(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	attachments, err := h.srv.GetBidAttachments(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *Handler) DownloadBidAttachment(c *gin.Context) {
+	var uri bidAttachmentURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	attachment, content, err := h.srv.OpenBidAttachment(c.Request.Context(), uri.ID, uri.AttachmentID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	sendAttachment(c, attachment, content)
+}
+
+func (h *Handler) DetachFromBid(c *gin.Context) {
+	var uri bidAttachmentURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	bid, err := h.srv.DetachFromBid(c.Request.Context(), uri.ID, uri.AttachmentID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+// newUpload describes the raw request body as an upload. The body is
+// streamed, so its length has to be known up front to check the limits.
+func newUpload(c *gin.Context, name string) (*models.AttachmentUpload, error) {
+	if c.Request.ContentLength <= 0 {
+		return nil, errLengthRequired
+	}
+
+	return &models.AttachmentUpload{
+		Name:        name,
+		ContentType: c.ContentType(),
+		Size:        c.Request.ContentLength,
+	}, nil
+}
+
+func sendAttachment(c *gin.Context, attachment *models.Attachment, content io.Reader) {
+	c.Header("ETag", fmt.Sprintf("%q", attachment.SHA256))
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+	})
+}
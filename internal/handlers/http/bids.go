@@ -1,39 +1,22 @@
 package httphandler
 
 import (
-	"errors"
-	"fmt"
 	"net/http"
 
-	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
 	"github.com/gin-gonic/gin"
 )
 
 func (h *Handler) ConstructBid(c *gin.Context) {
 	var constructBid *models.BidCreate
-	if err := c.BindJSON(&constructBid); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("ошибка в теле запроса: %v", err)})
+	if err := c.ShouldBindJSON(&constructBid); err != nil {
+		c.Error(newBindError("body", err))
 		return
 	}
 
 	bid, err := h.srv.ConstructBid(c.Request.Context(), constructBid)
-	switch {
-	case errors.Is(err, repository.ErrBidUnique) || errors.Is(err, repository.ErrTenderClosed):
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidDependencyNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -43,65 +26,127 @@ func (h *Handler) ConstructBid(c *gin.Context) {
 
 func (h *Handler) RenewStatusOfBid(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query refreshBidStatusRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bid, err := h.srv.RenewStatusOfBid(c.Request.Context(), uri.ID, query.Username, &query.Status)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+
+func (h *Handler) WithdrawBid(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var withdraw models.BidWithdraw
+	if err := c.ShouldBindJSON(&withdraw); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	bid, err := h.srv.WithdrawBid(c.Request.Context(), uri.ID, query.Username, withdraw.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+func (h *Handler) SubmitBidPrice(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
-	case errors.Is(err, repository.ErrBidNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
+	}
+
+	var price models.BidPrice
+	if err := c.ShouldBindJSON(&price); err != nil {
+		c.Error(newBindError("body", err))
 		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	}
+
+	bid, err := h.srv.SubmitBidPrice(c.Request.Context(), uri.ID, query.Username, price.Price)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, bid)
 }
 
+func (h *Handler) ScoreBid(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var scores models.BidScores
+	if err := c.ShouldBindJSON(&scores); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	result, err := h.srv.ScoreBid(c.Request.Context(), uri.ID, query.Username, &scores)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
 
 func (h *Handler) GetBidsOfTender(c *gin.Context) {
 	var uri bidTenderIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query onesRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bids, err := h.srv.GetBidsOfTender(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidTenderNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -110,19 +155,14 @@ func (h *Handler) GetBidsOfTender(c *gin.Context) {
 
 func (h *Handler) GetOnesBids(c *gin.Context) {
 	var query onesRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bids, err := h.srv.GetBidsOfUser(c.Request.Context(), query.Username, query.Limit, query.Offset)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -131,31 +171,20 @@ func (h *Handler) GetOnesBids(c *gin.Context) {
 
 func (h *Handler) GetStatusOfBids(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	status, err := h.srv.GetStatusOfBids(c.Request.Context(), uri.ID, query.Username)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -166,31 +195,20 @@ func (h *Handler) GetStatusOfBids(c *gin.Context) {
 
 func (h *Handler) ApplyDecision(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query decisionRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bid, err := h.srv.ApplyBidDecision(c.Request.Context(), uri.ID, query.Username, &query.Decision)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidNotFound) || errors.Is(err, repository.ErrTenderNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -199,31 +217,20 @@ func (h *Handler) ApplyDecision(c *gin.Context) {
 
 func (h *Handler) GetCommentsOfBid(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query reviewsRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	feedbacks, err := h.srv.GetCommentsOfBid(c.Request.Context(), uri.ID, query.AuthorUsername, query.RequesterUsername, query.Limit, query.Offset)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidReviewsNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, feedbacks)
@@ -231,42 +238,31 @@ func (h *Handler) GetCommentsOfBid(c *gin.Context) {
 
 func (h *Handler) ChangeBid(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	var bidEdit *models.BidEdit
-	if err := c.BindJSON(&bidEdit); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("ошибка в теле запроса: %v", err)})
+	if err := c.ShouldBindJSON(&bidEdit); err != nil {
+		c.Error(newBindError("body", err))
 		return
 	}
 
 	if bidEdit.IsEmpty() {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{"нет изменений"})
+		c.Error(errNoChanges)
 		return
 	}
 
 	bid, err := h.srv.ChangeBid(c.Request.Context(), uri.ID, query.Username, bidEdit)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -275,30 +271,20 @@ func (h *Handler) ChangeBid(c *gin.Context) {
 
 func (h *Handler) ApplyFeedback(c *gin.Context) {
 	var uri bidIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
 	}
 
 	var query feedbackRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bid, err := h.srv.ApplyBidFeedback(c.Request.Context(), uri.ID, query.Username, &query.BidFeedback)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrBidNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -307,34 +293,95 @@ func (h *Handler) ApplyFeedback(c *gin.Context) {
 
 func (h *Handler) ReturnBidVersion(c *gin.Context) {
 	var uri cancelBidUri
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	bid, err := h.srv.CancelChangesOfBid(c.Request.Context(), uri.ID, query.Username, uri.Version)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+
+func (h *Handler) PostBidMessage(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
-	case errors.Is(err, repository.ErrBidORVersionNotFound) || errors.Is(err, repository.ErrBidNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	}
+
+	var message models.BidMessageCreate
+	if err := c.ShouldBindJSON(&message); err != nil {
+		c.Error(newBindError("body", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, bid)
+	result, err := h.srv.PostBidMessage(c.Request.Context(), uri.ID, query.Username, &message)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) GetBidMessages(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	messages, err := h.srv.GetBidMessages(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
 }
 
+func (h *Handler) MarkBidMessagesRead(c *gin.Context) {
+	var uri bidIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	read, err := h.srv.MarkBidMessagesRead(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, read)
+}
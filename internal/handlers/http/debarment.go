@@ -0,0 +1,167 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) DebarSupplier(c *gin.Context) {
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var create models.DebarmentCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	debarment, err := h.srv.DebarSupplier(c.Request.Context(), query.Username, &create)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarment)
+}
+
+func (h *Handler) GetDebarments(c *gin.Context) {
+	var query debarmentsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	debarments, err := h.srv.GetDebarments(c.Request.Context(), query.Username, query.OrganizationID, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarments)
+}
+
+func (h *Handler) GetMyDebarments(c *gin.Context) {
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	debarments, err := h.srv.GetMyDebarments(c.Request.Context(), query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarments)
+}
+
+func (h *Handler) GetDebarment(c *gin.Context) {
+	var uri debarmentIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	debarment, err := h.srv.GetDebarment(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarment)
+}
+
+func (h *Handler) ChangeDebarment(c *gin.Context) {
+	var uri debarmentIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var edit models.DebarmentEdit
+	if err := c.ShouldBindJSON(&edit); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	if edit.Reason == nil && edit.ValidUntil == nil {
+		c.Error(errNoChanges)
+		return
+	}
+
+	debarment, err := h.srv.ChangeDebarment(c.Request.Context(), uri.ID, query.Username, &edit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarment)
+}
+
+func (h *Handler) LiftDebarment(c *gin.Context) {
+	var uri debarmentIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var lift models.DebarmentLift
+	if err := c.ShouldBindJSON(&lift); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	debarment, err := h.srv.LiftDebarment(c.Request.Context(), uri.ID, query.Username, lift.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, debarment)
+}
+
+func (h *Handler) GetDebarmentAudit(c *gin.Context) {
+	var uri debarmentIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	records, err := h.srv.GetDebarmentAudit(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
@@ -1,42 +1,98 @@
 package httphandler
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/health"
+	"github.com/DarRo9/Tenders/internal/metrics"
+	"github.com/DarRo9/Tenders/internal/tracing"
 	service "github.com/DarRo9/Tenders/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Service interface {
 	service.TenderService
 	service.BidService
+	service.PrequalificationService
+	service.DebarmentService
+	service.NotificationService
 }
 
 type Handler struct {
-	srv Service
-	log *logrus.Logger
+	srv             Service
+	graphql         http.Handler
+	metrics         *metrics.Metrics
+	probe           *health.Probe
+	transferTimeout time.Duration
+	log             *logrus.Logger
 }
 
-func New(srv Service, log *logrus.Logger) *Handler {
-	return &Handler{srv: srv, log: log}
+func New(srv Service, graphql http.Handler, metrics *metrics.Metrics, probe *health.Probe, transferTimeout time.Duration, log *logrus.Logger) *Handler {
+	return &Handler{srv: srv, graphql: graphql, metrics: metrics, probe: probe, transferTimeout: transferTimeout, log: log}
 }
 
 func (h *Handler) CreateRoutes() *gin.Engine {
-	r := gin.Default()
+	registerFieldNames()
+
+	r := gin.New()
+	r.Use(
+		otelgin.Middleware(tracing.ServiceName),
+		h.requestContext,
+		h.accessLog,
+	)
+
+	// Metrics and GraphQL are optional features, a nil dependency turns them off.
+	if h.metrics != nil {
+		r.Use(h.metrics.Middleware)
+		r.GET("/metrics", gin.WrapH(h.metrics.Handler()))
+	}
+
+	r.Use(gin.Recovery(), h.renderErrors)
 
 	api := r.Group("/api")
 	{
 		api.GET("/ping", h.PingStatus) 
+		if h.graphql != nil {
+			api.POST("/graphql", gin.WrapH(h.graphql))
+		}
+
+		health := api.Group("/health")
+		{
+			health.GET("/live", h.Liveness)
+			health.GET("/ready", h.Readiness)
+		}
 
 		tenders := api.Group("/tenders")
 		{
 			tenders.GET("", h.GetAllTenders)        
 			tenders.POST("/new", h.BuildTender) 
 			tenders.GET("/my", h.GetOnesTenders)   
+			tenders.GET("/invited", h.GetInvitedTenders)
 
 			tenders.GET("/:tenderId/status", h.GetStatusOfTender)               
 			tenders.PUT("/:tenderId/status", h.RefreshTenderStatus)             
 			tenders.PATCH("/:tenderId/edit", h.ChangeTender)                       
 			tenders.PUT("/:tenderId/rollback/:version", h.RefreshTenderVersion) 
+			tenders.PUT("/:tenderId/cancel", h.CancelTender)
+			tenders.PUT("/:tenderId/reveal", h.RevealTender)
+			tenders.GET("/:tenderId/audit", h.GetTenderAudit)
+			tenders.GET("/:tenderId/ranking", h.GetAuctionRanking)
+			tenders.PUT("/:tenderId/criteria", h.SetTenderCriteria)
+			tenders.GET("/:tenderId/criteria", h.GetTenderCriteria)
+			tenders.GET("/:tenderId/evaluation", h.GetTenderEvaluation)
+			tenders.PUT("/:tenderId/award", h.AwardTender)
+			tenders.POST("/:tenderId/questions", h.AskQuestion)
+			tenders.GET("/:tenderId/questions", h.GetTenderQuestions)
+			tenders.PUT("/:tenderId/questions/:questionId/answer", h.AnswerQuestion)
+			tenders.POST("/:tenderId/attachments", h.transferDeadline, h.AttachToTender)
+			tenders.GET("/:tenderId/attachments", h.GetTenderAttachments)
+			tenders.GET("/:tenderId/attachments/:attachmentId", h.transferDeadline, h.DownloadTenderAttachment)
+			tenders.DELETE("/:tenderId/attachments/:attachmentId", h.DetachFromTender)
+			tenders.PUT("/:tenderId/invitations", h.InviteOrganizations)
+			tenders.GET("/:tenderId/invitations", h.GetTenderInvitations)
 		}
 
 		bids := api.Group("/bids")
@@ -52,6 +108,16 @@ func (h *Handler) CreateRoutes() *gin.Engine {
 				bids.PUT("/:id/submit_decision", h.ApplyDecision)     
 				bids.PUT("/:id/feedback", h.ApplyFeedback)             
 				bids.PUT("/:id/rollback/:version", h.ReturnBidVersion) 
+				bids.PUT("/:id/withdraw", h.WithdrawBid)
+				bids.PUT("/:id/price", h.SubmitBidPrice)
+				bids.PUT("/:id/scores", h.ScoreBid)
+				bids.POST("/:id/messages", h.PostBidMessage)
+				bids.GET("/:id/messages", h.GetBidMessages)
+				bids.PUT("/:id/messages/read", h.MarkBidMessagesRead)
+				bids.POST("/:id/attachments", h.transferDeadline, h.AttachToBid)
+				bids.GET("/:id/attachments", h.GetBidAttachments)
+				bids.GET("/:id/attachments/:attachmentId", h.transferDeadline, h.DownloadBidAttachment)
+				bids.DELETE("/:id/attachments/:attachmentId", h.DetachFromBid)
 			}
 
 			
@@ -60,6 +126,33 @@ func (h *Handler) CreateRoutes() *gin.Engine {
 				bids.GET("/:id/reviews", h.GetCommentsOfBid) 
 			}
 		}
+
+		prequalification := api.Group("/prequalification")
+		{
+			prequalification.PUT("/questionnaires", h.SetQuestionnaire)
+			prequalification.GET("/questionnaires/:questionnaireId", h.GetQuestionnaire)
+			prequalification.POST("/questionnaires/:questionnaireId/applications", h.SubmitApplication)
+			prequalification.GET("/questionnaires/:questionnaireId/applications", h.GetApplicationsOfQuestionnaire)
+			prequalification.GET("/applications/my", h.GetMyApplications)
+			prequalification.GET("/applications/:applicationId", h.GetApplication)
+			prequalification.PUT("/applications/:applicationId/review", h.ReviewApplication)
+			prequalification.PUT("/applications/:applicationId/revoke", h.RevokeApplication)
+			prequalification.POST("/applications/:applicationId/licenses", h.AttachLicense)
+			prequalification.GET("/applications/:applicationId/licenses/:attachmentId", h.DownloadLicense)
+		}
+
+		debarments := api.Group("/debarments")
+		{
+			debarments.POST("/new", h.DebarSupplier)
+			debarments.GET("", h.GetDebarments)
+			debarments.GET("/my", h.GetMyDebarments)
+			debarments.GET("/:debarmentId", h.GetDebarment)
+			debarments.PATCH("/:debarmentId/edit", h.ChangeDebarment)
+			debarments.PUT("/:debarmentId/lift", h.LiftDebarment)
+			debarments.GET("/:debarmentId/audit", h.GetDebarmentAudit)
+		}
+
+		api.GET("/notifications", h.GetNotifications)
 	}
 
 	return r
@@ -9,3 +9,19 @@ import (
 func(h *Handler) PingStatus(c *gin.Context){
 	c.String(http.StatusOK, "ok")
 }
+
+// Liveness only tells that the process is able to serve HTTP.
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness tells whether the instance should receive traffic.
+func (h *Handler) Readiness(c *gin.Context) {
+	report := h.probe.Ready(c.Request.Context())
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
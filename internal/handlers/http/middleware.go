@@ -0,0 +1,88 @@
+package httphandler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "requestId"
+)
+
+// requestContext assigns the request an ID, echoing the one sent by the
+// client if present, and puts a logger with the request ID, user and route
+// into the request context for the layers below.
+func (h *Handler) requestContext(c *gin.Context) {
+	requestID := c.GetHeader(requestIDHeader)
+	if requestID == "" || len(requestID) > 64 {
+		requestID = newRequestID()
+	}
+
+	c.Set(requestIDKey, requestID)
+	c.Header(requestIDHeader, requestID)
+
+	fields := logrus.Fields{
+		"request_id": requestID,
+		"method":     c.Request.Method,
+		"route":      c.FullPath(),
+	}
+	if username := c.Query("username"); username != "" {
+		fields["user"] = username
+	}
+	if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+		fields["trace_id"] = span.TraceID().String()
+	}
+
+	entry := h.log.WithFields(fields)
+	c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), entry))
+
+	c.Next()
+}
+
+// accessLog writes one entry per request once it has been handled.
+func (h *Handler) accessLog(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	logger.FromContext(c.Request.Context(), h.log).WithFields(logrus.Fields{
+		"status":     c.Writer.Status(),
+		"latency_ms": time.Since(start).Milliseconds(),
+		"size":       c.Writer.Size(),
+	}).Info("request handled")
+}
+
+func requestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// transferDeadline gives an attachment upload or download the transfer
+// timeout in place of the read and write timeouts of the server, which are
+// too short to move a large file.
+func (h *Handler) transferDeadline(c *gin.Context) {
+	deadline := time.Now().Add(h.transferTimeout)
+
+	controller := http.NewResponseController(c.Writer)
+	for _, err := range []error{controller.SetReadDeadline(deadline), controller.SetWriteDeadline(deadline)} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logger.FromContext(c.Request.Context(), h.log).WithError(err).Warn("failed to extend the transfer deadline")
+		}
+	}
+
+	c.Next()
+}
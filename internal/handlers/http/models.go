@@ -6,10 +6,6 @@ type UsernameRequest struct {
 	Username string `form:"username" binding:"required,max=50"`
 }
 
-type errorResponse struct {
-	Reason string `json:"reason"`
-}
-
 type PaginationRequest struct {
 	Limit  int32 `form:"limit,default=5" binding:"omitempty,min=1"`
 	Offset int32 `form:"offset,default=0" binding:"omitempty,min=0"`
@@ -29,10 +25,17 @@ type tenderIdURI struct {
 	ID string `uri:"tenderId" binding:"required,uuid"`
 }
 
+type questionURI struct {
+	ID         string `uri:"tenderId" binding:"required,uuid"`
+	QuestionID string `uri:"questionId" binding:"required,uuid"`
+}
+
 type allTenderRequests struct {
 	Limit       int32                      `form:"limit,default=5" binding:"omitempty,min=1"`
 	Offset      int32                      `form:"offset,default=0" binding:"omitempty,min=0"`
 	ServiceType []models.TenderServiceType `form:"service_type" binding:"omitempty,dive,oneof=Construction Delivery Manufacture"`
+	// Username is optional: anonymous users see the public tenders only.
+	Username string `form:"username" binding:"omitempty,max=50"`
 }
 
 type updateTenderStatusRequests struct {
@@ -54,7 +57,7 @@ type feedbackRequest struct {
 }
 
 type refreshBidStatusRequest struct {
-	Status   models.BidStatus `form:"status" binding:"required,oneof=Created Published Closed"`
+	Status   models.BidStatus `form:"status" binding:"required,oneof=Created Published"`
 	Username string           `form:"username" binding:"required,max=50"`
 }
 
@@ -75,3 +78,40 @@ type decisionRequest struct {
 }
 
 
+
+type tenderAttachmentURI struct {
+	ID           string `uri:"tenderId" binding:"required,uuid"`
+	AttachmentID string `uri:"attachmentId" binding:"required,uuid"`
+}
+
+type bidAttachmentURI struct {
+	ID           string `uri:"id" binding:"required,uuid"`
+	AttachmentID string `uri:"attachmentId" binding:"required,uuid"`
+}
+
+type uploadRequest struct {
+	Name string `form:"name" binding:"required,max=255"`
+	UsernameRequest
+}
+
+type questionnaireIdURI struct {
+	ID string `uri:"questionnaireId" binding:"required,uuid"`
+}
+
+type applicationIdURI struct {
+	ID string `uri:"applicationId" binding:"required,uuid"`
+}
+
+type licenseURI struct {
+	ID           string `uri:"applicationId" binding:"required,uuid"`
+	AttachmentID string `uri:"attachmentId" binding:"required,uuid"`
+}
+
+type debarmentIdURI struct {
+	ID string `uri:"debarmentId" binding:"required,uuid"`
+}
+
+type debarmentsRequest struct {
+	onesRequest
+	OrganizationID *models.OrganizationID `form:"organizationId" binding:"omitempty,uuid"`
+}
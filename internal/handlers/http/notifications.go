@@ -0,0 +1,23 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetNotifications(c *gin.Context) {
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	notifications, err := h.srv.GetNotifications(c.Request.Context(), query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
@@ -0,0 +1,247 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) SetQuestionnaire(c *gin.Context) {
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var set models.QuestionnaireSet
+	if err := c.ShouldBindJSON(&set); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	questionnaire, err := h.srv.SetQuestionnaire(c.Request.Context(), query.Username, &set)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questionnaire)
+}
+
+func (h *Handler) GetQuestionnaire(c *gin.Context) {
+	var uri questionnaireIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	questionnaire, err := h.srv.GetQuestionnaire(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questionnaire)
+}
+
+func (h *Handler) SubmitApplication(c *gin.Context) {
+	var uri questionnaireIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var create models.ApplicationCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	application, err := h.srv.SubmitApplication(c.Request.Context(), uri.ID, query.Username, &create)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func (h *Handler) GetApplicationsOfQuestionnaire(c *gin.Context) {
+	var uri questionnaireIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	applications, err := h.srv.GetApplicationsOfQuestionnaire(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, applications)
+}
+
+func (h *Handler) GetMyApplications(c *gin.Context) {
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	applications, err := h.srv.GetMyApplications(c.Request.Context(), query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, applications)
+}
+
+func (h *Handler) GetApplication(c *gin.Context) {
+	var uri applicationIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	application, err := h.srv.GetApplication(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func (h *Handler) ReviewApplication(c *gin.Context) {
+	var uri applicationIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var review models.ApplicationReview
+	if err := c.ShouldBindJSON(&review); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	application, err := h.srv.ReviewApplication(c.Request.Context(), uri.ID, query.Username, &review)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func (h *Handler) RevokeApplication(c *gin.Context) {
+	var uri applicationIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var revocation models.ApplicationRevocation
+	if err := c.ShouldBindJSON(&revocation); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	application, err := h.srv.RevokeApplication(c.Request.Context(), uri.ID, query.Username, revocation.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, application)
+}
+
+func (h *Handler) AttachLicense(c *gin.Context) {
+	var uri applicationIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query uploadRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	upload, err := newUpload(c, query.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	attachment, err := h.srv.AttachLicense(c.Request.Context(), uri.ID, query.Username, upload, c.Request.Body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *Handler) DownloadLicense(c *gin.Context) {
+	var uri licenseURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	attachment, content, err := h.srv.OpenLicense(c.Request.Context(), uri.ID, uri.AttachmentID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	sendAttachment(c, attachment, content)
+}
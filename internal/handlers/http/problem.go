@@ -0,0 +1,220 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/DarRo9/Tenders/internal/i18n"
+	"github.com/DarRo9/Tenders/internal/logger"
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+const problemContentType = "application/problem+json"
+
+const (
+	codeValidationFailed = "validation_failed"
+	codeInternal         = "internal_error"
+)
+
+var (
+	errNoChanges      = repository.NewError(repository.KindValidation, "no_changes", "the request contains no changes")
+	errLengthRequired = repository.NewError(repository.KindValidation, "length_required", "the upload needs a non-empty body with a Content-Length")
+)
+
+var kindStatuses = map[repository.Kind]int{
+	repository.KindValidation:           http.StatusBadRequest,
+	repository.KindUnauthorized:         http.StatusUnauthorized,
+	repository.KindForbidden:            http.StatusForbidden,
+	repository.KindNotFound:             http.StatusNotFound,
+	repository.KindConflict:             http.StatusConflict,
+	repository.KindTooLarge:             http.StatusRequestEntityTooLarge,
+	repository.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+}
+
+// problem is an RFC 7807 problem details object extended with a stable code,
+// the field-level violations of a validation failure and the correlation ID
+// of a masked internal error.
+type problem struct {
+	Type          string      `json:"type"`
+	Title         string      `json:"title"`
+	Status        int         `json:"status"`
+	Detail        string      `json:"detail,omitempty"`
+	Instance      string      `json:"instance,omitempty"`
+	Code          string      `json:"code"`
+	Violations    []violation `json:"violations,omitempty"`
+	CorrelationID string      `json:"correlationId,omitempty"`
+	// Debarment tells a debarred supplier why it cannot bid.
+	Debarment *models.Debarment `json:"debarment,omitempty"`
+}
+
+type violation struct {
+	Field   string `json:"field"`
+	In      string `json:"in"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// bindError is a failure to bind the request part in (query, uri or body).
+type bindError struct {
+	in  string
+	err error
+}
+
+func newBindError(in string, err error) *bindError {
+	return &bindError{in: in, err: err}
+}
+
+func (e *bindError) Error() string {
+	return e.in + ": " + e.err.Error()
+}
+
+func (e *bindError) Unwrap() error {
+	return e.err
+}
+
+// renderErrors is the single place where errors attached to the context by
+// handlers are turned into responses.
+func (h *Handler) renderErrors(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	lang := i18n.FromRequest(c.Request)
+
+	var bindErr *bindError
+	if errors.As(err, &bindErr) {
+		h.writeValidationProblem(c, lang, bindErr)
+		return
+	}
+
+	var debarredErr *repository.DebarredError
+	if errors.As(err, &debarredErr) {
+		p := newProblem(c, lang, http.StatusForbidden, repository.ErrSupplierDebarred.Code,
+			i18n.Message(lang, "supplier_debarred.reason", debarredErr.Debarment.Reason))
+		p.Debarment = debarredErr.Debarment
+		writeProblem(c, lang, p)
+		return
+	}
+
+	var domainErr *repository.Error
+	if errors.As(err, &domainErr) {
+		if status, ok := kindStatuses[domainErr.Kind]; ok {
+			detail := domainErr.Message
+			if i18n.Has(domainErr.Code) {
+				detail = i18n.Message(lang, domainErr.Code)
+			}
+
+			writeProblem(c, lang, newProblem(c, lang, status, domainErr.Code, detail))
+			return
+		}
+	}
+
+	h.writeInternalProblem(c, lang, err)
+}
+
+func newProblem(c *gin.Context, lang language.Tag, status int, code, detail string) *problem {
+	return &problem{
+		Type:     "urn:tenders:problem:" + code,
+		Title:    i18n.Message(lang, "status."+strconv.Itoa(status)),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	}
+}
+
+func writeProblem(c *gin.Context, lang language.Tag, p *problem) {
+	c.Header("Content-Type", problemContentType)
+	c.Header("Content-Language", lang.String())
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+func (h *Handler) writeValidationProblem(c *gin.Context, lang language.Tag, bindErr *bindError) {
+	p := newProblem(c, lang, http.StatusBadRequest, codeValidationFailed, i18n.Message(lang, "invalid."+bindErr.in))
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(bindErr.err, &validationErrs) {
+		p.Detail = i18n.Message(lang, "malformed."+bindErr.in)
+		writeProblem(c, lang, p)
+		return
+	}
+
+	for _, fieldErr := range validationErrs {
+		p.Violations = append(p.Violations, violation{
+			Field:   fieldErr.Field(),
+			In:      bindErr.in,
+			Rule:    fieldErr.Tag(),
+			Message: violationMessage(lang, fieldErr),
+		})
+	}
+
+	writeProblem(c, lang, p)
+}
+
+// violationMessage turns a failed binding tag into a readable sentence.
+func violationMessage(lang language.Tag, fieldErr validator.FieldError) string {
+	field := fieldErr.Field()
+
+	switch tag := fieldErr.Tag(); tag {
+	case "required", "uuid":
+		return i18n.Message(lang, "rule."+tag, field)
+	case "required_if":
+		return i18n.Message(lang, "rule.required", field)
+	case "excluded_if":
+		return i18n.Message(lang, "rule.excluded", field)
+	case "gt":
+		return i18n.Message(lang, "rule.gt", field, fieldErr.Param())
+	case "oneof":
+		return i18n.Message(lang, "rule.oneof", field, strings.Join(strings.Fields(fieldErr.Param()), ", "))
+	case "min", "max":
+		key := "rule." + tag
+		if fieldErr.Kind() == reflect.String {
+			key += ".string"
+		}
+		return i18n.Message(lang, key, field, fieldErr.Param())
+	default:
+		return i18n.Message(lang, "rule.invalid", field)
+	}
+}
+
+// writeInternalProblem hides the cause of an unexpected error from the
+// client and logs it under the request ID, which is returned as the
+// correlation ID instead.
+func (h *Handler) writeInternalProblem(c *gin.Context, lang language.Tag, err error) {
+	logger.FromContext(c.Request.Context(), h.log).Error(err)
+
+	p := newProblem(c, lang, http.StatusInternalServerError, codeInternal, i18n.Message(lang, codeInternal))
+	p.CorrelationID = requestID(c)
+	writeProblem(c, lang, p)
+}
+
+// registerFieldNames makes validation errors refer to fields by the names
+// clients send (form, uri or json tag) instead of Go struct field names.
+func registerFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+
+		return field.Name
+	})
+}
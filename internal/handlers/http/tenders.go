@@ -1,12 +1,8 @@
 package httphandler
 
 import (
-	"errors"
-	"fmt"
-	"log"
 	"net/http"
 
-	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
 	"github.com/gin-gonic/gin"
 )
@@ -14,26 +10,14 @@ import (
 
 func (h *Handler) BuildTender(c *gin.Context) {
 	var createTender *models.TenderCreate
-	if err := c.BindJSON(&createTender); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("ошибка в теле запроса: %v", err)})
+	if err := c.ShouldBindJSON(&createTender); err != nil {
+		c.Error(newBindError("body", err))
 		return
 	}
 
 	tender, err := h.srv.BuildTender(c.Request.Context(), createTender)
-	switch {
-	case errors.Is(err, repository.ErrOrganizationDepencyNotFound):
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -42,15 +26,14 @@ func (h *Handler) BuildTender(c *gin.Context) {
 
 func (h *Handler) GetAllTenders(c *gin.Context) {
 	var query allTenderRequests
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
-	tenders, err := h.srv.GetAllTenders(c.Request.Context(), query.ServiceType, query.Limit, query.Offset)
+	tenders, err := h.srv.GetAllTenders(c.Request.Context(), query.ServiceType, query.Username, query.Limit, query.Offset)
 	if err != nil {
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -59,31 +42,20 @@ func (h *Handler) GetAllTenders(c *gin.Context) {
 
 func (h *Handler) RefreshTenderStatus(c *gin.Context) {
 	var uri tenderIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query updateTenderStatusRequests
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	tender, err := h.srv.RefreshTenderStatus(c.Request.Context(), uri.ID, query.Username, query.Status)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrTenderNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -92,21 +64,14 @@ func (h *Handler) RefreshTenderStatus(c *gin.Context) {
 
 func (h *Handler) GetOnesTenders(c *gin.Context) {
 	var query onesRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
-	log.Println(query)
-
 	tenders, err := h.srv.GetUserTenders(c.Request.Context(), query.Username, query.Limit, query.Offset)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -115,31 +80,20 @@ func (h *Handler) GetOnesTenders(c *gin.Context) {
 
 func (h *Handler) GetStatusOfTender(c *gin.Context) {
 	var uri tenderIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	status, err := h.srv.GetStatusOfTender(c.Request.Context(), uri.ID, query.Username)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrTenderNotFound) || errors.Is(err, repository.ErrTenderORVersionNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -149,31 +103,20 @@ func (h *Handler) GetStatusOfTender(c *gin.Context) {
 
 func (h *Handler) RefreshTenderVersion(c *gin.Context) {
 	var uri cancelTenderUri
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	tender, err := h.srv.RollbackTender(c.Request.Context(), uri.ID, uri.Version, query.Username)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
-		return
-	case errors.Is(err, repository.ErrTenderORVersionNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
-		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -182,44 +125,365 @@ func (h *Handler) RefreshTenderVersion(c *gin.Context) {
 
 func (h *Handler) ChangeTender(c *gin.Context) {
 	var uri tenderIdURI
-	if err := c.BindUri(&uri); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный uri: %v", err)})
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
 	}
 
 	var query UsernameRequest
-	if err := c.BindQuery(&query); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("неккоректный query: %v", err)})
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
 	}
 
 	var tenderEdit *models.TenderEdit
-	if err := c.BindJSON(&tenderEdit); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{fmt.Sprintf("ошибка в теле запроса: %v", err)})
+	if err := c.ShouldBindJSON(&tenderEdit); err != nil {
+		c.Error(newBindError("body", err))
 		return
 	}
 
 	if tenderEdit.IsEmpty() {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse{"изменений нет"})
+		c.Error(errNoChanges)
 		return
 	}
 
 	tender, err := h.srv.ChangeTender(c.Request.Context(), uri.ID, query.Username, tenderEdit)
-	switch {
-	case errors.Is(err, repository.ErrUserNotExist):
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{err.Error()})
+	if err != nil {
+		c.Error(err)
 		return
-	case errors.Is(err, repository.ErrRelationNotExist):
-		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{err.Error()})
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
+
+func (h *Handler) CancelTender(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
 		return
-	case errors.Is(err, repository.ErrTenderNotFound):
-		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse{err.Error()})
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
 		return
-	case err != nil:
-		h.log.Error(err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse{err.Error()})
+	}
+
+	var cancel models.TenderCancel
+	if err := c.ShouldBindJSON(&cancel); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	tender, err := h.srv.CancelTender(c.Request.Context(), uri.ID, query.Username, cancel.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
+
+func (h *Handler) RevealTender(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	tender, err := h.srv.RevealTender(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, tender)
 }
+
+func (h *Handler) GetTenderAudit(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	records, err := h.srv.GetTenderAudit(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+func (h *Handler) GetAuctionRanking(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	ranking, err := h.srv.GetAuctionRanking(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ranking)
+}
+
+func (h *Handler) SetTenderCriteria(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var criteria models.TenderCriteria
+	if err := c.ShouldBindJSON(&criteria); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	result, err := h.srv.SetTenderCriteria(c.Request.Context(), uri.ID, query.Username, &criteria)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) GetTenderCriteria(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	criteria, err := h.srv.GetTenderCriteria(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, criteria)
+}
+
+func (h *Handler) GetTenderEvaluation(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	evaluation, err := h.srv.GetTenderEvaluation(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
+
+func (h *Handler) AwardTender(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	bid, err := h.srv.AwardTender(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
+
+func (h *Handler) AskQuestion(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var question models.QuestionCreate
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	result, err := h.srv.AskQuestion(c.Request.Context(), uri.ID, query.Username, &question)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) GetTenderQuestions(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	questions, err := h.srv.GetTenderQuestions(c.Request.Context(), uri.ID, query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
+
+func (h *Handler) AnswerQuestion(c *gin.Context) {
+	var uri questionURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var answer models.QuestionAnswer
+	if err := c.ShouldBindJSON(&answer); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	question, err := h.srv.AnswerQuestion(c.Request.Context(), uri.ID, uri.QuestionID, query.Username, &answer)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (h *Handler) GetInvitedTenders(c *gin.Context) {
+	var query onesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	tenders, err := h.srv.GetInvitedTenders(c.Request.Context(), query.Username, query.Limit, query.Offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenders)
+}
+
+func (h *Handler) InviteOrganizations(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	var invitation models.InvitationCreate
+	if err := c.ShouldBindJSON(&invitation); err != nil {
+		c.Error(newBindError("body", err))
+		return
+	}
+
+	invitations, err := h.srv.InviteOrganizations(c.Request.Context(), uri.ID, query.Username, &invitation)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *Handler) GetTenderInvitations(c *gin.Context) {
+	var uri tenderIdURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.Error(newBindError("uri", err))
+		return
+	}
+
+	var query UsernameRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindError("query", err))
+		return
+	}
+
+	invitations, err := h.srv.GetTenderInvitations(c.Request.Context(), uri.ID, query.Username)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
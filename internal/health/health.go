@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+var ErrShuttingDown = errors.New("the server is shutting down")
+
+// Check reports whether a dependency of the service is usable.
+type Check func(ctx context.Context) error
+
+// Probe aggregates the readiness checks of the service.
+type Probe struct {
+	shuttingDown atomic.Bool
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+type Report struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+func NewProbe() *Probe {
+	return &Probe{checks: make(map[string]Check)}
+}
+
+func (p *Probe) Register(name string, check Check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.checks[name] = check
+}
+
+// SetShuttingDown makes every following readiness check fail, so that load
+// balancers stop routing traffic while in-flight requests drain.
+func (p *Probe) SetShuttingDown() {
+	p.shuttingDown.Store(true)
+}
+
+// Ready runs all registered checks concurrently.
+func (p *Probe) Ready(ctx context.Context) Report {
+	report := Report{Ready: true, Checks: make(map[string]string)}
+	if p.shuttingDown.Load() {
+		report.Ready = false
+		report.Checks["server"] = ErrShuttingDown.Error()
+		return report
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range p.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			err := check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = "ok"
+			if err != nil {
+				report.Checks[name] = err.Error()
+				report.Ready = false
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat tracks a background worker. The worker calls Beat after each
+// iteration; the check fails when the last beat is older than maxAge.
+type Heartbeat struct {
+	maxAge time.Duration
+	last   atomic.Int64
+}
+
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge}
+}
+
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+func (h *Heartbeat) Check(context.Context) error {
+	last := h.last.Load()
+	if last == 0 {
+		return fmt.Errorf("worker has not started")
+	}
+
+	if age := time.Since(time.Unix(0, last)); age > h.maxAge {
+		return fmt.Errorf("worker is stalled for %s", age.Round(time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,322 @@
+package i18n
+
+// catalog holds every message the API returns to clients. Domain errors are
+// keyed by their stable code, validation rules by "rule." plus the tag name.
+var catalog = map[string]translations{
+	// Titles of the problem responses, keyed by HTTP status.
+	"status.400": {en: "Bad Request", ru: "Некорректный запрос"},
+	"status.401": {en: "Unauthorized", ru: "Пользователь не авторизован"},
+	"status.403": {en: "Forbidden", ru: "Недостаточно прав"},
+	"status.404": {en: "Not Found", ru: "Не найдено"},
+	"status.409": {en: "Conflict", ru: "Конфликт"},
+	"status.413": {en: "Content Too Large", ru: "Слишком большой объём данных"},
+	"status.415": {en: "Unsupported Media Type", ru: "Неподдерживаемый тип данных"},
+	"status.500": {en: "Internal Server Error", ru: "Внутренняя ошибка сервера"},
+
+	// Errors produced by the handlers.
+	"validation_failed": {en: "the request is invalid", ru: "запрос некорректен"},
+	"invalid.query":     {en: "the request query is invalid", ru: "некорректные параметры запроса"},
+	"invalid.uri":       {en: "the request path is invalid", ru: "некорректный путь запроса"},
+	"invalid.body":      {en: "the request body is invalid", ru: "некорректное тело запроса"},
+	"malformed.query":   {en: "the request query cannot be parsed", ru: "не удалось разобрать параметры запроса"},
+	"malformed.uri":     {en: "the request path cannot be parsed", ru: "не удалось разобрать путь запроса"},
+	"malformed.body":    {en: "the request body cannot be parsed", ru: "не удалось разобрать тело запроса"},
+	"no_changes":        {en: "the request contains no changes", ru: "в запросе нет изменений"},
+	"length_required":   {en: "the upload needs a non-empty body with a Content-Length", ru: "для загрузки нужно непустое тело с заголовком Content-Length"},
+	"internal_error":    {en: "internal server error", ru: "внутренняя ошибка сервера"},
+
+	// Errors produced by the repository.
+	"user_not_found": {
+		en: "user does not exist or is invalid",
+		ru: "пользователь не существует или некорректен",
+	},
+	"insufficient_rights": {
+		en: "insufficient rights to perform the action",
+		ru: "недостаточно прав для выполнения действия",
+	},
+	"organization_not_found": {
+		en: "it is impossible to create a tender, since there is no organization with this id",
+		ru: "невозможно создать тендер: организации с таким id не существует",
+	},
+	"tender_not_found": {
+		en: "tender not found",
+		ru: "тендер не найден",
+	},
+	"tender_version_not_found": {
+		en: "tender or version not found",
+		ru: "тендер или версия не найдены",
+	},
+	"tender_closed": {
+		en: "tender closed",
+		ru: "тендер закрыт",
+	},
+	"tender_cancelled": {
+		en: "tender cancelled",
+		ru: "тендер отменён",
+	},
+	"tender_not_cancellable": {
+		en: "only a created or published tender can be cancelled",
+		ru: "отменить можно только созданный или опубликованный тендер",
+	},
+	"sealing_disabled": {
+		en: "sealed tenders are not enabled on this server",
+		ru: "закрытые тендеры не включены на этом сервере",
+	},
+	"invalid_deadline": {
+		en: "the submission deadline must be in the future",
+		ru: "срок подачи предложений должен быть в будущем",
+	},
+	"submission_closed": {
+		en: "the submission deadline of the tender has passed",
+		ru: "срок подачи предложений по тендеру истёк",
+	},
+	"tender_sealed": {
+		en: "the offers on the tender are sealed until they are revealed",
+		ru: "предложения по тендеру запечатаны до их вскрытия",
+	},
+	"tender_not_revealable": {
+		en: "only a sealed tender can be revealed, once, after its submission deadline",
+		ru: "вскрыть можно только закрытый тендер, один раз и после срока подачи предложений",
+	},
+	"invalid_auction": {
+		en: "an auction needs an unsealed Delivery tender that starts before its submission deadline",
+		ru: "аукцион возможен только для незакрытого тендера на поставку, начинающегося до срока подачи предложений",
+	},
+	"not_auction": {
+		en: "the tender is not an auction",
+		ru: "тендер не является аукционом",
+	},
+	"auction_not_started": {
+		en: "the auction has not started yet",
+		ru: "аукцион ещё не начался",
+	},
+	"price_required": {
+		en: "a bid on an auction needs a price",
+		ru: "для предложения на аукционе нужна цена",
+	},
+	"price_step_too_small": {
+		en: "the new price must be lower than the current one by at least the auction step",
+		ru: "новая цена должна быть ниже текущей как минимум на шаг аукциона",
+	},
+	"auction_decided_automatically": {
+		en: "the winner of an auction is determined automatically",
+		ru: "победитель аукциона определяется автоматически",
+	},
+	"auction_rollback": {
+		en: "an auction bid cannot be rolled back to an earlier price",
+		ru: "предложение на аукционе нельзя откатить к прежней цене",
+	},
+	"invalid_criteria": {
+		en: "the criteria need distinct names and weights that add up to 100",
+		ru: "критерии должны иметь разные названия и веса, в сумме равные 100",
+	},
+	"criteria_locked": {
+		en: "the criteria cannot be changed once offers have been scored",
+		ru: "критерии нельзя изменить после того, как предложения получили оценки",
+	},
+	"criterion_not_found": {
+		en: "criterion not found on the tender of the offer",
+		ru: "критерий не найден в тендере предложения",
+	},
+	"no_criteria": {
+		en: "the tender has no evaluation criteria",
+		ru: "у тендера нет критериев оценки",
+	},
+	"awarded_by_evaluation": {
+		en: "a tender with evaluation criteria is awarded by its weighted ranking",
+		ru: "победитель тендера с критериями оценки определяется по взвешенному рейтингу",
+	},
+	"evaluation_incomplete": {
+		en: "every published offer needs complete scores from a quorum of responsibles before the award",
+		ru: "до выбора победителя каждое опубликованное предложение должно быть полностью оценено кворумом ответственных",
+	},
+	"evaluation_tie": {
+		en: "the best offers share the same weighted score",
+		ru: "у лучших предложений одинаковая взвешенная оценка",
+	},
+	"question_not_found": {
+		en: "question not found on the tender",
+		ru: "вопрос по тендеру не найден",
+	},
+	"question_already_answered": {
+		en: "the question has already been answered",
+		ru: "на вопрос уже дан ответ",
+	},
+	"question_own_tender": {
+		en: "the responsibles of an organization cannot ask questions about its own tender",
+		ru: "ответственные организации не могут задавать вопросы по её собственному тендеру",
+	},
+	"invalid_invitations": {
+		en: "only an invitation-only tender invites organizations, and never its own",
+		ru: "приглашать организации может только тендер по приглашениям, и только чужие",
+	},
+	"bid_dependency_not_found": {
+		en: "can't create an offer because there is no tender or user",
+		ru: "невозможно создать предложение: тендер или пользователь не найдены",
+	},
+	"bid_already_exists": {
+		en: "there can be one proposal from an organization for one tender",
+		ru: "от организации может быть только одно предложение на тендер",
+	},
+	"bids_not_found": {
+		en: "tender or offer not found",
+		ru: "тендер или предложение не найдены",
+	},
+	"bid_not_found": {
+		en: "offer not found",
+		ru: "предложение не найдено",
+	},
+	"bid_version_not_found": {
+		en: "offer or version not found",
+		ru: "предложение или версия не найдены",
+	},
+	"decision_already_submitted": {
+		en: "the decision on this offer has already been submitted",
+		ru: "решение по этому предложению уже принято",
+	},
+	"bid_withdrawn": {
+		en: "offer withdrawn",
+		ru: "предложение отозвано",
+	},
+	"bid_not_withdrawable": {
+		en: "only an offer without a final decision on a published tender can be withdrawn",
+		ru: "отозвать можно только предложение по опубликованному тендеру, по которому нет окончательного решения",
+	},
+	"bid_status_final": {
+		en: "only an offer without a final decision on a published tender can change its status",
+		ru: "изменить статус можно только у предложения по опубликованному тендеру, по которому нет окончательного решения",
+	},
+	"bid_own_tender": {
+		en: "an organization cannot make an offer for its own tender",
+		ru: "организация не может подать предложение на собственный тендер",
+	},
+	"bid_resubmission_forbidden": {
+		en: "a new offer for this tender is not allowed after the previous one was closed",
+		ru: "новое предложение по этому тендеру не допускается после закрытия предыдущего",
+	},
+	"bid_not_scorable": {
+		en: "only a published offer on a published tender can be scored",
+		ru: "оценить можно только опубликованное предложение по опубликованному тендеру",
+	},
+	"bid_not_invited": {
+		en: "the organization is not invited to this tender",
+		ru: "организация не приглашена к участию в этом тендере",
+	},
+	"reviews_not_found": {
+		en: "no tender or reviews found",
+		ru: "тендер или отзывы не найдены",
+	},
+	"attachments_disabled": {
+		en: "attachments are not enabled on this server",
+		ru: "вложения не включены на этом сервере",
+	},
+	"attachment_not_found": {
+		en: "attachment not found",
+		ru: "вложение не найдено",
+	},
+	"attachment_too_large": {
+		en: "the file exceeds the maximum attachment size",
+		ru: "файл превышает максимальный размер вложения",
+	},
+	"attachment_type_not_allowed": {
+		en: "files of this type cannot be attached",
+		ru: "файлы этого типа нельзя прикладывать",
+	},
+	"attachment_quarantined": {
+		en: "the file is in quarantine until its malware scan is done",
+		ru: "файл находится в карантине до окончания проверки на вредоносное ПО",
+	},
+	"attachment_blocked": {
+		en: "the file did not pass its malware scan and cannot be downloaded",
+		ru: "файл не прошёл проверку на вредоносное ПО и не может быть скачан",
+	},
+	"questionnaire_not_found": {
+		en: "prequalification questionnaire not found",
+		ru: "анкета предквалификации не найдена",
+	},
+	"questionnaire_locked": {
+		en: "the questionnaire cannot be changed once suppliers have applied",
+		ru: "анкету нельзя изменить после того, как поставщики подали заявки",
+	},
+	"application_not_found": {
+		en: "prequalification application not found",
+		ru: "заявка на предквалификацию не найдена",
+	},
+	"application_pending": {
+		en: "the organization already has an application under review",
+		ru: "у организации уже есть заявка на рассмотрении",
+	},
+	"application_not_pending": {
+		en: "only an application under review can be changed or reviewed",
+		ru: "изменить или рассмотреть можно только заявку, находящуюся на рассмотрении",
+	},
+	"application_not_revocable": {
+		en: "only an approved application can be revoked",
+		ru: "отозвать можно только одобренную заявку",
+	},
+	"application_own_questionnaire": {
+		en: "an organization cannot prequalify for its own tenders",
+		ru: "организация не может проходить предквалификацию для собственных тендеров",
+	},
+	"invalid_answers": {
+		en: "every required question needs an answer of its type, and only the questions of the questionnaire can be answered, once",
+		ru: "на каждый обязательный вопрос нужен ответ его типа, отвечать можно только на вопросы анкеты и только один раз",
+	},
+	"invalid_validity": {
+		en: "an approval must be valid until a moment in the future",
+		ru: "одобрение должно действовать до момента в будущем",
+	},
+	"not_prequalified": {
+		en: "the supplier has no valid prequalification approval for this tender",
+		ru: "у поставщика нет действующего одобрения предквалификации для этого тендера",
+	},
+	"prequalified_organization_required": {
+		en: "a tender that requires prequalification takes offers on behalf of an organization only",
+		ru: "тендер с предквалификацией принимает предложения только от имени организации",
+	},
+	"debarment_not_found": {
+		en: "debarment not found",
+		ru: "отстранение не найдено",
+	},
+	"debarment_lifted": {
+		en: "the debarment is lifted already",
+		ru: "отстранение уже снято",
+	},
+	"invalid_debarment_period": {
+		en: "a debarment must end after it starts and in the future",
+		ru: "отстранение должно заканчиваться после своего начала и в будущем",
+	},
+	"self_debarment": {
+		en: "an organization cannot debar itself",
+		ru: "организация не может отстранить саму себя",
+	},
+	"platform_admin_required": {
+		en: "only platform admins manage global debarments",
+		ru: "глобальными отстранениями управляют только администраторы платформы",
+	},
+	"supplier_debarred": {
+		en: "the supplier is debarred from bidding on this tender",
+		ru: "поставщик отстранён от участия в этом тендере",
+	},
+	"supplier_debarred.reason": {
+		en: "the supplier is debarred from bidding on this tender: %s",
+		ru: "поставщик отстранён от участия в этом тендере: %s",
+	},
+	"message_not_found": {
+		en: "message not found in the thread of the offer",
+		ru: "сообщение не найдено в переписке по предложению",
+	},
+
+	// Validation rules of the gin binding tags. The first argument is always
+	// the field name, the second one the rule parameter.
+	"rule.required":   {en: "field %q is required", ru: "поле «%s» обязательно"},
+	"rule.uuid":       {en: "field %q must be a valid UUID", ru: "поле «%s» должно быть корректным UUID"},
+	"rule.oneof":      {en: "field %q must be one of: %s", ru: "поле «%s» должно быть одним из: %s"},
+	"rule.max":        {en: "field %q must be at most %s", ru: "поле «%s» должно быть не больше %s"},
+	"rule.min":        {en: "field %q must be at least %s", ru: "поле «%s» должно быть не меньше %s"},
+	"rule.max.string": {en: "field %q must be at most %s characters long", ru: "поле «%s» должно содержать не более %s символов"},
+	"rule.min.string": {en: "field %q must be at least %s characters long", ru: "поле «%s» должно содержать не менее %s символов"},
+	"rule.excluded":   {en: "field %q must be omitted", ru: "поле «%s» должно отсутствовать"},
+	"rule.gt":         {en: "field %q must be greater than %s", ru: "поле «%s» должно быть больше %s"},
+	"rule.invalid":    {en: "field %q is invalid", ru: "поле «%s» некорректно"},
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	English = language.English
+	Russian = language.Russian
+)
+
+// English goes first: it is the fallback for unsupported languages.
+var matcher = language.NewMatcher([]language.Tag{English, Russian})
+
+type translations struct {
+	en string
+	ru string
+}
+
+func (t translations) in(lang language.Tag) string {
+	if lang == Russian && t.ru != "" {
+		return t.ru
+	}
+
+	return t.en
+}
+
+// FromRequest picks the supported language that best matches the
+// Accept-Language header of the request.
+func FromRequest(r *http.Request) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return English
+	}
+
+	_, index, _ := matcher.Match(tags...)
+	if index == 1 {
+		return Russian
+	}
+
+	return English
+}
+
+// Has reports whether the catalog contains a message for key.
+func Has(key string) bool {
+	_, ok := catalog[key]
+	return ok
+}
+
+// Message formats the catalog message key in lang with args. Unknown keys
+// are returned as is, so a missing translation never hides an error.
+func Message(lang language.Tag, key string, args ...any) string {
+	t, ok := catalog[key]
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return t.in(lang)
+	}
+
+	return fmt.Sprintf(t.in(lang), args...)
+}
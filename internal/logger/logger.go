@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying the request-scoped entry.
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the request-scoped entry of ctx. Outside of a request
+// it falls back to a plain entry of log.
+func FromContext(ctx context.Context, log *logrus.Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	return logrus.NewEntry(log)
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SamplingFormatter limits the volume of repetitive logs. Within every tick
+// it lets through the first initial entries with the same level and message
+// and then every thereafter-th one. Warnings and errors are never dropped.
+type SamplingFormatter struct {
+	next       logrus.Formatter
+	initial    int
+	thereafter int
+	tick       time.Duration
+
+	mu     sync.Mutex
+	reset  time.Time
+	counts map[sampleKey]int
+}
+
+type sampleKey struct {
+	level   logrus.Level
+	message string
+}
+
+func NewSamplingFormatter(next logrus.Formatter, initial, thereafter int, tick time.Duration) *SamplingFormatter {
+	return &SamplingFormatter{
+		next:       next,
+		initial:    initial,
+		thereafter: thereafter,
+		tick:       tick,
+		counts:     make(map[sampleKey]int),
+	}
+}
+
+func (f *SamplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level <= logrus.WarnLevel || f.sampled(entry) {
+		return f.next.Format(entry)
+	}
+
+	// logrus writes whatever the formatter returns, so an empty line drops the entry.
+	return nil, nil
+}
+
+func (f *SamplingFormatter) sampled(entry *logrus.Entry) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry.Time.Sub(f.reset) >= f.tick {
+		f.reset = entry.Time
+		clear(f.counts)
+	}
+
+	key := sampleKey{level: entry.Level, message: entry.Message}
+	f.counts[key]++
+	n := f.counts[key]
+
+	if n <= f.initial {
+		return true
+	}
+
+	return f.thereafter > 0 && (n-f.initial)%f.thereafter == 0
+}
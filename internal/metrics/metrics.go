@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tenders"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration     *prometheus.HistogramVec
+	tendersPublished prometheus.Counter
+	bidsCreated      prometheus.Counter
+	decisions        *prometheus.CounterVec
+	awards           prometheus.Counter
+	cancellations    prometheus.Counter
+	withdrawals      prometheus.Counter
+	openTenders      *prometheus.GaugeVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		tendersPublished: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenders_published_total",
+			Help:      "Number of tenders moved to the Published status.",
+		}),
+		bidsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bids_created_total",
+			Help:      "Number of created bids.",
+		}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bid_decisions_total",
+			Help:      "Number of decisions submitted on bids by decision.",
+		}, []string{"decision"}),
+		awards: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenders_awarded_total",
+			Help:      "Number of tenders closed by awarding a bid.",
+		}),
+		cancellations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenders_cancelled_total",
+			Help:      "Number of cancelled tenders.",
+		}),
+		withdrawals: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bids_withdrawn_total",
+			Help:      "Number of bids withdrawn by their authors.",
+		}),
+		openTenders: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "open_tenders",
+			Help:      "Number of published tenders by service type.",
+		}, []string{"service_type"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.tendersPublished,
+		m.bidsCreated,
+		m.decisions,
+		m.awards,
+		m.cancellations,
+		m.withdrawals,
+		m.openTenders,
+	)
+
+	return m
+}
+
+// MustRegister adds collectors owned by other packages, such as the pool
+// statistics, to the exposed registry.
+func (m *Metrics) MustRegister(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware observes the duration of every request. Requests that match no
+// route are reported under one label to keep the cardinality bounded.
+func (m *Metrics) Middleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	m.httpDuration.
+		WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).
+		Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) TenderPublished() {
+	m.tendersPublished.Inc()
+}
+
+func (m *Metrics) BidCreated() {
+	m.bidsCreated.Inc()
+}
+
+func (m *Metrics) DecisionApplied(decision models.BidDecision) {
+	m.decisions.WithLabelValues(string(decision)).Inc()
+}
+
+func (m *Metrics) TenderAwarded() {
+	m.awards.Inc()
+}
+
+func (m *Metrics) TenderCancelled() {
+	m.cancellations.Inc()
+}
+
+func (m *Metrics) BidWithdrawn() {
+	m.withdrawals.Inc()
+}
+
+// SetOpenTenders replaces the open tenders gauge. Service types missing from
+// counts are reported as zero.
+func (m *Metrics) SetOpenTenders(counts map[models.TenderServiceType]int) {
+	for _, serviceType := range []models.TenderServiceType{
+		models.TenderServiceTypeConstruction,
+		models.TenderServiceTypeDelivery,
+		models.TenderServiceTypeManufacture,
+	} {
+		m.openTenders.WithLabelValues(string(serviceType)).Set(float64(counts[serviceType]))
+	}
+}
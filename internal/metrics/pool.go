@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exposes the statistics of a pgx connection pool.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquireCount            *prometheus.Desc
+	acquireDuration         *prometheus.Desc
+	acquiredConns           *prometheus.Desc
+	canceledAcquireCount    *prometheus.Desc
+	constructingConns       *prometheus.Desc
+	emptyAcquireCount       *prometheus.Desc
+	idleConns               *prometheus.Desc
+	maxConns                *prometheus.Desc
+	totalConns              *prometheus.Desc
+	newConnsCount           *prometheus.Desc
+	maxLifetimeDestroyCount *prometheus.Desc
+	maxIdleDestroyCount     *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool:                    pool,
+		acquireCount:            desc("acquire_count_total", "Number of successful connection acquires."),
+		acquireDuration:         desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		acquiredConns:           desc("acquired_conns", "Number of currently acquired connections."),
+		canceledAcquireCount:    desc("canceled_acquire_count_total", "Number of acquires canceled by a context."),
+		constructingConns:       desc("constructing_conns", "Number of connections being established."),
+		emptyAcquireCount:       desc("empty_acquire_count_total", "Number of acquires that waited for a connection."),
+		idleConns:               desc("idle_conns", "Number of idle connections."),
+		maxConns:                desc("max_conns", "Maximum size of the pool."),
+		totalConns:              desc("total_conns", "Total number of connections in the pool."),
+		newConnsCount:           desc("new_conns_count_total", "Number of established connections."),
+		maxLifetimeDestroyCount: desc("max_lifetime_destroy_count_total", "Number of connections closed for exceeding their lifetime."),
+		maxIdleDestroyCount:     desc("max_idle_destroy_count_total", "Number of connections closed for being idle too long."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+
+	counter(c.acquireCount, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	gauge(c.acquiredConns, float64(stat.AcquiredConns()))
+	counter(c.canceledAcquireCount, float64(stat.CanceledAcquireCount()))
+	gauge(c.constructingConns, float64(stat.ConstructingConns()))
+	counter(c.emptyAcquireCount, float64(stat.EmptyAcquireCount()))
+	gauge(c.idleConns, float64(stat.IdleConns()))
+	gauge(c.maxConns, float64(stat.MaxConns()))
+	gauge(c.totalConns, float64(stat.TotalConns()))
+	counter(c.newConnsCount, float64(stat.NewConnsCount()))
+	counter(c.maxLifetimeDestroyCount, float64(stat.MaxLifetimeDestroyCount()))
+	counter(c.maxIdleDestroyCount, float64(stat.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+type OpenTendersCounter interface {
+	CountOpenTendersByServiceType(ctx context.Context) (map[models.TenderServiceType]int, error)
+}
+
+// RefreshOpenTenders updates the open tenders gauge every interval until ctx
+// is canceled. beat is called after every successful refresh.
+func (m *Metrics) RefreshOpenTenders(ctx context.Context, repo OpenTendersCounter, interval time.Duration, beat func(), log *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		counts, err := repo.CountOpenTendersByServiceType(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("failed to refresh open tenders gauge")
+		} else if err == nil {
+			m.SetOpenTenders(counts)
+			beat()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,30 +1,142 @@
 package repository
 
-import "errors"
+import "github.com/DarRo9/Tenders/models"
 
 var (
 	FKViolation = "23503"
 	UniqueConstraint = "23505"
+	NotNullViolation = "23502"
+)
+
+// Kind is the category of a domain error. It decides how the error is
+// reported to the client regardless of which endpoint produced it.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooLarge
+	KindUnsupportedMediaType
+)
+
+// Error is a domain error with a category and a stable machine-readable
+// code. Errors are compared by identity, so every sentinel is a distinct
+// pointer and errors.Is keeps working through wrapping.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func NewError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var (
+	ErrUserNotExist = NewError(KindUnauthorized, "user_not_found", "user does not exist or is invalid")
+	ErrRelationNotExist = NewError(KindForbidden, "insufficient_rights", "insufficient rights to perform the action")
 )
 
 var (
-	ErrUserNotExist = errors.New("user does not exist or is invalid")
-	ErrRelationNotExist = errors.New("insufficient rights to perform the action")
+	ErrOrganizationDepencyNotFound = NewError(KindNotFound, "organization_not_found", "it is impossible to create a tender, since there is no organization with this id")
+	ErrTenderNotFound = NewError(KindNotFound, "tender_not_found", "tender not found")
+	ErrTenderORVersionNotFound = NewError(KindNotFound, "tender_version_not_found", "tender or version not found")
+	ErrTenderClosed = NewError(KindConflict, "tender_closed", "tender closed")
+	ErrTenderCancelled = NewError(KindConflict, "tender_cancelled", "tender cancelled")
+	ErrTenderNotCancellable = NewError(KindConflict, "tender_not_cancellable", "only a created or published tender can be cancelled")
+	ErrSealingDisabled = NewError(KindConflict, "sealing_disabled", "sealed tenders are not enabled on this server")
+	ErrInvalidDeadline = NewError(KindValidation, "invalid_deadline", "the submission deadline must be in the future")
+	ErrSubmissionClosed = NewError(KindConflict, "submission_closed", "the submission deadline of the tender has passed")
+	ErrTenderSealed = NewError(KindConflict, "tender_sealed", "the offers on the tender are sealed until they are revealed")
+	ErrTenderNotRevealable = NewError(KindConflict, "tender_not_revealable", "only a sealed tender can be revealed, once, after its submission deadline")
+	ErrInvalidAuction = NewError(KindValidation, "invalid_auction", "an auction needs an unsealed Delivery tender that starts before its submission deadline")
+	ErrNotAuction = NewError(KindConflict, "not_auction", "the tender is not an auction")
+	ErrAuctionNotStarted = NewError(KindConflict, "auction_not_started", "the auction has not started yet")
+	ErrPriceRequired = NewError(KindValidation, "price_required", "a bid on an auction needs a price")
+	ErrPriceStepTooSmall = NewError(KindConflict, "price_step_too_small", "the new price must be lower than the current one by at least the auction step")
+	ErrAuctionDecision = NewError(KindConflict, "auction_decided_automatically", "the winner of an auction is determined automatically")
+	ErrAuctionRollback = NewError(KindConflict, "auction_rollback", "an auction bid cannot be rolled back to an earlier price")
+	ErrInvalidCriteria = NewError(KindValidation, "invalid_criteria", "the criteria need distinct names and weights that add up to 100")
+	ErrCriteriaLocked = NewError(KindConflict, "criteria_locked", "the criteria cannot be changed once offers have been scored")
+	ErrCriterionNotFound = NewError(KindNotFound, "criterion_not_found", "criterion not found on the tender of the offer")
+	ErrNoCriteria = NewError(KindConflict, "no_criteria", "the tender has no evaluation criteria")
+	ErrCriteriaDecision = NewError(KindConflict, "awarded_by_evaluation", "a tender with evaluation criteria is awarded by its weighted ranking")
+	ErrEvaluationIncomplete = NewError(KindConflict, "evaluation_incomplete", "every published offer needs complete scores from a quorum of responsibles before the award")
+	ErrEvaluationTie = NewError(KindConflict, "evaluation_tie", "the best offers share the same weighted score")
+	ErrQuestionNotFound = NewError(KindNotFound, "question_not_found", "question not found on the tender")
+	ErrQuestionAnswered = NewError(KindConflict, "question_already_answered", "the question has already been answered")
+	ErrQuestionOwnTender = NewError(KindForbidden, "question_own_tender", "the responsibles of an organization cannot ask questions about its own tender")
+	ErrInvalidInvitations = NewError(KindValidation, "invalid_invitations", "only an invitation-only tender invites organizations, and never its own")
 )
 
 var (
-	ErrOrganizationDepencyNotFound = errors.New("it is impossible to create a tender, since there is no organization with this id")
-	ErrTenderNotFound = errors.New("tender nettender not found found")
-	ErrTenderORVersionNotFound = errors.New("tender or version not found")
-	ErrTenderClosed = errors.New("tender closed")
+	ErrBidDependencyNotFound = NewError(KindNotFound, "bid_dependency_not_found", "Can't create an offer because there is no tender or user")
+	ErrBidUnique = NewError(KindConflict, "bid_already_exists", "There can be one proposal from an organization for one tender")
+	ErrBidTenderNotFound = NewError(KindNotFound, "bids_not_found", "tender or offer not found")
+	ErrBidNotFound = NewError(KindNotFound, "bid_not_found", "offer not found")
+	ErrBidORVersionNotFound = NewError(KindNotFound, "bid_version_not_found", "offer or version not found")
+	ErrBidReviewsNotFound = NewError(KindNotFound, "reviews_not_found", "no tender or reviews found")
+	ErrMessageNotFound = NewError(KindNotFound, "message_not_found", "message not found in the thread of the offer")
+	ErrBidDecisionUnique = NewError(KindConflict, "decision_already_submitted", "the decision on this offer has already been submitted")
+	ErrBidWithdrawn = NewError(KindConflict, "bid_withdrawn", "offer withdrawn")
+	ErrBidNotWithdrawable = NewError(KindConflict, "bid_not_withdrawable", "only an offer without a final decision on a published tender can be withdrawn")
+	ErrBidStatusFinal = NewError(KindConflict, "bid_status_final", "only an offer without a final decision on a published tender can change its status")
+	ErrBidOwnTender = NewError(KindForbidden, "bid_own_tender", "an organization cannot make an offer for its own tender")
+	ErrBidResubmissionForbidden = NewError(KindConflict, "bid_resubmission_forbidden", "a new offer for this tender is not allowed after the previous one was closed")
+	ErrBidNotScorable = NewError(KindConflict, "bid_not_scorable", "only a published offer on a published tender can be scored")
+	ErrBidNotInvited = NewError(KindForbidden, "bid_not_invited", "the organization is not invited to this tender")
 )
 
 var (
-	ErrBidDependencyNotFound = errors.New("Can't create an offer because there is no tender or user")
-	ErrBidUnique = errors.New("There can be one proposal from an organization for one tender")
-	ErrBidTenderNotFound = errors.New("tender or offer not found")
-	ErrBidNotFound = errors.New("offer not found")
-	ErrBidORVersionNotFound = errors.New("offer or version not found")
-	ErrBidReviewsNotFound = errors.New("no tender or reviews found")
+	ErrAttachmentsDisabled = NewError(KindConflict, "attachments_disabled", "attachments are not enabled on this server")
+	ErrAttachmentNotFound = NewError(KindNotFound, "attachment_not_found", "attachment not found")
+	ErrAttachmentTooLarge = NewError(KindTooLarge, "attachment_too_large", "the file exceeds the maximum attachment size")
+	ErrAttachmentType = NewError(KindUnsupportedMediaType, "attachment_type_not_allowed", "files of this type cannot be attached")
+	ErrAttachmentQuarantined = NewError(KindConflict, "attachment_quarantined", "the file is in quarantine until its malware scan is done")
+	ErrAttachmentBlocked = NewError(KindConflict, "attachment_blocked", "the file did not pass its malware scan and cannot be downloaded")
 )
 
+var (
+	ErrQuestionnaireNotFound = NewError(KindNotFound, "questionnaire_not_found", "prequalification questionnaire not found")
+	ErrQuestionnaireLocked = NewError(KindConflict, "questionnaire_locked", "the questionnaire cannot be changed once suppliers have applied")
+	ErrApplicationNotFound = NewError(KindNotFound, "application_not_found", "prequalification application not found")
+	ErrApplicationPending = NewError(KindConflict, "application_pending", "the organization already has an application under review")
+	ErrApplicationNotPending = NewError(KindConflict, "application_not_pending", "only an application under review can be changed or reviewed")
+	ErrApplicationNotRevocable = NewError(KindConflict, "application_not_revocable", "only an approved application can be revoked")
+	ErrApplicationOwnQuestionnaire = NewError(KindForbidden, "application_own_questionnaire", "an organization cannot prequalify for its own tenders")
+	ErrInvalidAnswers = NewError(KindValidation, "invalid_answers", "every required question needs an answer of its type, and only the questions of the questionnaire can be answered, once")
+	ErrInvalidValidity = NewError(KindValidation, "invalid_validity", "an approval must be valid until a moment in the future")
+	ErrNotPrequalified = NewError(KindForbidden, "not_prequalified", "the supplier has no valid prequalification approval for this tender")
+	ErrPrequalifiedOrganizationRequired = NewError(KindForbidden, "prequalified_organization_required", "a tender that requires prequalification takes offers on behalf of an organization only")
+)
+
+var (
+	ErrDebarmentNotFound = NewError(KindNotFound, "debarment_not_found", "debarment not found")
+	ErrDebarmentLifted = NewError(KindConflict, "debarment_lifted", "the debarment is lifted already")
+	ErrInvalidDebarmentPeriod = NewError(KindValidation, "invalid_debarment_period", "a debarment must end after it starts and in the future")
+	ErrSelfDebarment = NewError(KindValidation, "self_debarment", "an organization cannot debar itself")
+	ErrPlatformAdminRequired = NewError(KindForbidden, "platform_admin_required", "only platform admins manage global debarments")
+	ErrSupplierDebarred = NewError(KindForbidden, "supplier_debarred", "the supplier is debarred from bidding on this tender")
+)
+
+// DebarredError is ErrSupplierDebarred with the debarment behind it, so that
+// the supplier learns why it cannot bid.
+type DebarredError struct {
+	Debarment *models.Debarment
+}
+
+func (e *DebarredError) Error() string {
+	return ErrSupplierDebarred.Message + ": " + e.Debarment.Reason
+}
+
+func (e *DebarredError) Unwrap() error {
+	return ErrSupplierDebarred
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) AttachToTender(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(attachment.TenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	result := m.addAttachment(attachment)
+
+	m.tenderVersions = append(m.tenderVersions, snapshotTender(tender))
+	tender.AttachmentIDs = append(slices.Clone(tender.AttachmentIDs), result.ID)
+	tender.Version++
+
+	return clone(result), nil
+}
+
+func (m *Memory) DetachFromTender(ctx context.Context, tenderID, attachmentID string) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	if !contains(tender.AttachmentIDs, attachmentID) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	m.tenderVersions = append(m.tenderVersions, snapshotTender(tender))
+	tender.AttachmentIDs = without(tender.AttachmentIDs, attachmentID)
+	tender.Version++
+
+	return clone(tender), nil
+}
+
+func (m *Memory) AttachToBid(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(*attachment.BidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	result := m.addAttachment(attachment)
+
+	m.bidVersions = append(m.bidVersions, clone(bid))
+	bid.AttachmentIDs = append(slices.Clone(bid.AttachmentIDs), result.ID)
+	m.carrySealed(bid.ID, bid.Version, bid.Version+1)
+	bid.Version++
+
+	return clone(result), nil
+}
+
+func (m *Memory) DetachFromBid(ctx context.Context, bidID, attachmentID string) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	if !contains(bid.AttachmentIDs, attachmentID) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	m.bidVersions = append(m.bidVersions, clone(bid))
+	bid.AttachmentIDs = without(bid.AttachmentIDs, attachmentID)
+	m.carrySealed(bid.ID, bid.Version, bid.Version+1)
+	bid.Version++
+
+	return clone(bid), nil
+}
+
+func (m *Memory) GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	attachment := find(m.attachments, func(a *models.Attachment) bool { return a.ID == attachmentID })
+	if attachment == nil {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	return clone(attachment), nil
+}
+
+func (m *Memory) GetQuarantinedAttachments(ctx context.Context, limit int32) ([]*models.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return page(filter(m.attachments, func(a *models.Attachment) bool {
+		return a.ScanStatus == models.ScanStatusQuarantined
+	}), limit, 0), nil
+}
+
+func (m *Memory) RecordAttachmentScan(ctx context.Context, attachmentID string, scan *models.AttachmentScan) (*models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attachment := find(m.attachments, func(a *models.Attachment) bool {
+		return a.ID == attachmentID && a.ScanStatus == models.ScanStatusQuarantined
+	})
+	if attachment == nil {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	scannedAt := now()
+	attachment.ScanStatus = scan.Status
+	attachment.ScanSignature = scan.Signature
+	attachment.ScannedAt = &scannedAt
+
+	return clone(attachment), nil
+}
+
+func (m *Memory) addAttachment(attachment *models.Attachment) *models.Attachment {
+	result := clone(attachment)
+	result.ID = newID()
+	result.CreatedAt = now()
+	result.ScanStatus = models.ScanStatusQuarantined
+	result.ScanSignature = ""
+	result.ScannedAt = nil
+	m.attachments = append(m.attachments, result)
+
+	return result
+}
+
+// without returns a copy of the IDs with id left out. The slices of the
+// versions are shared, so they are never changed in place.
+func without(ids []string, id string) []string {
+	result := []string{}
+	for _, other := range ids {
+		if other != id {
+			result = append(result, other)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) SubmitAuctionPrice(ctx context.Context, bidID string, price float64) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if tender.Auction == nil || bid.Price != nil && *bid.Price-price < tender.Auction.Step {
+		return nil, repository.ErrPriceStepTooSmall
+	}
+	m.bidVersions = append(m.bidVersions, clone(bid))
+
+	pricedAt := now()
+	bid.Price, bid.PricedAt = &price, &pricedAt
+	m.carrySealed(bidID, bid.Version, bid.Version+1)
+	bid.Version++
+
+	return clone(bid), nil
+}
+
+func (m *Memory) ExtendAuction(ctx context.Context, tenderID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil || tender.Auction == nil || tender.Status != models.TenderStatusPublished {
+		return false, nil
+	}
+
+	at := now()
+	end := at.Add(time.Duration(tender.Auction.ExtensionSeconds) * time.Second)
+	if !tender.SubmissionDeadline.After(at) || !tender.SubmissionDeadline.Before(end) {
+		return false, nil
+	}
+	tender.SubmissionDeadline = &end
+
+	return true, nil
+}
+
+func (m *Memory) GetAuctionRanking(ctx context.Context, tenderID, userID string) ([]*models.AuctionRank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bids := m.auctionBids(tenderID, models.BidStatusPublished, models.BidStatusApproved)
+
+	ranking := []*models.AuctionRank{}
+	for i, bid := range bids {
+		ranking = append(ranking, &models.AuctionRank{
+			Rank:     i + 1,
+			Price:    *bid.Price,
+			PricedAt: *bid.PricedAt,
+			Own:      m.speaksForBid(userID, bid),
+		})
+	}
+
+	return ranking, nil
+}
+
+func (m *Memory) CloseEndedAuctions(ctx context.Context) ([]*models.AuctionResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	results := []*models.AuctionResult{}
+	closedAt := now()
+	for _, tender := range m.tenders {
+		if tender.Auction == nil || tender.Status != models.TenderStatusPublished || tender.SubmissionDeadline.After(closedAt) {
+			continue
+		}
+
+		result := &models.AuctionResult{TenderID: tender.ID}
+		details := "no bids"
+		bids := filter(m.auctionBids(tender.ID, models.BidStatusPublished), func(b *models.BidResponse) bool {
+			return m.blockingDebarment(tender, b.AuthorID, b.OrganizationID) == nil
+		})
+		if len(bids) != 0 {
+			winner := m.bidByID(bids[0].ID)
+			winner.Status = models.BidStatusApproved
+			result.BidID = winner.ID
+			details = "winning price: " + strconv.FormatFloat(*winner.Price, 'f', 2, 64)
+		}
+		tender.Status = models.TenderStatusClosed
+
+		m.audit = append(m.audit, &models.AuditRecord{
+			ID:        newID(),
+			Action:    models.AuditActionAuctionClosed,
+			TenderID:  tender.ID,
+			BidID:     result.BidID,
+			Details:   details,
+			CreatedAt: closedAt,
+		})
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// auctionBids returns copies of the priced bids on the tender with one of
+// the statuses, best first.
+func (m *Memory) auctionBids(tenderID string, statuses ...models.BidStatus) []*models.BidResponse {
+	bids := filter(m.bids, func(b *models.BidResponse) bool {
+		return b.TenderID == tenderID && b.Price != nil && contains(statuses, b.Status)
+	})
+	sort.SliceStable(bids, func(i, j int) bool {
+		if *bids[i].Price != *bids[j].Price {
+			return *bids[i].Price < *bids[j].Price
+		}
+		if !bids[i].PricedAt.Equal(*bids[j].PricedAt) {
+			return bids[i].PricedAt.Before(*bids[j].PricedAt)
+		}
+		return bids[i].ID < bids[j].ID
+	})
+
+	return bids
+}
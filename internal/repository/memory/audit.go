@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetAuditLog(ctx context.Context, tenderID string, limit, offset int32) ([]*models.AuditRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := filter(m.audit, func(r *models.AuditRecord) bool { return r.TenderID == tenderID })
+
+	return page(records, limit, offset), nil
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) ConstructBid(ctx context.Context, bid *models.BidCreate, sealed *models.SealedContent) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	duplicate := find(m.bids, func(b *models.BidResponse) bool {
+		return b.TenderID == bid.TenderID && sameAuthor(b, bid.AuthorId, bid.OrganizationID) && isLive(b.Status)
+	})
+	if duplicate != nil {
+		return nil, repository.ErrBidUnique
+	}
+
+	if m.tenderByID(bid.TenderID) == nil || m.employeeByID(bid.AuthorId) == nil ||
+		bid.OrganizationID != nil && m.organizationByID(*bid.OrganizationID) == nil {
+		return nil, repository.ErrBidDependencyNotFound
+	}
+
+	bidResp := &models.BidResponse{
+		ID:             newID(),
+		Name:           bid.Name,
+		Description:    bid.Description,
+		Status:         models.BidStatusCreated,
+		TenderID:       bid.TenderID,
+		AuthorType:     bid.AuthorType,
+		AuthorID:       bid.AuthorId,
+		OrganizationID: bid.OrganizationID,
+		Version:        1,
+		CreatedAt:      now(),
+	}
+	if bid.Price != nil {
+		price, pricedAt := *bid.Price, bidResp.CreatedAt
+		bidResp.Price, bidResp.PricedAt = &price, &pricedAt
+	}
+	if sealed != nil {
+		bidResp.ID, bidResp.Name, bidResp.Description, bidResp.Sealed = sealed.BidID, "", "", true
+		m.sealed[bidVersion{bidResp.ID, 1}] = sealed.Content
+	}
+	m.bids = append(m.bids, bidResp)
+
+	return clone(bidResp), nil
+}
+
+func (m *Memory) ChangeBid(ctx context.Context, bidID string, bidEdit *models.BidEdit) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+	m.bidVersions = append(m.bidVersions, clone(bid))
+
+	if bidEdit.Name != nil {
+		bid.Name = *bidEdit.Name
+	}
+	if bidEdit.Description != nil {
+		bid.Description = *bidEdit.Description
+	}
+	m.carrySealed(bidID, bid.Version, bid.Version+1)
+	bid.Version++
+
+	return clone(bid), nil
+}
+
+func (m *Memory) CancelChangesOfBid(ctx context.Context, bidID string, version int32) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	previous := find(m.bidVersions, func(b *models.BidResponse) bool {
+		return b.ID == bidID && b.Version == int(version)
+	})
+	if previous == nil {
+		return nil, repository.ErrBidORVersionNotFound
+	}
+	m.bidVersions = append(m.bidVersions, clone(bid))
+
+	current := bid.Version
+	*bid = *previous
+	bid.Version = current + 1
+	m.carrySealed(bidID, int(version), bid.Version)
+
+	return clone(bid), nil
+}
+
+func (m *Memory) GetBidsOfTender(ctx context.Context, tenderID string, limit, offset int32) ([]*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bids := filter(m.bids, func(b *models.BidResponse) bool {
+		return b.TenderID == tenderID && b.Status != models.BidStatusCreated
+	})
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].CreatedAt.Before(bids[j].CreatedAt) })
+
+	bids = page(bids, limit, offset)
+	if len(bids) == 0 {
+		return nil, repository.ErrBidTenderNotFound
+	}
+
+	return bids, nil
+}
+
+func (m *Memory) GetBidsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bids := filter(m.bids, func(b *models.BidResponse) bool { return m.speaksForBid(userID, b) })
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Name < bids[j].Name })
+
+	return page(bids, limit, offset), nil
+}
+
+func (m *Memory) GetBidsWithID(ctx context.Context, bidID string) (*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	return clone(bid), nil
+}
+
+func (m *Memory) RenewStatusOfBid(ctx context.Context, bidID, username string, status *models.BidStatus) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if bid.Status != models.BidStatusCreated && bid.Status != models.BidStatusPublished ||
+		tender == nil || tender.Status != models.TenderStatusPublished {
+		return nil, repository.ErrBidStatusFinal
+	}
+	bid.Status = *status
+
+	return clone(bid), nil
+}
+
+func (m *Memory) WithdrawBid(ctx context.Context, bidID, reason string) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if bid.Status != models.BidStatusCreated && bid.Status != models.BidStatusPublished ||
+		tender == nil || tender.Status != models.TenderStatusPublished {
+		return nil, repository.ErrBidNotWithdrawable
+	}
+	m.bidVersions = append(m.bidVersions, clone(bid))
+
+	bid.Status = models.BidStatusWithdrawn
+	bid.WithdrawalReason = reason
+	m.carrySealed(bidID, bid.Version, bid.Version+1)
+	bid.Version++
+
+	invalidatedAt := now()
+	for _, d := range m.decisions {
+		if d.BidID == bidID && d.InvalidatedAt == nil {
+			d.InvalidatedAt = &invalidatedAt
+		}
+	}
+
+	return clone(bid), nil
+}
+
+func (m *Memory) GetBidStatusesOfAuthor(ctx context.Context, tenderID, authorID string, organizationID *models.OrganizationID) ([]models.BidStatus, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bids := filter(m.bids, func(b *models.BidResponse) bool {
+		return b.TenderID == tenderID && sameAuthor(b, authorID, organizationID)
+	})
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].CreatedAt.Before(bids[j].CreatedAt) })
+
+	var statuses []models.BidStatus
+	for _, b := range bids {
+		statuses = append(statuses, b.Status)
+	}
+
+	return statuses, nil
+}
+
+// sameAuthor compares organization bids by organization and user bids by
+// author, like the live bid indexes do.
+func sameAuthor(bid *models.BidResponse, authorID string, organizationID *models.OrganizationID) bool {
+	if organizationID != nil {
+		return bid.OrganizationID != nil && *bid.OrganizationID == *organizationID
+	}
+
+	return bid.OrganizationID == nil && bid.AuthorID == authorID
+}
+
+// isLive mirrors the predicate of the live bid indexes.
+func isLive(status models.BidStatus) bool {
+	return status == models.BidStatusCreated || status == models.BidStatusPublished || status == models.BidStatusApproved
+}
+
+// carrySealed gives a new version of a bid the sealed content of an older
+// one, as copying the row does in postgres.
+func (m *Memory) carrySealed(bidID string, from, to int) {
+	if content, ok := m.sealed[bidVersion{bidID, from}]; ok {
+		m.sealed[bidVersion{bidID, to}] = content
+	}
+}
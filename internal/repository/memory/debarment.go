@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) CreateDebarment(ctx context.Context, userID string, create *models.DebarmentCreate, details string) (*models.Debarment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.organizationByID(create.SupplierID) == nil {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+	if create.OrganizationID != nil && m.organizationByID(*create.OrganizationID) == nil {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+
+	createdAt := now()
+	validFrom := createdAt
+	if create.ValidFrom != nil {
+		validFrom = create.ValidFrom.UTC().Truncate(time.Microsecond)
+	}
+
+	debarment := &models.Debarment{
+		ID:             newID(),
+		SupplierID:     create.SupplierID,
+		Scope:          create.Scope,
+		OrganizationID: create.OrganizationID,
+		Reason:         create.Reason,
+		ValidFrom:      validFrom,
+		CreatedBy:      userID,
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	if create.ValidUntil != nil {
+		validUntil := create.ValidUntil.UTC().Truncate(time.Microsecond)
+		debarment.ValidUntil = &validUntil
+	}
+	m.debarments = append(m.debarments, debarment)
+	m.auditDebarment(debarment.ID, userID, models.AuditActionDebarmentCreated, details)
+
+	return clone(debarment), nil
+}
+
+func (m *Memory) GetDebarment(ctx context.Context, debarmentID string) (*models.Debarment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	debarment := m.debarmentByID(debarmentID)
+	if debarment == nil {
+		return nil, repository.ErrDebarmentNotFound
+	}
+
+	return clone(debarment), nil
+}
+
+func (m *Memory) GetDebarments(ctx context.Context, organizationID *models.OrganizationID, limit, offset int32) ([]*models.Debarment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	debarments := filter(m.debarments, func(d *models.Debarment) bool {
+		return organizationID == nil || d.OrganizationID == nil || *d.OrganizationID == *organizationID
+	})
+	sort.SliceStable(debarments, func(i, j int) bool { return debarments[i].CreatedAt.After(debarments[j].CreatedAt) })
+
+	return page(debarments, limit, offset), nil
+}
+
+func (m *Memory) GetDebarmentsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Debarment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	at := time.Now()
+	debarments := filter(m.debarments, func(d *models.Debarment) bool {
+		return d.LiftedAt == nil && (d.ValidUntil == nil || d.ValidUntil.After(at)) && m.isResponsible(userID, d.SupplierID)
+	})
+	sort.SliceStable(debarments, func(i, j int) bool { return debarments[i].ValidFrom.Before(debarments[j].ValidFrom) })
+
+	return page(debarments, limit, offset), nil
+}
+
+func (m *Memory) UpdateDebarment(ctx context.Context, debarmentID, userID string, edit *models.DebarmentEdit, details string) (*models.Debarment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	debarment, err := m.changeableDebarment(debarmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if edit.Reason != nil {
+		debarment.Reason = *edit.Reason
+	}
+	if edit.ValidUntil != nil {
+		validUntil := edit.ValidUntil.UTC().Truncate(time.Microsecond)
+		debarment.ValidUntil = &validUntil
+	}
+	debarment.UpdatedAt = now()
+	m.auditDebarment(debarmentID, userID, models.AuditActionDebarmentChanged, details)
+
+	return clone(debarment), nil
+}
+
+func (m *Memory) LiftDebarment(ctx context.Context, debarmentID, userID, reason string) (*models.Debarment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	debarment, err := m.changeableDebarment(debarmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	liftedAt := now()
+	debarment.LiftedBy = userID
+	debarment.LiftedAt = &liftedAt
+	debarment.LiftReason = reason
+	debarment.UpdatedAt = liftedAt
+	m.auditDebarment(debarmentID, userID, models.AuditActionDebarmentLifted, reason)
+
+	return clone(debarment), nil
+}
+
+func (m *Memory) GetDebarmentAudit(ctx context.Context, debarmentID string, limit, offset int32) ([]*models.AuditRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := filter(m.audit, func(r *models.AuditRecord) bool { return r.DebarmentID == debarmentID })
+
+	return page(records, limit, offset), nil
+}
+
+func (m *Memory) ControlDebarment(ctx context.Context, tenderID, userID string, organizationID *models.OrganizationID) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil
+	}
+
+	if debarment := m.blockingDebarment(tender, userID, organizationID); debarment != nil {
+		return &repository.DebarredError{Debarment: clone(debarment)}
+	}
+
+	return nil
+}
+
+// blockingDebarment returns the debarment in force that blocks the
+// organization, or without one any organization of the user, from the
+// tender the longest.
+func (m *Memory) blockingDebarment(tender *models.TenderResponse, userID string, organizationID *models.OrganizationID) *models.Debarment {
+	at := time.Now()
+
+	var blocking *models.Debarment
+	for _, d := range m.debarments {
+		switch {
+		case !d.InForce(at):
+			continue
+		case d.OrganizationID != nil && *d.OrganizationID != tender.OrganizationID:
+			continue
+		case organizationID != nil && d.SupplierID != *organizationID:
+			continue
+		case organizationID == nil && !m.isResponsible(userID, d.SupplierID):
+			continue
+		}
+
+		if blocking == nil || blocking.ValidUntil != nil && (d.ValidUntil == nil || d.ValidUntil.After(*blocking.ValidUntil)) {
+			blocking = d
+		}
+	}
+
+	return blocking
+}
+
+func (m *Memory) debarmentByID(id string) *models.Debarment {
+	return find(m.debarments, func(d *models.Debarment) bool { return d.ID == id })
+}
+
+func (m *Memory) changeableDebarment(id string) (*models.Debarment, error) {
+	debarment := m.debarmentByID(id)
+	switch {
+	case debarment == nil:
+		return nil, repository.ErrDebarmentNotFound
+	case debarment.LiftedAt != nil:
+		return nil, repository.ErrDebarmentLifted
+	}
+
+	return debarment, nil
+}
+
+func (m *Memory) auditDebarment(debarmentID, userID string, action models.AuditAction, details string) {
+	m.audit = append(m.audit, &models.AuditRecord{
+		ID:          newID(),
+		ActorID:     &userID,
+		Action:      action,
+		DebarmentID: debarmentID,
+		Details:     details,
+		CreatedAt:   now(),
+	})
+}
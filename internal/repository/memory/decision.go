@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) ApplyBidDecision(ctx context.Context, bidID, username string, decision *models.BidDecision) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user := m.employeeByUsername(username)
+	if user == nil {
+		return nil, repository.ErrUserNotExist
+	}
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	duplicate := find(m.decisions, func(d *models.BidDecisionResponse) bool {
+		return d.BidID == bidID && d.UserID == user.ID
+	})
+	if duplicate != nil {
+		return nil, repository.ErrBidDecisionUnique
+	}
+
+	m.decisions = append(m.decisions, &models.BidDecisionResponse{
+		ID:       newID(),
+		BidID:    bidID,
+		UserID:   user.ID,
+		Decision: *decision,
+	})
+
+	return clone(bid), nil
+}
+
+func (m *Memory) CountOrganizationsByBid(ctx context.Context, bidID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return 0, nil
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if tender == nil {
+		return 0, nil
+	}
+
+	var count int
+	for _, r := range m.responsibles {
+		if r.OrganizationID == tender.OrganizationID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (m *Memory) CountApplyedDecisions(ctx context.Context, bidID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int
+	for _, d := range m.decisions {
+		if d.BidID == bidID && d.Decision == models.BidDecisionApproved && d.InvalidatedAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) SetTenderCriteria(ctx context.Context, tenderID string, criteria []*models.CriterionCreate) ([]*models.Criterion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tenderByID(tenderID) == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	scored := find(m.scores, func(s *models.BidScore) bool {
+		criterion := m.criterionByID(s.CriterionID)
+		return criterion != nil && criterion.TenderID == tenderID
+	})
+	if scored != nil {
+		return nil, repository.ErrCriteriaLocked
+	}
+
+	names := make(map[string]bool, len(criteria))
+	for _, criterion := range criteria {
+		if names[criterion.Name] {
+			return nil, repository.ErrInvalidCriteria
+		}
+		names[criterion.Name] = true
+	}
+
+	kept := m.criteria[:0]
+	for _, criterion := range m.criteria {
+		if criterion.TenderID != tenderID {
+			kept = append(kept, criterion)
+		}
+	}
+	m.criteria = kept
+
+	for _, criterion := range criteria {
+		m.criteria = append(m.criteria, &models.Criterion{
+			ID:       newID(),
+			TenderID: tenderID,
+			Name:     criterion.Name,
+			Weight:   criterion.Weight,
+		})
+	}
+
+	return filter(m.criteria, func(c *models.Criterion) bool { return c.TenderID == tenderID }), nil
+}
+
+func (m *Memory) GetCriteriaByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.Criterion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.criteria, func(c *models.Criterion) bool { return contains(tenderIDs, c.TenderID) }), nil
+}
+
+func (m *Memory) ScoreBid(ctx context.Context, bidID, userID string, scores []*models.CriterionScore) ([]*models.BidScore, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	for _, score := range scores {
+		criterion := m.criterionByID(score.CriterionID)
+		if bid == nil || criterion == nil || criterion.TenderID != bid.TenderID {
+			return nil, repository.ErrCriterionNotFound
+		}
+	}
+
+	scoredAt := now()
+	for _, score := range scores {
+		existing := find(m.scores, func(s *models.BidScore) bool {
+			return s.BidID == bidID && s.CriterionID == score.CriterionID && s.UserID == userID
+		})
+		if existing != nil {
+			existing.Score, existing.ScoredAt = score.Score, scoredAt
+			continue
+		}
+
+		m.scores = append(m.scores, &models.BidScore{
+			BidID:       bidID,
+			CriterionID: score.CriterionID,
+			UserID:      userID,
+			Score:       score.Score,
+			ScoredAt:    scoredAt,
+		})
+	}
+
+	result := []*models.BidScore{}
+	for _, criterion := range m.criteria {
+		score := find(m.scores, func(s *models.BidScore) bool {
+			return s.BidID == bidID && s.CriterionID == criterion.ID && s.UserID == userID
+		})
+		if score != nil {
+			result = append(result, clone(score))
+		}
+	}
+
+	return result, nil
+}
+
+func (m *Memory) GetScoresOfTender(ctx context.Context, tenderID string) ([]*models.BidScore, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.scores, func(s *models.BidScore) bool {
+		bid := m.bidByID(s.BidID)
+		return bid != nil && bid.TenderID == tenderID
+	}), nil
+}
+
+func (m *Memory) AwardBid(ctx context.Context, bidID, username, details string) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil || bid.Status != models.BidStatusPublished {
+		return nil, repository.ErrTenderClosed
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if tender.Status != models.TenderStatusPublished {
+		return nil, repository.ErrTenderClosed
+	}
+
+	bid.Status = models.BidStatusApproved
+	tender.Status = models.TenderStatusClosed
+
+	var actorID *string
+	if user := m.employeeByUsername(username); user != nil {
+		actorID = &user.ID
+	}
+
+	m.audit = append(m.audit, &models.AuditRecord{
+		ID:        newID(),
+		ActorID:   actorID,
+		Action:    models.AuditActionTenderAwarded,
+		TenderID:  tender.ID,
+		BidID:     bid.ID,
+		Details:   details,
+		CreatedAt: now(),
+	})
+
+	return clone(bid), nil
+}
+
+func (m *Memory) criterionByID(id string) *models.Criterion {
+	return find(m.criteria, func(c *models.Criterion) bool { return c.ID == id })
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetCommentsOfBid(ctx context.Context, tenderID, authorUsername string, limit, offset int32) ([]*models.BidReviewResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	author := m.employeeByUsername(authorUsername)
+	if author == nil {
+		return nil, repository.ErrBidReviewsNotFound
+	}
+
+	messages := m.sortedMessages(func(msg *models.BidMessage) bool {
+		bid := m.bidByID(msg.BidID)
+		return msg.Role == models.MessageRoleBuyer && bid != nil && bid.TenderID == tenderID && bid.AuthorID == author.ID
+	})
+
+	messages = page(messages, limit, offset)
+	if len(messages) == 0 {
+		return nil, repository.ErrBidReviewsNotFound
+	}
+
+	reviews := make([]*models.BidReviewResponse, len(messages))
+	for i, message := range messages {
+		reviews[i] = message.Review()
+	}
+
+	return reviews, nil
+}
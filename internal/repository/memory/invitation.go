@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) InviteOrganizations(ctx context.Context, tenderID string, organizationIDs []models.OrganizationID) ([]*models.TenderInvitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tenderByID(tenderID) == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	for _, organizationID := range organizationIDs {
+		if m.organizationByID(organizationID) == nil {
+			return nil, repository.ErrOrganizationDepencyNotFound
+		}
+	}
+
+	m.invite(tenderID, organizationIDs)
+
+	return filter(m.invitations, func(i *models.TenderInvitation) bool { return i.TenderID == tenderID }), nil
+}
+
+func (m *Memory) GetTenderInvitations(ctx context.Context, tenderID string) ([]*models.TenderInvitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.tenderByID(tenderID) == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	return filter(m.invitations, func(i *models.TenderInvitation) bool { return i.TenderID == tenderID }), nil
+}
+
+func (m *Memory) GetInvitedTenders(ctx context.Context, userID string, limit, offset int32) ([]*models.TenderResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenders := filter(m.tenders, func(t *models.TenderResponse) bool {
+		return t.Status == models.TenderStatusPublished && t.Visibility == models.TenderVisibilityInvitation &&
+			m.isInvited(userID, t.ID)
+	})
+	sort.SliceStable(tenders, func(i, j int) bool { return tenders[i].Name < tenders[j].Name })
+
+	return page(tenders, limit, offset), nil
+}
+
+func (m *Memory) ControlTenderVisibility(ctx context.Context, tenderID, userID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return repository.ErrTenderNotFound
+	}
+
+	if !m.isVisible(tender, userID) {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+// invite skips the organizations invited already.
+func (m *Memory) invite(tenderID string, organizationIDs []models.OrganizationID) {
+	for _, organizationID := range organizationIDs {
+		invited := find(m.invitations, func(i *models.TenderInvitation) bool {
+			return i.TenderID == tenderID && i.OrganizationID == organizationID
+		})
+		if invited != nil {
+			continue
+		}
+
+		m.invitations = append(m.invitations, &models.TenderInvitation{
+			TenderID:       tenderID,
+			OrganizationID: organizationID,
+			InvitedAt:      now(),
+		})
+	}
+}
+
+// isVisible reports whether the user sees the tender regardless of its
+// status. An empty userID is an anonymous user.
+func (m *Memory) isVisible(tender *models.TenderResponse, userID string) bool {
+	switch {
+	case tender.Visibility == models.TenderVisibilityPublic:
+		return true
+	case m.isResponsible(userID, tender.OrganizationID):
+		return true
+	case tender.Visibility == models.TenderVisibilityInvitation:
+		return m.isInvited(userID, tender.ID)
+	}
+
+	return false
+}
+
+// isInvited reports whether the user is a responsible of an organization
+// invited to the tender.
+func (m *Memory) isInvited(userID, tenderID string) bool {
+	return find(m.invitations, func(i *models.TenderInvitation) bool {
+		return i.TenderID == tenderID && m.isResponsible(userID, i.OrganizationID)
+	}) != nil
+}
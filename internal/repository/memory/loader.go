@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetTendersByIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.tenders, func(t *models.TenderResponse) bool { return contains(tenderIDs, t.ID) }), nil
+}
+
+func (m *Memory) GetTenderVersionsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := filter(m.tenderVersions, func(t *models.TenderResponse) bool { return contains(tenderIDs, t.ID) })
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+
+	return versions, nil
+}
+
+func (m *Memory) GetBidsByIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.bids, func(b *models.BidResponse) bool { return contains(bidIDs, b.ID) }), nil
+}
+
+func (m *Memory) GetBidsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bids := filter(m.bids, func(b *models.BidResponse) bool { return contains(tenderIDs, b.TenderID) })
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].CreatedAt.Before(bids[j].CreatedAt) })
+
+	return bids, nil
+}
+
+func (m *Memory) GetBidVersionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := filter(m.bidVersions, func(b *models.BidResponse) bool { return contains(bidIDs, b.ID) })
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+
+	return versions, nil
+}
+
+func (m *Memory) GetDecisionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidDecisionResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.decisions, func(d *models.BidDecisionResponse) bool { return contains(bidIDs, d.BidID) }), nil
+}
+
+func (m *Memory) GetFeedbackByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidReviewResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	messages := m.sortedMessages(func(msg *models.BidMessage) bool {
+		return contains(bidIDs, msg.BidID) && msg.Role == models.MessageRoleBuyer
+	})
+
+	reviews := make([]*models.BidReviewResponse, len(messages))
+	for i, message := range messages {
+		reviews[i] = message.Review()
+	}
+
+	return reviews, nil
+}
+
+func (m *Memory) GetEmployeesByIDs(ctx context.Context, userIDs []string) ([]*models.Employee, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.employees, func(e *models.Employee) bool { return contains(userIDs, e.ID) }), nil
+}
+
+func (m *Memory) GetEmployeesByUsernames(ctx context.Context, usernames []string) ([]*models.Employee, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.employees, func(e *models.Employee) bool { return contains(usernames, e.Username) }), nil
+}
+
+func (m *Memory) GetOrganizationsByIDs(ctx context.Context, organizationIDs []string) ([]*models.Organization, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.organizations, func(o *models.Organization) bool {
+		return contains(organizationIDs, string(o.ID))
+	}), nil
+}
+
+func (m *Memory) GetAttachmentsByIDs(ctx context.Context, attachmentIDs []string) ([]*models.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	attachments := []*models.Attachment{}
+	for _, id := range attachmentIDs {
+		if attachment := find(m.attachments, func(a *models.Attachment) bool { return a.ID == id }); attachment != nil {
+			attachments = append(attachments, clone(attachment))
+		}
+	}
+
+	return attachments, nil
+}
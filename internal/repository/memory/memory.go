@@ -0,0 +1,201 @@
+// Package memory is an in-memory repository.Repository. It follows the
+// semantics of the postgres implementation, sentinel errors included, and is
+// meant for tests and local experiments.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/google/uuid"
+)
+
+var _ repository.Repository = (*Memory)(nil)
+
+type Memory struct {
+	mu sync.RWMutex
+
+	organizations  []*models.Organization
+	employees      []*models.Employee
+	responsibles   []*seed.Responsible
+	tenders        []*models.TenderResponse
+	tenderVersions []*models.TenderResponse
+	bids           []*models.BidResponse
+	bidVersions    []*models.BidResponse
+	decisions      []*models.BidDecisionResponse
+	messages       []*models.BidMessage
+	notifications  []*models.Notification
+	tenderKeys     map[string][]byte
+	sealed         map[bidVersion][]byte
+	audit          []*models.AuditRecord
+	criteria       []*models.Criterion
+	scores         []*models.BidScore
+	questions      []*models.Question
+	attachments    []*models.Attachment
+	invitations    []*models.TenderInvitation
+	questionnaires []*models.Questionnaire
+	applications   []*models.Application
+	debarments     []*models.Debarment
+}
+
+// bidVersion identifies the current version of a bid or one of its
+// snapshots.
+type bidVersion struct {
+	bidID   string
+	version int
+}
+
+func New() *Memory {
+	return &Memory{
+		tenderKeys: make(map[string][]byte),
+		sealed:     make(map[bidVersion][]byte),
+	}
+}
+
+// Seed adds the dataset, the same way the postgres implementation does. With
+// reset everything stored before is dropped.
+func (m *Memory) Seed(ctx context.Context, d *seed.Dataset, reset bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if reset {
+		m.organizations, m.employees, m.responsibles = nil, nil, nil
+		m.tenders, m.tenderVersions = nil, nil
+		m.bids, m.bidVersions, m.decisions, m.messages = nil, nil, nil, nil
+		m.notifications = nil
+		m.tenderKeys, m.sealed, m.audit = make(map[string][]byte), make(map[bidVersion][]byte), nil
+		m.criteria, m.scores, m.questions = nil, nil, nil
+		m.attachments, m.invitations = nil, nil
+		m.questionnaires, m.applications, m.debarments = nil, nil, nil
+	}
+
+	m.organizations = append(m.organizations, copies(d.Organizations)...)
+	m.employees = append(m.employees, copies(d.Employees)...)
+	m.responsibles = append(m.responsibles, copies(d.Responsibles)...)
+	m.tenders = append(m.tenders, copies(d.Tenders)...)
+	m.tenderVersions = append(m.tenderVersions, copies(d.TenderVersions)...)
+	m.bids = append(m.bids, copies(d.Bids)...)
+	m.bidVersions = append(m.bidVersions, copies(d.BidVersions)...)
+	m.decisions = append(m.decisions, copies(d.Decisions)...)
+	m.messages = append(m.messages, copies(d.Messages)...)
+
+	return nil
+}
+
+func newID() string {
+	return uuid.NewString()
+}
+
+// now is truncated to the precision Postgres stores.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func copies[T any](items []T) []*T {
+	result := make([]*T, len(items))
+	for i := range items {
+		item := items[i]
+		result[i] = &item
+	}
+
+	return result
+}
+
+func clone[T any](item *T) *T {
+	c := *item
+	return &c
+}
+
+func filter[T any](items []*T, keep func(*T) bool) []*T {
+	result := []*T{}
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, clone(item))
+		}
+	}
+
+	return result
+}
+
+func find[T any](items []*T, match func(*T) bool) *T {
+	for _, item := range items {
+		if match(item) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func page[T any](items []*T, limit, offset int32) []*T {
+	if offset < 0 {
+		offset = 0
+	}
+	if int(offset) >= len(items) {
+		return []*T{}
+	}
+	items = items[offset:]
+
+	if limit >= 0 && int(limit) < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+func contains[T comparable](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Memory) employeeByUsername(username string) *models.Employee {
+	return find(m.employees, func(e *models.Employee) bool { return e.Username == username })
+}
+
+func (m *Memory) employeeByID(id string) *models.Employee {
+	return find(m.employees, func(e *models.Employee) bool { return e.ID == id })
+}
+
+func (m *Memory) organizationByID(id models.OrganizationID) *models.Organization {
+	return find(m.organizations, func(o *models.Organization) bool { return o.ID == id })
+}
+
+func (m *Memory) tenderByID(id string) *models.TenderResponse {
+	return find(m.tenders, func(t *models.TenderResponse) bool { return t.ID == id })
+}
+
+func (m *Memory) bidByID(id string) *models.BidResponse {
+	return find(m.bids, func(b *models.BidResponse) bool { return b.ID == id })
+}
+
+func (m *Memory) isResponsible(userID string, organizationID models.OrganizationID) bool {
+	return find(m.responsibles, func(r *seed.Responsible) bool {
+		return r.UserID == userID && r.OrganizationID == organizationID
+	}) != nil
+}
+
+// speaksForBid reports whether the user is the author of the bid or a
+// responsible of the organization it is made on behalf of.
+func (m *Memory) speaksForBid(userID string, bid *models.BidResponse) bool {
+	return bid.AuthorID == userID || bid.OrganizationID != nil && m.isResponsible(userID, *bid.OrganizationID)
+}
+
+func (m *Memory) organizationsOf(userID string) []models.OrganizationID {
+	var organizations []models.OrganizationID
+	for _, r := range m.responsibles {
+		if r.UserID == userID {
+			organizations = append(organizations, r.OrganizationID)
+		}
+	}
+
+	return organizations
+}
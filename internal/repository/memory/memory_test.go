@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/DarRo9/Tenders/internal/repository/memory"
+	"github.com/DarRo9/Tenders/internal/repository/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repository {
+		return memory.New()
+	})
+}
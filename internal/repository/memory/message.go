@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) PostBidMessage(ctx context.Context, bidID, authorID string, role models.MessageRole, message *models.BidMessageCreate) (*models.BidMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.bidByID(bidID) == nil {
+		return nil, repository.ErrBidNotFound
+	}
+
+	if message.ReplyTo != nil {
+		parent := find(m.messages, func(msg *models.BidMessage) bool { return msg.ID == *message.ReplyTo && msg.BidID == bidID })
+		if parent == nil {
+			return nil, repository.ErrMessageNotFound
+		}
+	}
+
+	msg := &models.BidMessage{
+		ID:          newID(),
+		BidID:       bidID,
+		AuthorID:    &authorID,
+		Role:        role,
+		ReplyTo:     message.ReplyTo,
+		Body:        message.Body,
+		Attachments: []*models.MessageAttachment{},
+		CreatedAt:   now(),
+	}
+	for _, attachment := range message.Attachments {
+		msg.Attachments = append(msg.Attachments, clone(attachment))
+	}
+	m.messages = append(m.messages, msg)
+
+	return cloneMessage(msg), nil
+}
+
+func (m *Memory) GetBidMessages(ctx context.Context, bidID string, limit, offset int32) ([]*models.BidMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	messages := m.sortedMessages(func(msg *models.BidMessage) bool { return msg.BidID == bidID })
+
+	return page(messages, limit, offset), nil
+}
+
+func (m *Memory) MarkBidMessagesRead(ctx context.Context, bidID, userID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	read := 0
+	for _, msg := range m.messages {
+		if msg.BidID != bidID || msg.AuthorID != nil && *msg.AuthorID == userID {
+			continue
+		}
+
+		if find(msg.ReadBy, func(r *models.ReadReceipt) bool { return r.UserID == userID }) != nil {
+			continue
+		}
+
+		msg.ReadBy = append(msg.ReadBy, &models.ReadReceipt{UserID: userID, ReadAt: now()})
+		read++
+	}
+
+	return read, nil
+}
+
+// sortedMessages returns copies of the matching messages, oldest first.
+func (m *Memory) sortedMessages(keep func(*models.BidMessage) bool) []*models.BidMessage {
+	messages := []*models.BidMessage{}
+	for _, msg := range m.messages {
+		if keep(msg) {
+			messages = append(messages, cloneMessage(msg))
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+
+	return messages
+}
+
+// cloneMessage copies a message together with its attachments and read
+// receipts, which clone would share.
+func cloneMessage(msg *models.BidMessage) *models.BidMessage {
+	c := clone(msg)
+	c.Attachments = []*models.MessageAttachment{}
+	for _, attachment := range msg.Attachments {
+		c.Attachments = append(c.Attachments, clone(attachment))
+	}
+	c.ReadBy = []*models.ReadReceipt{}
+	for _, receipt := range msg.ReadBy {
+		c.ReadBy = append(c.ReadBy, clone(receipt))
+	}
+
+	return c
+}
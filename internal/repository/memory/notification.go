@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetNotifications(ctx context.Context, userID string, limit, offset int32) ([]*models.Notification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	notifications := filter(m.notifications, func(n *models.Notification) bool { return n.RecipientID == userID })
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+
+	return page(notifications, limit, offset), nil
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) ControlOrganizationPermission(ctx context.Context, organizationID *models.OrganizationID, username string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(username)
+	switch {
+	case user == nil:
+		return repository.ErrUserNotExist
+	case organizationID == nil || !m.isResponsible(user.ID, *organizationID):
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlOrganizationPermissionByID(ctx context.Context, organizationID *models.OrganizationID, userID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	switch {
+	case m.employeeByID(userID) == nil:
+		return repository.ErrUserNotExist
+	case organizationID == nil || !m.isResponsible(userID, *organizationID):
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlBidCreationByName(ctx context.Context, bidID, creatorUsername string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(creatorUsername)
+	if user == nil {
+		return repository.ErrUserNotExist
+	}
+
+	bid := m.bidByID(bidID)
+	if bid == nil || !m.speaksForBid(user.ID, bid) {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlBidCreationByID(ctx context.Context, username string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(username)
+	if user == nil {
+		return "", repository.ErrUserNotExist
+	}
+
+	return user.ID, nil
+}
+
+func (m *Memory) ControlUserResponsibility(ctx context.Context, userId string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.employeeByID(userId) == nil {
+		return repository.ErrUserNotExist
+	}
+
+	if len(m.organizationsOf(userId)) == 0 {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlTendersCreationByName(ctx context.Context, tenderId, creatorUsername string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.employeeByUsername(creatorUsername) == nil {
+		return repository.ErrUserNotExist
+	}
+
+	tender := m.tenderByID(tenderId)
+	if tender == nil || tender.CreatorUsername != creatorUsername {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlUserResponsibilityForTender(ctx context.Context, tenderID, username string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(username)
+	if user == nil {
+		return repository.ErrUserNotExist
+	}
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil || !m.isResponsible(user.ID, tender.OrganizationID) {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
+
+func (m *Memory) ControlUserResponsibilityForAuthorBid(ctx context.Context, bidID, username string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(username)
+	if user == nil {
+		return repository.ErrUserNotExist
+	}
+
+	bid := m.bidByID(bidID)
+	if bid != nil && bid.OrganizationID != nil {
+		if m.isResponsible(user.ID, *bid.OrganizationID) {
+			return nil
+		}
+	} else if bid != nil {
+		for _, organizationID := range m.organizationsOf(user.ID) {
+			if m.isResponsible(bid.AuthorID, organizationID) {
+				return nil
+			}
+		}
+	}
+
+	return repository.ErrRelationNotExist
+}
+
+// ControlUserResponsibilityForTenderByBidID reports an unknown user as a
+// missing relation, like the postgres query does.
+func (m *Memory) ControlUserResponsibilityForTenderByBidID(ctx context.Context, bidID, username string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user := m.employeeByUsername(username)
+	bid := m.bidByID(bidID)
+	if user == nil || bid == nil {
+		return repository.ErrRelationNotExist
+	}
+
+	tender := m.tenderByID(bid.TenderID)
+	if tender == nil || !m.isResponsible(user.ID, tender.OrganizationID) {
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
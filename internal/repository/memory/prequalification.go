@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) SetQuestionnaire(ctx context.Context, set *models.QuestionnaireSet) (*models.Questionnaire, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.organizationByID(set.OrganizationID) == nil {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+
+	questionnaire := m.questionnaireOf(set.OrganizationID, set.ServiceType)
+	if questionnaire == nil {
+		questionnaire = &models.Questionnaire{
+			ID:             newID(),
+			OrganizationID: set.OrganizationID,
+			ServiceType:    set.ServiceType,
+			CreatedAt:      now(),
+		}
+		m.questionnaires = append(m.questionnaires, questionnaire)
+	}
+
+	applied := find(m.applications, func(a *models.Application) bool { return a.QuestionnaireID == questionnaire.ID })
+	if applied != nil {
+		return nil, repository.ErrQuestionnaireLocked
+	}
+
+	questions := make([]*models.PrequalificationQuestion, len(set.Questions))
+	for i, question := range set.Questions {
+		questions[i] = &models.PrequalificationQuestion{
+			ID:         newID(),
+			Text:       question.Text,
+			AnswerType: question.AnswerType,
+			Required:   question.Required,
+		}
+	}
+	questionnaire.Questions = questions
+	questionnaire.UpdatedAt = now()
+
+	return clone(questionnaire), nil
+}
+
+func (m *Memory) GetQuestionnaire(ctx context.Context, questionnaireID string) (*models.Questionnaire, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	questionnaire := find(m.questionnaires, func(q *models.Questionnaire) bool { return q.ID == questionnaireID })
+	if questionnaire == nil {
+		return nil, repository.ErrQuestionnaireNotFound
+	}
+
+	return clone(questionnaire), nil
+}
+
+func (m *Memory) FindQuestionnaire(ctx context.Context, organizationID models.OrganizationID, serviceType models.TenderServiceType) (*models.Questionnaire, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	questionnaire := m.questionnaireOf(organizationID, serviceType)
+	if questionnaire == nil {
+		return nil, repository.ErrQuestionnaireNotFound
+	}
+
+	return clone(questionnaire), nil
+}
+
+func (m *Memory) SubmitApplication(ctx context.Context, questionnaireID, userID string, create *models.ApplicationCreate) (*models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	questionnaire := find(m.questionnaires, func(q *models.Questionnaire) bool { return q.ID == questionnaireID })
+	if questionnaire == nil {
+		return nil, repository.ErrQuestionnaireNotFound
+	}
+
+	if m.organizationByID(create.OrganizationID) == nil {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+
+	pending := find(m.applications, func(a *models.Application) bool {
+		return a.QuestionnaireID == questionnaireID && a.OrganizationID == create.OrganizationID &&
+			a.Status == models.ApplicationStatusSubmitted
+	})
+	if pending != nil {
+		return nil, repository.ErrApplicationPending
+	}
+
+	// The answers follow the order of the questions, like the postgres
+	// implementation returns them.
+	answers := []*models.Answer{}
+	for _, question := range questionnaire.Questions {
+		for _, answer := range create.Answers {
+			if answer.QuestionID == question.ID {
+				answers = append(answers, &models.Answer{QuestionID: answer.QuestionID, Value: answer.Value})
+			}
+		}
+	}
+
+	application := &models.Application{
+		ID:              newID(),
+		QuestionnaireID: questionnaireID,
+		OrganizationID:  create.OrganizationID,
+		SubmittedBy:     userID,
+		SubmittedAt:     now(),
+		Status:          models.ApplicationStatusSubmitted,
+		Answers:         answers,
+	}
+	m.applications = append(m.applications, application)
+
+	return clone(application), nil
+}
+
+func (m *Memory) GetApplication(ctx context.Context, applicationID string) (*models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	application := m.applicationByID(applicationID)
+	if application == nil {
+		return nil, repository.ErrApplicationNotFound
+	}
+
+	return clone(application), nil
+}
+
+func (m *Memory) GetApplicationsOfQuestionnaire(ctx context.Context, questionnaireID string, limit, offset int32) ([]*models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return page(m.latestApplications(func(a *models.Application) bool {
+		return a.QuestionnaireID == questionnaireID
+	}), limit, offset), nil
+}
+
+func (m *Memory) GetApplicationsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return page(m.latestApplications(func(a *models.Application) bool {
+		return m.isResponsible(userID, a.OrganizationID)
+	}), limit, offset), nil
+}
+
+func (m *Memory) ReviewApplication(ctx context.Context, applicationID, userID string, review *models.ApplicationReview) (*models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	application := m.applicationByID(applicationID)
+	switch {
+	case application == nil:
+		return nil, repository.ErrApplicationNotFound
+	case application.Status != models.ApplicationStatusSubmitted:
+		return nil, repository.ErrApplicationNotPending
+	}
+
+	reviewedAt := now()
+	application.Status = models.ApplicationStatus(review.Decision)
+	application.ReviewedBy = userID
+	application.ReviewedAt = &reviewedAt
+	application.ReviewComment = review.Comment
+	if review.Decision == models.ApplicationDecisionApproved {
+		validUntil := review.ValidUntil.UTC().Truncate(time.Microsecond)
+		application.ValidUntil = &validUntil
+	}
+
+	return clone(application), nil
+}
+
+func (m *Memory) RevokeApplication(ctx context.Context, applicationID, userID, reason string) (*models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	application := m.applicationByID(applicationID)
+	switch {
+	case application == nil:
+		return nil, repository.ErrApplicationNotFound
+	case application.Status != models.ApplicationStatusApproved:
+		return nil, repository.ErrApplicationNotRevocable
+	}
+
+	revokedAt := now()
+	application.Status = models.ApplicationStatusRevoked
+	application.RevokedBy = userID
+	application.RevokedAt = &revokedAt
+	application.RevocationReason = reason
+
+	return clone(application), nil
+}
+
+func (m *Memory) AttachToApplication(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	application := m.applicationByID(*attachment.ApplicationID)
+	switch {
+	case application == nil:
+		return nil, repository.ErrApplicationNotFound
+	case application.Status != models.ApplicationStatusSubmitted:
+		return nil, repository.ErrApplicationNotPending
+	}
+
+	return clone(m.addAttachment(attachment)), nil
+}
+
+func (m *Memory) GetApplicationLicenses(ctx context.Context, applicationID string) ([]*models.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return filter(m.attachments, func(a *models.Attachment) bool {
+		return a.ApplicationID != nil && *a.ApplicationID == applicationID
+	}), nil
+}
+
+func (m *Memory) ControlPrequalification(ctx context.Context, tenderID string, organizationID models.OrganizationID) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return repository.ErrNotPrequalified
+	}
+
+	questionnaire := m.questionnaireOf(tender.OrganizationID, tender.ServiceType)
+	if questionnaire == nil {
+		return repository.ErrNotPrequalified
+	}
+
+	approved := find(m.applications, func(a *models.Application) bool {
+		return a.QuestionnaireID == questionnaire.ID && a.OrganizationID == organizationID &&
+			a.Status == models.ApplicationStatusApproved && a.ValidUntil.After(time.Now())
+	})
+	if approved == nil {
+		return repository.ErrNotPrequalified
+	}
+
+	return nil
+}
+
+func (m *Memory) questionnaireOf(organizationID models.OrganizationID, serviceType models.TenderServiceType) *models.Questionnaire {
+	return find(m.questionnaires, func(q *models.Questionnaire) bool {
+		return q.OrganizationID == organizationID && q.ServiceType == serviceType
+	})
+}
+
+func (m *Memory) applicationByID(id string) *models.Application {
+	return find(m.applications, func(a *models.Application) bool { return a.ID == id })
+}
+
+// latestApplications returns the matching applications, the latest first.
+func (m *Memory) latestApplications(keep func(*models.Application) bool) []*models.Application {
+	applications := filter(m.applications, keep)
+	sort.SliceStable(applications, func(i, j int) bool {
+		return applications[i].SubmittedAt.After(applications[j].SubmittedAt)
+	})
+
+	return applications
+}
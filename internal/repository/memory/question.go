@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) AskQuestion(ctx context.Context, tenderID, askerID, question string) (*models.Question, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tenderByID(tenderID) == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	q := &models.Question{
+		ID:        newID(),
+		TenderID:  tenderID,
+		AskerID:   askerID,
+		Question:  question,
+		CreatedAt: now(),
+	}
+	m.questions = append(m.questions, q)
+
+	return clone(q), nil
+}
+
+func (m *Memory) GetQuestion(ctx context.Context, tenderID, questionID string) (*models.Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	question := find(m.questions, func(q *models.Question) bool { return q.ID == questionID && q.TenderID == tenderID })
+	if question == nil {
+		return nil, repository.ErrQuestionNotFound
+	}
+
+	return clone(question), nil
+}
+
+func (m *Memory) GetQuestions(ctx context.Context, tenderID, userID string, all bool, limit, offset int32) ([]*models.Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	questions := filter(m.questions, func(q *models.Question) bool {
+		return q.TenderID == tenderID && (all || q.AskerID == userID ||
+			q.Visibility != nil && *q.Visibility == models.QuestionVisibilityPublic)
+	})
+
+	return page(questions, limit, offset), nil
+}
+
+func (m *Memory) AnswerQuestion(ctx context.Context, tenderID, questionID, userID string, answer *models.QuestionAnswer, amendedVersion *int32) (*models.Question, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	question := find(m.questions, func(q *models.Question) bool { return q.ID == questionID && q.TenderID == tenderID })
+	if question == nil {
+		return nil, repository.ErrQuestionNotFound
+	}
+
+	if question.Answer != nil {
+		return nil, repository.ErrQuestionAnswered
+	}
+
+	answeredAt := now()
+	text, visibility := answer.Answer, answer.Visibility
+	question.Answer, question.Visibility, question.AnsweredAt = &text, &visibility, &answeredAt
+	question.AmendedVersion = amendedVersion
+
+	recipients := []string{question.AskerID}
+	if visibility == models.QuestionVisibilityPublic {
+		for _, bid := range m.bids {
+			if bid.TenderID == tenderID && !contains(recipients, bid.AuthorID) &&
+				(bid.Status == models.BidStatusCreated || bid.Status == models.BidStatusPublished) {
+				recipients = append(recipients, bid.AuthorID)
+			}
+		}
+	}
+
+	for _, recipient := range recipients {
+		m.notifications = append(m.notifications, &models.Notification{
+			ID:          newID(),
+			RecipientID: recipient,
+			Kind:        models.NotificationKindQuestionAnswered,
+			TenderID:    tenderID,
+			Message:     text,
+			CreatedAt:   answeredAt,
+		})
+	}
+
+	return clone(question), nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetTenderKey(ctx context.Context, tenderID string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wrappedKey, ok := m.tenderKeys[tenderID]
+	if !ok {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	return wrappedKey, nil
+}
+
+func (m *Memory) ChangeSealedBid(ctx context.Context, bidID string, sealedContent []byte) (*models.BidResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bid := m.bidByID(bidID)
+	if bid == nil {
+		return nil, repository.ErrBidNotFound
+	}
+	m.bidVersions = append(m.bidVersions, clone(bid))
+
+	bid.Version++
+	bid.Sealed = sealedContent != nil
+	if sealedContent != nil {
+		m.sealed[bidVersion{bidID, bid.Version}] = sealedContent
+	}
+
+	return clone(bid), nil
+}
+
+func (m *Memory) GetSealedBidContents(ctx context.Context, tenderID string) ([]*models.SealedContent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	contents := []*models.SealedContent{}
+	for _, versions := range [][]*models.BidResponse{m.bids, m.bidVersions} {
+		for _, bid := range versions {
+			content, ok := m.sealed[bidVersion{bid.ID, bid.Version}]
+			if bid.TenderID != tenderID || !bid.Sealed || !ok {
+				continue
+			}
+
+			contents = append(contents, &models.SealedContent{BidID: bid.ID, Version: bid.Version, Content: content})
+		}
+	}
+
+	return contents, nil
+}
+
+// RevealTender checks that bids cover every sealed version before changing
+// anything, so that a failed reveal leaves the tender sealed.
+func (m *Memory) RevealTender(ctx context.Context, tenderID, username string, bids []*models.RevealedBid) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	revealedAt := now()
+	if tender == nil || !tender.Sealed || tender.RevealedAt != nil ||
+		tender.SubmissionDeadline == nil || tender.SubmissionDeadline.After(revealedAt) {
+		return nil, repository.ErrTenderNotRevealable
+	}
+
+	revealed := make(map[bidVersion]*models.RevealedBid, len(bids))
+	for _, bid := range bids {
+		revealed[bidVersion{bid.BidID, bid.Version}] = bid
+	}
+
+	var sealedBids []*models.BidResponse
+	for _, versions := range [][]*models.BidResponse{m.bids, m.bidVersions} {
+		for _, bid := range versions {
+			if bid.TenderID != tenderID || !bid.Sealed {
+				continue
+			}
+			if revealed[bidVersion{bid.ID, bid.Version}] == nil {
+				return nil, fmt.Errorf("tender %s still has sealed bids after the reveal", tenderID)
+			}
+
+			sealedBids = append(sealedBids, bid)
+		}
+	}
+
+	for _, bid := range sealedBids {
+		key := bidVersion{bid.ID, bid.Version}
+		bid.Name, bid.Description, bid.Sealed = revealed[key].Name, revealed[key].Description, false
+		delete(m.sealed, key)
+	}
+	tender.RevealedAt = &revealedAt
+
+	record := &models.AuditRecord{
+		ID:        newID(),
+		Action:    models.AuditActionTenderRevealed,
+		TenderID:  tenderID,
+		Details:   "sealed bids revealed: " + strconv.Itoa(countBids(bids)),
+		CreatedAt: revealedAt,
+	}
+	if actor := m.employeeByUsername(username); actor != nil {
+		record.ActorID = &actor.ID
+	}
+	m.audit = append(m.audit, record)
+
+	return clone(tender), nil
+}
+
+// countBids counts the bids behind the revealed versions.
+func countBids(bids []*models.RevealedBid) int {
+	ids := make(map[string]struct{}, len(bids))
+	for _, bid := range bids {
+		ids[bid.BidID] = struct{}{}
+	}
+
+	return len(ids)
+}
@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (m *Memory) GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenders := filter(m.tenders, func(t *models.TenderResponse) bool { return t.CreatorUsername == username })
+	sort.SliceStable(tenders, func(i, j int) bool { return tenders[i].Name < tenders[j].Name })
+
+	return page(tenders, limit, offset), nil
+}
+
+func (m *Memory) GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, userID string, limit, offset int32) ([]*models.TenderResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenders := filter(m.tenders, func(t *models.TenderResponse) bool {
+		return t.Status == models.TenderStatusPublished &&
+			(len(serviceType) == 0 || contains(serviceType, t.ServiceType)) &&
+			m.isVisible(t, userID)
+	})
+	sort.SliceStable(tenders, func(i, j int) bool { return tenders[i].Name < tenders[j].Name })
+
+	return page(tenders, limit, offset), nil
+}
+
+func (m *Memory) GetStatusOfTender(ctx context.Context, tenderID string) (*models.TenderStatus, *models.OrganizationID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, nil, repository.ErrTenderNotFound
+	}
+
+	status, organizationID := tender.Status, tender.OrganizationID
+
+	return &status, &organizationID, nil
+}
+
+func (m *Memory) BuildTender(ctx context.Context, tender *models.TenderCreate, wrappedKey []byte) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.organizationByID(tender.OrganizationID) == nil {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+
+	tenderResp := &models.TenderResponse{
+		ID:                 newID(),
+		Name:               tender.Name,
+		Description:        tender.Description,
+		ServiceType:        tender.ServiceType,
+		Status:             models.TenderStatusCreated,
+		OrganizationID:     tender.OrganizationID,
+		Version:            1,
+		CreatedAt:          now(),
+		CreatorUsername:    tender.CreatorUsername,
+		SubmissionDeadline: tender.SubmissionDeadline,
+		Sealed:             tender.Sealed,
+		Visibility:         tender.Visibility,
+		Prequalification:   tender.Prequalification,
+	}
+	if tender.Auction != nil {
+		auction := *tender.Auction
+		tenderResp.Auction = &auction
+	}
+
+	for _, organizationID := range tender.InvitedOrganizations {
+		if m.organizationByID(organizationID) == nil {
+			return nil, repository.ErrOrganizationDepencyNotFound
+		}
+	}
+
+	m.tenders = append(m.tenders, tenderResp)
+	m.invite(tenderResp.ID, tender.InvitedOrganizations)
+
+	if wrappedKey != nil {
+		m.tenderKeys[tenderResp.ID] = wrappedKey
+	}
+
+	return clone(tenderResp), nil
+}
+
+func (m *Memory) RefreshTenderStatus(ctx context.Context, tenderID string, status models.TenderStatus) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+	tender.Status = status
+
+	return clone(tender), nil
+}
+
+func (m *Memory) IsTenderPudlished(ctx context.Context, tenderID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return repository.ErrTenderNotFound
+	}
+
+	if tender.Status != models.TenderStatusPublished {
+		return repository.ErrTenderClosed
+	}
+
+	return nil
+}
+
+func (m *Memory) UpdateTender(ctx context.Context, tenderID string, tenderEdit *models.TenderEdit) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+	m.tenderVersions = append(m.tenderVersions, snapshotTender(tender))
+
+	if tenderEdit.Name != nil {
+		tender.Name = *tenderEdit.Name
+	}
+	if tenderEdit.Description != nil {
+		tender.Description = *tenderEdit.Description
+	}
+	if tenderEdit.ServiceType != nil {
+		tender.ServiceType = *tenderEdit.ServiceType
+	}
+	tender.Version++
+
+	return clone(tender), nil
+}
+
+func (m *Memory) RollbackTender(ctx context.Context, tenderID string, version int32) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	previous := find(m.tenderVersions, func(t *models.TenderResponse) bool {
+		return t.ID == tenderID && t.Version == int(version)
+	})
+	if previous == nil {
+		return nil, repository.ErrTenderORVersionNotFound
+	}
+	m.tenderVersions = append(m.tenderVersions, snapshotTender(tender))
+
+	current := *tender
+	*tender = *previous
+	tender.Version = current.Version + 1
+	tender.SubmissionDeadline, tender.RevealedAt, tender.Auction = current.SubmissionDeadline, current.RevealedAt, current.Auction
+	tender.Visibility, tender.Prequalification = current.Visibility, current.Prequalification
+
+	return clone(tender), nil
+}
+
+func (m *Memory) CountOpenTendersByServiceType(ctx context.Context) (map[models.TenderServiceType]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[models.TenderServiceType]int)
+	for _, tender := range m.tenders {
+		if tender.Status == models.TenderStatusPublished {
+			counts[tender.ServiceType]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (m *Memory) CancelTender(ctx context.Context, tenderID, reason string) (*models.TenderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tender := m.tenderByID(tenderID)
+	if tender == nil {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	if tender.Status != models.TenderStatusCreated && tender.Status != models.TenderStatusPublished {
+		return nil, repository.ErrTenderNotCancellable
+	}
+	m.tenderVersions = append(m.tenderVersions, snapshotTender(tender))
+
+	tender.Status = models.TenderStatusCancelled
+	tender.CancellationReason = reason
+	tender.Version++
+
+	for _, bid := range m.bids {
+		if bid.TenderID != tenderID ||
+			(bid.Status != models.BidStatusCreated && bid.Status != models.BidStatusPublished) {
+			continue
+		}
+
+		bid.Status = models.BidStatusTenderCancelled
+		for _, recipientID := range m.bidManagers(bid) {
+			m.notifications = append(m.notifications, &models.Notification{
+				ID:          newID(),
+				RecipientID: recipientID,
+				Kind:        models.NotificationKindTenderCancelled,
+				TenderID:    tenderID,
+				BidID:       bid.ID,
+				Message:     reason,
+				CreatedAt:   now(),
+			})
+		}
+	}
+
+	return clone(tender), nil
+}
+
+// bidManagers are the author of the bid and, for an organization bid, every
+// responsible of the organization.
+func (m *Memory) bidManagers(bid *models.BidResponse) []string {
+	managers := []string{bid.AuthorID}
+	if bid.OrganizationID == nil {
+		return managers
+	}
+
+	for _, r := range m.responsibles {
+		if r.OrganizationID == *bid.OrganizationID && r.UserID != bid.AuthorID {
+			managers = append(managers, r.UserID)
+		}
+	}
+
+	return managers
+}
+
+// snapshotTender copies the tender for tender_version, which does not track
+// the reveal, the auction, the visibility and the prequalification.
+func snapshotTender(tender *models.TenderResponse) *models.TenderResponse {
+	snapshot := clone(tender)
+	snapshot.RevealedAt, snapshot.Auction = nil, nil
+	snapshot.Visibility, snapshot.Prequalification = "", false
+
+	return snapshot
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// attachmentColumns lists the attachment columns in the order
+// scanAttachment reads them.
+const attachmentColumns = `id, COALESCE(tender_id::text, ''), bid_id, application_id, name, content_type, size, sha256, storage_key, COALESCE(uploaded_by::text, ''), created_at,
+	scan_status, scan_signature, scanned_at`
+
+func scanAttachment(row pgx.Row, attachment *models.Attachment) error {
+	return row.Scan(
+		&attachment.ID, &attachment.TenderID, &attachment.BidID, &attachment.ApplicationID, &attachment.Name, &attachment.ContentType,
+		&attachment.Size, &attachment.SHA256, &attachment.StorageKey, &attachment.UploadedBy, &attachment.CreatedAt,
+		&attachment.ScanStatus, &attachment.ScanSignature, &attachment.ScannedAt)
+}
+
+// AttachToTender stores the attachment and adds it to a new version of the
+// tender.
+func (p *Postgres) AttachToTender(ctx context.Context, attachment *models.Attachment) (result *models.Attachment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	result, err = insertAttachment(ctx, tx, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = newTenderVersion(ctx, tx, attachment.TenderID, `array_append(attachment_ids, $2::uuid)`, result.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DetachFromTender removes the attachment from a new version of the tender.
+// The file stays with the older versions.
+func (p *Postgres) DetachFromTender(ctx context.Context, tenderID, attachmentID string) (tender *models.TenderResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	return newTenderVersion(ctx, tx, tenderID, `array_remove(attachment_ids, $2::uuid)`, attachmentID)
+}
+
+// AttachToBid stores the attachment and adds it to a new version of the bid.
+func (p *Postgres) AttachToBid(ctx context.Context, attachment *models.Attachment) (result *models.Attachment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	result, err = insertAttachment(ctx, tx, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = newBidVersion(ctx, tx, *attachment.BidID, `array_append(attachment_ids, $2::uuid)`, result.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DetachFromBid removes the attachment from a new version of the bid.
+func (p *Postgres) DetachFromBid(ctx context.Context, bidID, attachmentID string) (bid *models.BidResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	return newBidVersion(ctx, tx, bidID, `array_remove(attachment_ids, $2::uuid)`, attachmentID)
+}
+
+func (p *Postgres) GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := scanAttachment(p.DB.QueryRow(ctx, `
+	SELECT `+attachmentColumns+`
+	FROM attachment
+		WHERE id = $1;`, attachmentID), attachment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetQuarantinedAttachments returns the oldest attachments waiting for their
+// scan first.
+func (p *Postgres) GetQuarantinedAttachments(ctx context.Context, limit int32) ([]*models.Attachment, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+attachmentColumns+`
+	FROM attachment
+		WHERE scan_status = 'Quarantined'
+	ORDER BY created_at
+	LIMIT $1;`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		attachment := &models.Attachment{}
+		if err := scanAttachment(rows, attachment); err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// RecordAttachmentScan stores the outcome of the scan of a quarantined
+// attachment. An attachment scanned already is ErrAttachmentNotFound.
+func (p *Postgres) RecordAttachmentScan(ctx context.Context, attachmentID string, scan *models.AttachmentScan) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := scanAttachment(p.DB.QueryRow(ctx, `
+	UPDATE attachment
+	SET
+		scan_status = $2,
+		scan_signature = $3,
+		scanned_at = NOW()
+	WHERE id = $1
+		AND scan_status = 'Quarantined'
+	RETURNING `+attachmentColumns+`;`, attachmentID, scan.Status, scan.Signature), attachment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func insertAttachment(ctx context.Context, tx pgx.Tx, attachment *models.Attachment) (*models.Attachment, error) {
+	result := &models.Attachment{}
+	err := scanAttachment(tx.QueryRow(ctx, `
+	INSERT INTO attachment
+		(tender_id, bid_id, application_id, name, content_type, size, sha256, storage_key, uploaded_by)
+	VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING `+attachmentColumns+`;`,
+		attachment.TenderID, attachment.BidID, attachment.ApplicationID, attachment.Name, attachment.ContentType,
+		attachment.Size, attachment.SHA256, attachment.StorageKey, attachment.UploadedBy), result)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		switch {
+		case attachment.ApplicationID != nil:
+			return nil, repository.ErrApplicationNotFound
+		case attachment.BidID != nil:
+			return nil, repository.ErrBidNotFound
+		}
+		return nil, repository.ErrTenderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newTenderVersion snapshots the tender and sets its attachments to the
+// given expression of the current ones and the attachment ID. Removing an
+// attachment the tender does not have is ErrAttachmentNotFound.
+func newTenderVersion(ctx context.Context, tx pgx.Tx, tenderID, attachments, attachmentID string) (*models.TenderResponse, error) {
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO tender_version
+		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids)
+	SELECT
+		id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids
+	FROM tender
+	WHERE id = $1;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	if pgCmd.RowsAffected() == 0 {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	tender := &models.TenderResponse{}
+	err = scanTender(tx.QueryRow(ctx, `
+	UPDATE tender
+	SET
+		attachment_ids = `+attachments+`,
+		version = version + 1
+	WHERE id = $1
+		AND `+attachments+` IS DISTINCT FROM attachment_ids
+	RETURNING `+tenderColumns+`;`, tenderID, attachmentID), tender)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+// newBidVersion is newTenderVersion for bids.
+func newBidVersion(ctx context.Context, tx pgx.Tx, bidID, attachments, attachmentID string) (*models.BidResponse, error) {
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO bid_version
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids)
+	SELECT
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
+	FROM bid
+	WHERE id = $1;`, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if pgCmd.RowsAffected() == 0 {
+		return nil, repository.ErrBidNotFound
+	}
+
+	bid := &models.BidResponse{}
+	err = scanBid(tx.QueryRow(ctx, `
+	UPDATE bid
+	SET
+		attachment_ids = `+attachments+`,
+		version = version + 1
+	WHERE id = $1
+		AND `+attachments+` IS DISTINCT FROM attachment_ids
+	RETURNING `+bidColumns+`;`, bidID, attachmentID), bid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
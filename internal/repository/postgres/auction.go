@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// SubmitAuctionPrice lowers the price of a bid by at least the step of its
+// auction. The previous price is kept as a version.
+func (p *Postgres) SubmitAuctionPrice(ctx context.Context, bidID string, price float64) (bid *models.BidResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+	SELECT true
+	FROM bid
+		WHERE id = $1
+	FOR UPDATE;`, bidID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrBidNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO bid_version 
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids) 
+	SELECT
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
+	FROM bid
+	WHERE id = $1;`, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	bid = &models.BidResponse{}
+	err = scanBid(tx.QueryRow(ctx, `
+	WITH updated AS (
+		UPDATE bid b
+		SET
+			price = $2,
+			priced_at = NOW(),
+			version = b.version + 1
+		FROM tender t
+		WHERE b.id = $1
+			AND t.id = b.tender_id
+			AND (b.price IS NULL OR b.price - $2 >= t.auction_step)
+		RETURNING b.*
+	)
+	SELECT `+bidColumns+` FROM updated;`, bidID, price), bid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrPriceStepTooSmall
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+// ExtendAuction pushes the end of a running auction back when it is closer
+// than the extension, so that a last second price drop can be answered.
+func (p *Postgres) ExtendAuction(ctx context.Context, tenderID string) (bool, error) {
+	pgCmd, err := p.DB.Exec(ctx, `
+	UPDATE tender
+	SET submission_deadline = NOW() + make_interval(secs => auction_extension_seconds)
+	WHERE id = $1
+		AND auction_start IS NOT NULL
+		AND status = 'Published'
+		AND submission_deadline > NOW()
+		AND submission_deadline < NOW() + make_interval(secs => auction_extension_seconds);`, tenderID)
+	if err != nil {
+		return false, err
+	}
+
+	return pgCmd.RowsAffected() != 0, nil
+}
+
+func (p *Postgres) GetAuctionRanking(ctx context.Context, tenderID, userID string) ([]*models.AuctionRank, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		ROW_NUMBER() OVER (ORDER BY b.price ASC, b.priced_at ASC, b.id ASC),
+		b.price,
+		b.priced_at,
+		b.author_id = $2 OR EXISTS (
+			SELECT 1
+			FROM organization_responsible r
+			WHERE r.organization_id = b.organization_id AND r.user_id = $2
+		)
+	FROM bid b
+		WHERE b.tender_id = $1
+		AND b.status IN ('Published', 'Approved')
+		AND b.price IS NOT NULL
+	ORDER BY 1;`, tenderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ranking := []*models.AuctionRank{}
+	for rows.Next() {
+		rank := &models.AuctionRank{}
+		if err := rows.Scan(&rank.Rank, &rank.Price, &rank.PricedAt, &rank.Own); err != nil {
+			return nil, err
+		}
+
+		ranking = append(ranking, rank)
+	}
+
+	return ranking, rows.Err()
+}
+
+// CloseEndedAuctions awards every published auction past its end to the
+// lowest price, the earliest one on a tie, and closes the tender. Debarred
+// suppliers are passed over. Auctions being closed by another replica are
+// skipped.
+func (p *Postgres) CloseEndedAuctions(ctx context.Context) (results []*models.AuctionResult, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, `
+	SELECT id
+	FROM tender
+		WHERE auction_start IS NOT NULL
+		AND status = 'Published'
+		AND submission_deadline <= NOW()
+	FOR UPDATE SKIP LOCKED;`)
+	if err != nil {
+		return nil, err
+	}
+
+	var tenderIDs []string
+	for rows.Next() {
+		var tenderID string
+		if err := rows.Scan(&tenderID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		tenderIDs = append(tenderIDs, tenderID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results = []*models.AuctionResult{}
+	for _, tenderID := range tenderIDs {
+		result := &models.AuctionResult{TenderID: tenderID}
+
+		var price float64
+		err = tx.QueryRow(ctx, `
+		UPDATE bid
+		SET status = 'Approved'
+		WHERE id = (
+			SELECT id
+			FROM bid
+				WHERE tender_id = $1
+				AND status = 'Published'
+				AND price IS NOT NULL
+				AND NOT EXISTS (
+					SELECT 1
+					FROM debarment
+						WHERE `+debarmentBlocks("$1", "bid.organization_id", "bid.author_id")+`
+				)
+			ORDER BY price ASC, priced_at ASC, id ASC
+			LIMIT 1
+		)
+		RETURNING id, price;`, tenderID).Scan(&result.BidID, &price)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		details := "no bids"
+		if result.BidID != "" {
+			details = "winning price: " + strconv.FormatFloat(price, 'f', 2, 64)
+		}
+
+		_, err = tx.Exec(ctx, `
+		UPDATE tender
+		SET status = 'Closed'
+		WHERE id = $1;`, tenderID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, `
+		INSERT INTO audit_log (action, tender_id, bid_id, details)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4);`, models.AuditActionAuctionClosed, tenderID, result.BidID, details)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
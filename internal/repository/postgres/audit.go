@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (p *Postgres) GetAuditLog(ctx context.Context, tenderID string, limit, offset int32) ([]*models.AuditRecord, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, actor_id, action, COALESCE(tender_id::text, ''), COALESCE(bid_id::text, ''), details, created_at
+	FROM audit_log
+		WHERE tender_id = $1
+	ORDER BY created_at ASC
+	LIMIT $2 OFFSET $3;`, tenderID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*models.AuditRecord{}
+	for rows.Next() {
+		record := &models.AuditRecord{}
+		if err := rows.Scan(
+			&record.ID, &record.ActorID, &record.Action, &record.TenderID,
+			&record.BidID, &record.Details, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
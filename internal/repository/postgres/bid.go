@@ -12,17 +12,25 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (p *Postgres) ConstructBid(ctx context.Context, bid *models.BidCreate) (*models.BidResponse, error) {
+// ConstructBid stores the sealed content instead of the name and description
+// of a bid on a sealed tender. Such a bid takes the ID it was sealed for.
+func (p *Postgres) ConstructBid(ctx context.Context, bid *models.BidCreate, sealed *models.SealedContent) (*models.BidResponse, error) {
 	bidResp := &models.BidResponse{}
 
-	err := p.DB.QueryRow(ctx, `
+	name, description := bid.Name, bid.Description
+	var id *string
+	var sealedContent []byte
+	if sealed != nil {
+		name, description = "", ""
+		id, sealedContent = &sealed.BidID, sealed.Content
+	}
+
+	err := scanBid(p.DB.QueryRow(ctx, `
 	insert into bid 
-		(name, description, tender_id, author_type, author_id)
+		(id, name, description, tender_id, author_type, author_id, organization_id, sealed_content, price, priced_at)
 	values 
-    	($1, $2, $3, $4, $5) 
-	returning *;`, bid.Name, bid.Description, bid.TenderID, bid.AuthorType, bid.AuthorId).Scan(
-		&bidResp.ID, &bidResp.Name, &bidResp.Description, &bidResp.Status, &bidResp.TenderID,
-		&bidResp.AuthorType, &bidResp.AuthorID, &bidResp.Version, &bidResp.CreatedAt)
+    	(COALESCE($9::uuid, uuid_generate_v4()), $1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $8::numeric IS NOT NULL THEN NOW() END) 
+	returning `+bidColumns+`;`, name, description, bid.TenderID, bid.AuthorType, bid.AuthorId, bid.OrganizationID, sealedContent, bid.Price, id), bidResp)
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
@@ -54,9 +62,11 @@ func (p *Postgres) ChangeBid(ctx context.Context, bidID string, bidEdit *models.
 
 	pgCmd, err := tx.Exec(ctx, `
 	INSERT INTO bid_version 
-		(bid_id, name, description, status, tender_id, author_type, author_id, version, created_at) 
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids) 
 	SELECT
-		id, name, description, status, tender_id, author_type, author_id, version, created_at
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
 	FROM bid
 	WHERE id = $1;`, bidID)
 	if pgCmd.RowsAffected() == 0 {
@@ -77,12 +87,10 @@ func (p *Postgres) ChangeBid(ctx context.Context, bidID string, bidEdit *models.
 	}
 
 	values = append(values, bidID)
-	query := fmt.Sprintf(`update bid set %s, version = version + 1 where id = $%v returning *;`, strings.Join(keys, ", "), len(values))
+	query := fmt.Sprintf(`update bid set %s, version = version + 1 where id = $%v returning `+bidColumns+`;`, strings.Join(keys, ", "), len(values))
 
 	bid := &models.BidResponse{}
-	err = tx.QueryRow(ctx, query, values...).Scan(
-		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-		&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt)
+	err = scanBid(tx.QueryRow(ctx, query, values...), bid)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrBidNotFound
 	}
@@ -106,9 +114,11 @@ func (p *Postgres) CancelChangesOfBid(ctx context.Context, bidID string, version
 
 	pgCmd, err := tx.Exec(ctx, `
 	INSERT INTO bid_version 
-		(bid_id, name, description, status, tender_id, author_type, author_id, version, created_at) 
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids) 
 	SELECT
-		id, name, description, status, tender_id, author_type, author_id, version, created_at
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
 	FROM bid
 	WHERE id = $1;`, bidID)
 	if pgCmd.RowsAffected() == 0 {
@@ -116,28 +126,35 @@ func (p *Postgres) CancelChangesOfBid(ctx context.Context, bidID string, version
 	}
 
 	bid := &models.BidResponse{}
-	err = tx.QueryRow(ctx, `
+	err = scanBid(tx.QueryRow(ctx, `
 	WITH bv AS (
 		SELECT
-			name, description, status, tender_id, author_type, author_id, version, created_at
+			name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+			sealed_content, price, priced_at, attachment_ids
 		FROM bid_version
 		WHERE bid_id = $1 AND version = $2
+	), updated AS (
+		UPDATE bid b
+		SET
+			name = bv.name,
+			description = bv.description,
+			status = bv.status,
+			tender_id = bv.tender_id,
+			author_type = bv.author_type,
+			author_id = bv.author_id,
+			organization_id = bv.organization_id,
+			version = b.version + 1,
+			created_at = bv.created_at,
+			withdrawal_reason = bv.withdrawal_reason,
+			sealed_content = bv.sealed_content,
+			price = bv.price,
+			priced_at = bv.priced_at,
+			attachment_ids = bv.attachment_ids
+		FROM bv
+			WHERE b.id = $1
+		RETURNING b.*
 	)
-	UPDATE bid b
-	SET
-		name = bv.name,
-		description = bv.description,
-		status = bv.status,
-		tender_id = bv.tender_id,
-		author_type = bv.author_type,
-		author_id = bv.author_id,
-		version = b.version + 1,
-		created_at = bv.created_at
-	FROM bv
-		WHERE b.id = $1
-	RETURNING b.*;`, bidID, version).Scan(
-		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-		&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt)
+	SELECT `+bidColumns+` FROM updated;`, bidID, version), bid)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrBidORVersionNotFound
 	}
@@ -148,7 +165,7 @@ func (p *Postgres) CancelChangesOfBid(ctx context.Context, bidID string, version
 
 func (p *Postgres) GetBidsOfTender(ctx context.Context, tenderID string, limit, offset int32) ([]*models.BidResponse, error) {
 	rows, err := p.DB.Query(ctx, `
-	SELECT *
+	SELECT `+bidColumns+`
 	FROM bid
 		WHERE tender_id = $1
 		AND status != 'Created' 
@@ -164,9 +181,7 @@ func (p *Postgres) GetBidsOfTender(ctx context.Context, tenderID string, limit,
 	bids := []*models.BidResponse{}
 	for rows.Next() {
 		bid := &models.BidResponse{}
-		if err := rows.Scan(
-			&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-			&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt); err != nil {
+		if err := scanBid(rows, bid); err != nil {
 			return nil, err
 		}
 
@@ -182,9 +197,12 @@ func (p *Postgres) GetBidsOfTender(ctx context.Context, tenderID string, limit,
 
 func (p *Postgres) GetBidsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.BidResponse, error) {
 	rows, err := p.DB.Query(ctx, `
-	SELECT *
+	SELECT `+bidColumns+`
 	FROM bid b 
 		WHERE author_id = $1
+		OR organization_id IN (
+			SELECT organization_id FROM organization_responsible WHERE user_id = $1
+		)
 	ORDER BY name ASC
 	LIMIT $2 OFFSET $3;`, userID, limit, offset)
 	if err != nil {
@@ -195,9 +213,7 @@ func (p *Postgres) GetBidsOfUser(ctx context.Context, userID string, limit, offs
 	bids := []*models.BidResponse{}
 	for rows.Next() {
 		bid := &models.BidResponse{}
-		if err := rows.Scan(
-			&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-			&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt); err != nil {
+		if err := scanBid(rows, bid); err != nil {
 			return nil, err
 		}
 
@@ -209,12 +225,10 @@ func (p *Postgres) GetBidsOfUser(ctx context.Context, userID string, limit, offs
 
 func (p *Postgres) GetBidsWithID(ctx context.Context, bidID string) (*models.BidResponse, error) {
 	bid := &models.BidResponse{}
-	err := p.DB.QueryRow(ctx, `
-        SELECT *
+	err := scanBid(p.DB.QueryRow(ctx, `
+        SELECT `+bidColumns+`
         FROM bid b
-        WHERE b.id = $1`, bidID).Scan(
-		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-		&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt)
+        WHERE b.id = $1`, bidID), bid)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrBidNotFound
 	}
@@ -222,19 +236,119 @@ func (p *Postgres) GetBidsWithID(ctx context.Context, bidID string) (*models.Bid
 	return bid, err
 }
 
+// RenewStatusOfBid changes the status of a bid without a final decision on
+// a published tender only, so that decisions cannot be reopened.
 func (p *Postgres) RenewStatusOfBid(ctx context.Context, bidID, username string, status *models.BidStatus) (*models.BidResponse, error) {
 	bid := &models.BidResponse{}
-	err := p.DB.QueryRow(ctx, `
-    UPDATE bid
+	err := scanBid(p.DB.QueryRow(ctx, `
+    UPDATE bid b
 		SET status = $2::bid_status
 		WHERE id = $1
-	returning *`, bidID, status).Scan(
-		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-		&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt)
-	if errors.Is(err, pgx.ErrNoRows) {
+			AND status IN ('Created', 'Published')
+			AND EXISTS (
+				SELECT 1 FROM tender t WHERE t.id = b.tender_id AND t.status = 'Published'
+			)
+	returning `+bidColumns, bidID, status), bid)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return bid, err
+	}
+
+	var exists bool
+	if err := p.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bid WHERE id = $1)`, bidID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
 		return nil, repository.ErrBidNotFound
 	}
 
-	return bid, err
+	return nil, repository.ErrBidStatusFinal
+}
+
+
+// WithdrawBid withdraws a bid that has no final decision yet and invalidates
+// the decisions already cast on it, in one transaction.
+func (p *Postgres) WithdrawBid(ctx context.Context, bidID, reason string) (bid *models.BidResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO bid_version 
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids) 
+	SELECT
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
+	FROM bid
+	WHERE id = $1;`, bidID)
+	if pgCmd.RowsAffected() == 0 {
+		return nil, repository.ErrBidNotFound
+	}
+
+	bid = &models.BidResponse{}
+	err = scanBid(tx.QueryRow(ctx, `
+	UPDATE bid b
+	SET
+		status = 'Withdrawn',
+		withdrawal_reason = $2,
+		version = version + 1
+	WHERE id = $1
+		AND status IN ('Created', 'Published')
+		AND EXISTS (
+			SELECT 1 FROM tender t WHERE t.id = b.tender_id AND t.status = 'Published'
+		)
+	returning `+bidColumns+`;`, bidID, reason), bid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrBidNotWithdrawable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	UPDATE bid_decision
+	SET invalidated_at = NOW()
+	WHERE bid_id = $1
+		AND invalidated_at IS NULL;`, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	return bid, nil
 }
 
+// GetBidStatusesOfAuthor lists the bids of the organization on the tender
+// when organizationID is set, otherwise the user bids of the author.
+func (p *Postgres) GetBidStatusesOfAuthor(ctx context.Context, tenderID, authorID string, organizationID *models.OrganizationID) ([]models.BidStatus, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT status
+	FROM bid
+		WHERE tender_id = $1
+		AND (organization_id = $3 OR $3::uuid IS NULL AND organization_id IS NULL AND author_id = $2)
+	ORDER BY created_at ASC;`, tenderID, authorID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses []models.BidStatus
+	for rows.Next() {
+		var status models.BidStatus
+		if err := rows.Scan(&status); err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// debarmentColumns lists the debarment columns in the order scanDebarment
+// reads them.
+const debarmentColumns = `id, supplier_id, scope, organization_id, reason, valid_from, valid_until,
+		COALESCE(created_by::text, ''), created_at, updated_at,
+		COALESCE(lifted_by::text, ''), lifted_at, lift_reason`
+
+func scanDebarment(row pgx.Row, debarment *models.Debarment) error {
+	return row.Scan(
+		&debarment.ID, &debarment.SupplierID, &debarment.Scope, &debarment.OrganizationID, &debarment.Reason,
+		&debarment.ValidFrom, &debarment.ValidUntil,
+		&debarment.CreatedBy, &debarment.CreatedAt, &debarment.UpdatedAt,
+		&debarment.LiftedBy, &debarment.LiftedAt, &debarment.LiftReason)
+}
+
+// debarmentBlocks is the condition under which a debarment in force blocks a
+// supplier from the tender, all given by SQL expressions: the organization,
+// or when it is NULL any organization of the user.
+func debarmentBlocks(tender, organization, user string) string {
+	return fmt.Sprintf(`lifted_at IS NULL
+		AND valid_from <= NOW()
+		AND (valid_until IS NULL OR valid_until > NOW())
+		AND (organization_id IS NULL OR organization_id = (SELECT organization_id FROM tender WHERE id = %[1]s))
+		AND CASE
+			WHEN %[2]s IS NOT NULL THEN supplier_id = %[2]s
+			ELSE supplier_id IN (
+				SELECT organization_id FROM organization_responsible WHERE user_id = %[3]s
+			)
+		END`, tender, organization, user)
+}
+
+// CreateDebarment stores the debarment together with its audit record.
+func (p *Postgres) CreateDebarment(ctx context.Context, userID string, create *models.DebarmentCreate, details string) (debarment *models.Debarment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	debarment = &models.Debarment{}
+	err = scanDebarment(tx.QueryRow(ctx, `
+	INSERT INTO debarment
+		(supplier_id, scope, organization_id, reason, valid_from, valid_until, created_by)
+	VALUES
+		($1, $2, $3, $4, COALESCE($5, NOW()), $6, $7)
+	RETURNING `+debarmentColumns+`;`,
+		create.SupplierID, create.Scope, create.OrganizationID, create.Reason, create.ValidFrom, create.ValidUntil, userID), debarment)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = auditDebarment(ctx, tx, debarment.ID, userID, models.AuditActionDebarmentCreated, details); err != nil {
+		return nil, err
+	}
+
+	return debarment, nil
+}
+
+func (p *Postgres) GetDebarment(ctx context.Context, debarmentID string) (*models.Debarment, error) {
+	debarment := &models.Debarment{}
+	err := scanDebarment(p.DB.QueryRow(ctx, `
+	SELECT `+debarmentColumns+`
+	FROM debarment
+		WHERE id = $1;`, debarmentID), debarment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrDebarmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return debarment, nil
+}
+
+func (p *Postgres) GetDebarments(ctx context.Context, organizationID *models.OrganizationID, limit, offset int32) ([]*models.Debarment, error) {
+	return p.queryDebarments(ctx, `
+	SELECT `+debarmentColumns+`
+	FROM debarment
+		WHERE $1::uuid IS NULL
+		OR organization_id = $1::uuid
+		OR organization_id IS NULL
+	ORDER BY created_at DESC, id
+	LIMIT $2 OFFSET $3;`, organizationID, limit, offset)
+}
+
+func (p *Postgres) GetDebarmentsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Debarment, error) {
+	return p.queryDebarments(ctx, `
+	SELECT `+debarmentColumns+`
+	FROM debarment
+		WHERE lifted_at IS NULL
+		AND (valid_until IS NULL OR valid_until > NOW())
+		AND supplier_id IN (
+			SELECT organization_id FROM organization_responsible WHERE user_id = $1
+		)
+	ORDER BY valid_from, id
+	LIMIT $2 OFFSET $3;`, userID, limit, offset)
+}
+
+// UpdateDebarment changes a debarment that is not lifted. The row is locked
+// so that a lift cannot slip in between.
+func (p *Postgres) UpdateDebarment(ctx context.Context, debarmentID, userID string, edit *models.DebarmentEdit, details string) (debarment *models.Debarment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if err = lockDebarment(ctx, tx, debarmentID); err != nil {
+		return nil, err
+	}
+
+	debarment = &models.Debarment{}
+	err = scanDebarment(tx.QueryRow(ctx, `
+	UPDATE debarment
+	SET
+		reason = COALESCE($2, reason),
+		valid_until = COALESCE($3, valid_until),
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING `+debarmentColumns+`;`, debarmentID, edit.Reason, edit.ValidUntil), debarment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = auditDebarment(ctx, tx, debarmentID, userID, models.AuditActionDebarmentChanged, details); err != nil {
+		return nil, err
+	}
+
+	return debarment, nil
+}
+
+// LiftDebarment ends a debarment for good. The reason is audited as well.
+func (p *Postgres) LiftDebarment(ctx context.Context, debarmentID, userID, reason string) (debarment *models.Debarment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if err = lockDebarment(ctx, tx, debarmentID); err != nil {
+		return nil, err
+	}
+
+	debarment = &models.Debarment{}
+	err = scanDebarment(tx.QueryRow(ctx, `
+	UPDATE debarment
+	SET
+		lifted_by = $2,
+		lifted_at = NOW(),
+		lift_reason = $3,
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING `+debarmentColumns+`;`, debarmentID, userID, reason), debarment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = auditDebarment(ctx, tx, debarmentID, userID, models.AuditActionDebarmentLifted, reason); err != nil {
+		return nil, err
+	}
+
+	return debarment, nil
+}
+
+func (p *Postgres) GetDebarmentAudit(ctx context.Context, debarmentID string, limit, offset int32) ([]*models.AuditRecord, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, actor_id, action, debarment_id, details, created_at
+	FROM audit_log
+		WHERE debarment_id = $1
+	ORDER BY created_at ASC, id
+	LIMIT $2 OFFSET $3;`, debarmentID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*models.AuditRecord{}
+	for rows.Next() {
+		record := &models.AuditRecord{}
+		if err := rows.Scan(
+			&record.ID, &record.ActorID, &record.Action, &record.DebarmentID,
+			&record.Details, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// ControlDebarment reports the debarment that blocks the supplier the
+// longest when several do.
+func (p *Postgres) ControlDebarment(ctx context.Context, tenderID, userID string, organizationID *models.OrganizationID) error {
+	debarment := &models.Debarment{}
+	err := scanDebarment(p.DB.QueryRow(ctx, `
+	SELECT `+debarmentColumns+`
+	FROM debarment
+		WHERE `+debarmentBlocks("$1", "$3::uuid", "$2")+`
+	ORDER BY valid_until DESC NULLS FIRST, created_at, id
+	LIMIT 1;`, tenderID, userID, organizationID), debarment)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &repository.DebarredError{Debarment: debarment}
+}
+
+func (p *Postgres) queryDebarments(ctx context.Context, query string, args ...any) ([]*models.Debarment, error) {
+	rows, err := p.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	debarments := []*models.Debarment{}
+	for rows.Next() {
+		debarment := &models.Debarment{}
+		if err := scanDebarment(rows, debarment); err != nil {
+			return nil, err
+		}
+
+		debarments = append(debarments, debarment)
+	}
+
+	return debarments, rows.Err()
+}
+
+// lockDebarment locks a debarment that can still be changed.
+func lockDebarment(ctx context.Context, tx pgx.Tx, debarmentID string) error {
+	var lifted bool
+	err := tx.QueryRow(ctx, `
+	SELECT lifted_at IS NOT NULL
+	FROM debarment
+		WHERE id = $1
+	FOR UPDATE;`, debarmentID).Scan(&lifted)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrDebarmentNotFound
+	case err != nil:
+		return err
+	case lifted:
+		return repository.ErrDebarmentLifted
+	}
+
+	return nil
+}
+
+func auditDebarment(ctx context.Context, tx pgx.Tx, debarmentID, userID string, action models.AuditAction, details string) error {
+	_, err := tx.Exec(ctx, `
+	INSERT INTO audit_log (actor_id, action, debarment_id, details)
+	VALUES ($1, $2, $3, $4);`, userID, action, debarmentID, details)
+
+	return err
+}
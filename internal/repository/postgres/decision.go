@@ -3,31 +3,39 @@ package postgres
 import (
 	"context"
 	"errors"
-	"log"
 
 	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func (p *Postgres) ApplyBidDecision(ctx context.Context, bidID, username string, decision *models.BidDecision) (*models.BidResponse, error) {
 	bid := &models.BidResponse{}
-	err := p.DB.QueryRow(ctx, `
+	err := scanBid(p.DB.QueryRow(ctx, `
 	WITH inserted AS (
 		INSERT INTO bid_decision (bid_id, user_id, decision)
 		VALUES ($1, (SELECT id FROM employee WHERE username = $2), $3)
 		RETURNING bid_id
 	)
-	SELECT b.*
+	SELECT `+bidColumns+`
 	FROM bid b
-	JOIN inserted i ON b.id = i.bid_id;`, bidID, username, decision).Scan(
-		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
-		&bid.AuthorType, &bid.AuthorID, &bid.Version, &bid.CreatedAt)
+	JOIN inserted i ON b.id = i.bid_id;`, bidID, username, decision), bid)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrBidNotFound
 	}
 
-	log.Println(err)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case repository.NotNullViolation:
+			return nil, repository.ErrUserNotExist
+		case repository.FKViolation:
+			return nil, repository.ErrBidNotFound
+		case repository.UniqueConstraint:
+			return nil, repository.ErrBidDecisionUnique
+		}
+	}
 
 	return bid, err
 }
@@ -54,7 +62,7 @@ func (p *Postgres) CountApplyedDecisions(ctx context.Context, bidID string) (int
     err := p.DB.QueryRow(ctx, `
         SELECT COUNT(*)
         FROM bid_decision
-        WHERE bid_id = $1 AND decision = $2`, bidID, models.BidDecisionApproved).Scan(&count)
+        WHERE bid_id = $1 AND decision = $2 AND invalidated_at IS NULL`, bidID, models.BidDecisionApproved).Scan(&count)
     
     return count, err
 }
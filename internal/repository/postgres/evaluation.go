@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SetTenderCriteria replaces the criteria of a tender, as long as no bid
+// has been scored on them.
+func (p *Postgres) SetTenderCriteria(ctx context.Context, tenderID string, criteria []*models.CriterionCreate) (_ []*models.Criterion, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var scored bool
+	err = tx.QueryRow(ctx, `
+	SELECT EXISTS (
+		SELECT 1
+		FROM bid_score s
+		JOIN tender_criterion c ON c.id = s.criterion_id
+			WHERE c.tender_id = t.id
+	)
+	FROM tender t
+		WHERE t.id = $1
+	FOR UPDATE OF t;`, tenderID).Scan(&scored)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrTenderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if scored {
+		return nil, repository.ErrCriteriaLocked
+	}
+
+	_, err = tx.Exec(ctx, `
+	DELETE FROM tender_criterion
+		WHERE tender_id = $1;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(criteria))
+	weights := make([]int32, 0, len(criteria))
+	for _, criterion := range criteria {
+		names = append(names, criterion.Name)
+		weights = append(weights, criterion.Weight)
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO tender_criterion (tender_id, name, weight, position)
+	SELECT $1, c.name, c.weight, c.position
+	FROM unnest($2::text[], $3::int[]) WITH ORDINALITY AS c(name, weight, position);`, tenderID, names, weights)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.UniqueConstraint {
+		return nil, repository.ErrInvalidCriteria
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+	SELECT id, tender_id, name, weight
+	FROM tender_criterion
+		WHERE tender_id = $1
+	ORDER BY position ASC;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectCriteria(rows)
+}
+
+func (p *Postgres) GetCriteriaByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.Criterion, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT id, tender_id, name, weight
+	FROM tender_criterion
+		WHERE tender_id = ANY($1::uuid[])
+	ORDER BY tender_id, position ASC;`, tenderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectCriteria(rows)
+}
+
+// ScoreBid sets the scores of the evaluator on the bid, replacing earlier
+// scores on the same criteria. It returns every score of the evaluator on
+// the bid.
+func (p *Postgres) ScoreBid(ctx context.Context, bidID, userID string, scores []*models.CriterionScore) (_ []*models.BidScore, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	criterionIDs := make([]string, 0, len(scores))
+	values := make([]int32, 0, len(scores))
+	for _, score := range scores {
+		criterionIDs = append(criterionIDs, score.CriterionID)
+		values = append(values, score.Score)
+	}
+
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO bid_score (bid_id, criterion_id, user_id, score)
+	SELECT b.id, c.id, $2, s.score
+	FROM unnest($3::uuid[], $4::int[]) AS s(criterion_id, score)
+	JOIN tender_criterion c ON c.id = s.criterion_id
+	JOIN bid b ON b.tender_id = c.tender_id
+		WHERE b.id = $1
+	ON CONFLICT (bid_id, criterion_id, user_id) DO UPDATE
+	SET
+		score = EXCLUDED.score,
+		scored_at = NOW();`, bidID, userID, criterionIDs, values)
+	if err != nil {
+		return nil, err
+	}
+	if pgCmd.RowsAffected() != int64(len(scores)) {
+		return nil, repository.ErrCriterionNotFound
+	}
+
+	rows, err := tx.Query(ctx, `
+	SELECT s.bid_id, s.criterion_id, s.user_id, s.score, s.scored_at
+	FROM bid_score s
+	JOIN tender_criterion c ON c.id = s.criterion_id
+		WHERE s.bid_id = $1 AND s.user_id = $2
+	ORDER BY c.position ASC;`, bidID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectScores(rows)
+}
+
+func (p *Postgres) GetScoresOfTender(ctx context.Context, tenderID string) ([]*models.BidScore, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT s.bid_id, s.criterion_id, s.user_id, s.score, s.scored_at
+	FROM bid_score s
+	JOIN bid b ON b.id = s.bid_id
+		WHERE b.tender_id = $1
+	ORDER BY s.scored_at ASC;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectScores(rows)
+}
+
+// AwardBid approves a published bid on a published tender and closes the
+// tender.
+func (p *Postgres) AwardBid(ctx context.Context, bidID, username, details string) (bid *models.BidResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	bid = &models.BidResponse{}
+	err = scanBid(tx.QueryRow(ctx, `
+	WITH updated AS (
+		UPDATE bid b
+		SET status = 'Approved'
+		FROM tender t
+		WHERE b.id = $1
+			AND t.id = b.tender_id
+			AND b.status = 'Published'
+			AND t.status = 'Published'
+		RETURNING b.*
+	)
+	SELECT `+bidColumns+` FROM updated;`, bidID), bid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrTenderClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	UPDATE tender
+	SET status = 'Closed'
+	WHERE id = $1;`, bid.TenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO audit_log (actor_id, action, tender_id, bid_id, details)
+	VALUES ((SELECT id FROM employee WHERE username = $1), $2, $3, $4, $5);`, username, models.AuditActionTenderAwarded, bid.TenderID, bid.ID, details)
+	if err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+func collectCriteria(rows pgx.Rows) ([]*models.Criterion, error) {
+	defer rows.Close()
+
+	criteria := []*models.Criterion{}
+	for rows.Next() {
+		criterion := &models.Criterion{}
+		if err := rows.Scan(&criterion.ID, &criterion.TenderID, &criterion.Name, &criterion.Weight); err != nil {
+			return nil, err
+		}
+
+		criteria = append(criteria, criterion)
+	}
+
+	return criteria, rows.Err()
+}
+
+func collectScores(rows pgx.Rows) ([]*models.BidScore, error) {
+	defer rows.Close()
+
+	scores := []*models.BidScore{}
+	for rows.Next() {
+		score := &models.BidScore{}
+		if err := rows.Scan(&score.BidID, &score.CriterionID, &score.UserID, &score.Score, &score.ScoredAt); err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
@@ -7,32 +7,24 @@ import (
 	"github.com/DarRo9/Tenders/models"
 )
 
-func (p *Postgres) ApplyBidFeedback(ctx context.Context, bidID string, feedback *models.BidFeedback) error {
-	pgCmd, err := p.DB.Exec(ctx, `
-	INSERT INTO bid_feedback 
-		(bid_id, description) 
-    VALUES ($1, $2) `, bidID, feedback)
-	if pgCmd.RowsAffected() == 0 {
-		return repository.ErrBidNotFound
-	}
-
-	return err
-}
-
+// GetCommentsOfBid lists the buyer messages on the bids of an author, the
+// way feedback was listed before the threads.
 func (p *Postgres) GetCommentsOfBid(ctx context.Context, tenderID, authorUsername string, limit, offset int32) ([]*models.BidReviewResponse, error) {
 	rows, err := p.DB.Query(ctx, `
-	SELECT bf.*
-		FROM bid_feedback bf
-		JOIN bid b ON bf.bid_id = b.id
+	SELECT
+		m.id, m.bid_id, m.author_id, m.role, m.reply_to, m.body, m.created_at
+		FROM bid_message m
+		JOIN bid b ON m.bid_id = b.id
 		WHERE b.tender_id = $1
+		AND m.role = 'Buyer'
 		AND EXISTS (
 			SELECT 1
 			FROM employee e
 				WHERE e.id = b.author_id
-				AND e.username = $2  
-		ORDER BY created_at ASC
-		LIMIT $3
-		OFFSET $4);`, tenderID, authorUsername, limit, offset)
+				AND e.username = $2)
+	ORDER BY m.created_at ASC, m.id ASC
+	LIMIT $3
+	OFFSET $4;`, tenderID, authorUsername, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -40,16 +32,19 @@ func (p *Postgres) GetCommentsOfBid(ctx context.Context, tenderID, authorUsernam
 
 	var reviews []*models.BidReviewResponse
 	for rows.Next() {
-		var review models.BidReviewResponse
-		if err := rows.Scan(&review.ID, &review.BidID, &review.Description, &review.CreatedAt); err != nil {
+		var message models.BidMessage
+		if err := scanMessage(rows, &message); err != nil {
 			return nil, err
 		}
-		reviews = append(reviews, &review)
+		reviews = append(reviews, message.Review())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	if len(reviews) == 0 {
 		return nil, repository.ErrBidReviewsNotFound
 	}
 
-	return reviews, err
+	return reviews, nil
 }
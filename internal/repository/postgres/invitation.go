@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// tenderVisibleTo is the condition under which the user, given by the
+// numbered query parameter, sees the tender t regardless of its status: it is
+// public, of one of the user's organizations, or one of them is invited. An
+// empty user is an anonymous one.
+func tenderVisibleTo(param int) string {
+	return fmt.Sprintf(`(t.visibility = 'Public'
+		OR t.organization_id IN (
+			SELECT organization_id FROM organization_responsible WHERE user_id = NULLIF($%[1]d, '')::uuid
+		)
+		OR t.visibility = 'Invitation' AND EXISTS (
+			SELECT 1
+			FROM tender_invitation ti
+			JOIN organization_responsible orr ON orr.organization_id = ti.organization_id
+				WHERE ti.tender_id = t.id
+				AND orr.user_id = NULLIF($%[1]d, '')::uuid
+		))`, param)
+}
+
+// InviteOrganizations adds the organizations to the invitations of the tender
+// and returns all of them. Organizations invited already keep their
+// invitation.
+func (p *Postgres) InviteOrganizations(ctx context.Context, tenderID string, organizationIDs []models.OrganizationID) ([]*models.TenderInvitation, error) {
+	_, err := p.DB.Exec(ctx, `
+	INSERT INTO tender_invitation (tender_id, organization_id)
+	SELECT $1, unnest($2::uuid[])
+	ON CONFLICT DO NOTHING;`, tenderID, organizationIDs)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		if pgErr.ConstraintName == "tender_invitation_tender_id_fkey" {
+			return nil, repository.ErrTenderNotFound
+		}
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetTenderInvitations(ctx, tenderID)
+}
+
+func (p *Postgres) GetTenderInvitations(ctx context.Context, tenderID string) ([]*models.TenderInvitation, error) {
+	var exists bool
+	err := p.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tender WHERE id = $1);`, tenderID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		tender_id, organization_id, invited_at
+	FROM tender_invitation
+		WHERE tender_id = $1
+	ORDER BY invited_at, organization_id;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.TenderInvitation{}
+	for rows.Next() {
+		invitation := &models.TenderInvitation{}
+		if err := rows.Scan(&invitation.TenderID, &invitation.OrganizationID, &invitation.InvitedAt); err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// GetInvitedTenders returns the published invitation-only tenders one of the
+// user's organizations is invited to.
+func (p *Postgres) GetInvitedTenders(ctx context.Context, userID string, limit, offset int32) ([]*models.TenderResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+tenderColumns+`
+	FROM tender t
+		WHERE status = 'Published'
+		AND visibility = 'Invitation'
+		AND EXISTS (
+			SELECT 1
+			FROM tender_invitation ti
+			JOIN organization_responsible orr ON orr.organization_id = ti.organization_id
+				WHERE ti.tender_id = t.id
+				AND orr.user_id = $1
+		)
+	ORDER BY name ASC
+	LIMIT $2 OFFSET $3;`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectTenders(rows)
+}
+
+func (p *Postgres) ControlTenderVisibility(ctx context.Context, tenderID, userID string) error {
+	var visible bool
+
+	err := p.DB.QueryRow(ctx, `
+	SELECT `+tenderVisibleTo(2)+`
+	FROM tender t
+		WHERE t.id = $1;`, tenderID, userID).Scan(&visible)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrTenderNotFound
+	case err != nil:
+		return err
+	case !visible:
+		return repository.ErrRelationNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *Postgres) GetTendersByIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+tenderColumns+`
+	FROM tender
+		WHERE id = ANY($1::uuid[]);`, tenderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectTenders(rows)
+}
+
+func (p *Postgres) GetTenderVersionsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+tenderVersionColumns+`
+	FROM tender_version
+		WHERE tender_id = ANY($1::uuid[])
+	ORDER BY version ASC;`, tenderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectTenders(rows)
+}
+
+func (p *Postgres) GetBidsByIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+bidColumns+`
+	FROM bid
+		WHERE id = ANY($1::uuid[]);`, bidIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectBids(rows)
+}
+
+func (p *Postgres) GetBidsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.BidResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+bidColumns+`
+	FROM bid
+		WHERE tender_id = ANY($1::uuid[])
+	ORDER BY created_at ASC;`, tenderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectBids(rows)
+}
+
+func (p *Postgres) GetBidVersionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+bidVersionColumns+`
+	FROM bid_version
+		WHERE bid_id = ANY($1::uuid[])
+	ORDER BY version ASC;`, bidIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectBids(rows)
+}
+
+func (p *Postgres) GetDecisionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidDecisionResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, bid_id, user_id, decision, invalidated_at
+	FROM bid_decision
+		WHERE bid_id = ANY($1::uuid[]);`, bidIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decisions := []*models.BidDecisionResponse{}
+	for rows.Next() {
+		decision := &models.BidDecisionResponse{}
+		if err := rows.Scan(&decision.ID, &decision.BidID, &decision.UserID, &decision.Decision, &decision.InvalidatedAt); err != nil {
+			return nil, err
+		}
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, rows.Err()
+}
+
+func (p *Postgres) GetFeedbackByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidReviewResponse, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+messageColumns+`
+	FROM bid_message
+		WHERE bid_id = ANY($1::uuid[])
+		AND role = 'Buyer'
+	ORDER BY created_at ASC, id ASC;`, bidIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*models.BidReviewResponse{}
+	for rows.Next() {
+		message := &models.BidMessage{}
+		if err := scanMessage(rows, message); err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, message.Review())
+	}
+
+	return reviews, rows.Err()
+}
+
+func (p *Postgres) GetEmployeesByIDs(ctx context.Context, userIDs []string) ([]*models.Employee, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, username, COALESCE(first_name, ''), COALESCE(last_name, ''), created_at, updated_at
+	FROM employee
+		WHERE id = ANY($1::uuid[]);`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectEmployees(rows)
+}
+
+func (p *Postgres) GetEmployeesByUsernames(ctx context.Context, usernames []string) ([]*models.Employee, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, username, COALESCE(first_name, ''), COALESCE(last_name, ''), created_at, updated_at
+	FROM employee
+		WHERE username = ANY($1::text[]);`, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectEmployees(rows)
+}
+
+func (p *Postgres) GetOrganizationsByIDs(ctx context.Context, organizationIDs []string) ([]*models.Organization, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, name, COALESCE(description, ''), COALESCE(type::text, ''), created_at, updated_at
+	FROM organization
+		WHERE id = ANY($1::uuid[]);`, organizationIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []*models.Organization{}
+	for rows.Next() {
+		organization := &models.Organization{}
+		if err := rows.Scan(
+			&organization.ID, &organization.Name, &organization.Description, &organization.Type,
+			&organization.CreatedAt, &organization.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, rows.Err()
+}
+
+func collectTenders(rows pgx.Rows) ([]*models.TenderResponse, error) {
+	defer rows.Close()
+
+	tenders := []*models.TenderResponse{}
+	for rows.Next() {
+		tender := &models.TenderResponse{}
+		if err := scanTender(rows, tender); err != nil {
+			return nil, err
+		}
+
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
+
+func collectBids(rows pgx.Rows) ([]*models.BidResponse, error) {
+	defer rows.Close()
+
+	bids := []*models.BidResponse{}
+	for rows.Next() {
+		bid := &models.BidResponse{}
+		if err := scanBid(rows, bid); err != nil {
+			return nil, err
+		}
+
+		bids = append(bids, bid)
+	}
+
+	return bids, rows.Err()
+}
+
+func collectEmployees(rows pgx.Rows) ([]*models.Employee, error) {
+	defer rows.Close()
+
+	employees := []*models.Employee{}
+	for rows.Next() {
+		employee := &models.Employee{}
+		if err := rows.Scan(
+			&employee.ID, &employee.Username, &employee.FirstName, &employee.LastName,
+			&employee.CreatedAt, &employee.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		employees = append(employees, employee)
+	}
+
+	return employees, rows.Err()
+}
+
+// GetAttachmentsByIDs returns the attachments in the order of the IDs.
+func (p *Postgres) GetAttachmentsByIDs(ctx context.Context, attachmentIDs []string) ([]*models.Attachment, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+attachmentColumns+`
+	FROM attachment
+		WHERE id = ANY($1::uuid[])
+	ORDER BY array_position($1::uuid[], id);`, attachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		attachment := &models.Attachment{}
+		if err := scanAttachment(rows, attachment); err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// messageColumns lists the bid_message columns in the order scanMessage
+// reads them.
+const messageColumns = `id, bid_id, author_id, role, reply_to, body, created_at`
+
+func scanMessage(row pgx.Row, message *models.BidMessage) error {
+	return row.Scan(
+		&message.ID, &message.BidID, &message.AuthorID, &message.Role, &message.ReplyTo, &message.Body, &message.CreatedAt)
+}
+
+// PostBidMessage adds a message to the thread of a bid. A reply must refer to
+// a message of the same thread.
+func (p *Postgres) PostBidMessage(ctx context.Context, bidID, authorID string, role models.MessageRole, message *models.BidMessageCreate) (result *models.BidMessage, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if message.ReplyTo != nil {
+		var exists bool
+		err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM bid_message
+				WHERE id = $1 AND bid_id = $2
+		);`, *message.ReplyTo, bidID).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			return nil, repository.ErrMessageNotFound
+		}
+	}
+
+	result = &models.BidMessage{}
+	err = scanMessage(tx.QueryRow(ctx, `
+	INSERT INTO bid_message (bid_id, author_id, role, reply_to, body)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING `+messageColumns+`;`, bidID, authorID, role, message.ReplyTo, message.Body), result)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		return nil, repository.ErrBidNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Attachments = []*models.MessageAttachment{}
+	result.ReadBy = []*models.ReadReceipt{}
+	if len(message.Attachments) == 0 {
+		return result, nil
+	}
+
+	names := make([]string, len(message.Attachments))
+	urls := make([]string, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		names[i], urls[i] = attachment.Name, attachment.URL
+		result.Attachments = append(result.Attachments, &models.MessageAttachment{Name: attachment.Name, URL: attachment.URL})
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO bid_message_attachment (message_id, name, url, position)
+	SELECT $1, a.name, a.url, a.position
+	FROM unnest($2::text[], $3::text[]) WITH ORDINALITY AS a(name, url, position);`, result.ID, names, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetBidMessages lists the thread of a bid, oldest first, with the
+// attachments and read receipts of every message.
+func (p *Postgres) GetBidMessages(ctx context.Context, bidID string, limit, offset int32) ([]*models.BidMessage, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+messageColumns+`
+	FROM bid_message
+		WHERE bid_id = $1
+	ORDER BY created_at ASC, id ASC
+	LIMIT $2 OFFSET $3;`, bidID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []*models.BidMessage{}
+	byID := make(map[string]*models.BidMessage)
+	ids := []string{}
+	for rows.Next() {
+		message := &models.BidMessage{
+			Attachments: []*models.MessageAttachment{},
+			ReadBy:      []*models.ReadReceipt{},
+		}
+		if err := scanMessage(rows, message); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+		byID[message.ID] = message
+		ids = append(ids, message.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return messages, nil
+	}
+
+	rows, err = p.DB.Query(ctx, `
+	SELECT message_id, name, url
+	FROM bid_message_attachment
+		WHERE message_id = ANY($1::uuid[])
+	ORDER BY message_id, position;`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID string
+		attachment := &models.MessageAttachment{}
+		if err := rows.Scan(&messageID, &attachment.Name, &attachment.URL); err != nil {
+			return nil, err
+		}
+
+		byID[messageID].Attachments = append(byID[messageID].Attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = p.DB.Query(ctx, `
+	SELECT message_id, user_id, read_at
+	FROM bid_message_read
+		WHERE message_id = ANY($1::uuid[])
+	ORDER BY read_at ASC, user_id ASC;`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID string
+		receipt := &models.ReadReceipt{}
+		if err := rows.Scan(&messageID, &receipt.UserID, &receipt.ReadAt); err != nil {
+			return nil, err
+		}
+
+		byID[messageID].ReadBy = append(byID[messageID].ReadBy, receipt)
+	}
+
+	return messages, rows.Err()
+}
+
+// MarkBidMessagesRead adds a read receipt of the user to every message of the
+// thread the user has not written or read yet.
+func (p *Postgres) MarkBidMessagesRead(ctx context.Context, bidID, userID string) (int, error) {
+	pgCmd, err := p.DB.Exec(ctx, `
+	INSERT INTO bid_message_read (message_id, user_id)
+	SELECT id, $2
+	FROM bid_message
+		WHERE bid_id = $1
+		AND author_id IS DISTINCT FROM $2
+	ON CONFLICT DO NOTHING;`, bidID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(pgCmd.RowsAffected()), nil
+}
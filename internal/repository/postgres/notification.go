@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (p *Postgres) GetNotifications(ctx context.Context, userID string, limit, offset int32) ([]*models.Notification, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		id, recipient_id, kind, COALESCE(tender_id::text, ''), COALESCE(bid_id::text, ''), message, created_at
+	FROM notification
+		WHERE recipient_id = $1
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3;`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		notification := &models.Notification{}
+		if err := rows.Scan(
+			&notification.ID, &notification.RecipientID, &notification.Kind, &notification.TenderID,
+			&notification.BidID, &notification.Message, &notification.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
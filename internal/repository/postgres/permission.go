@@ -42,7 +42,10 @@ func (p *Postgres) ControlBidCreationByName(ctx context.Context, bidID, creatorU
     SELECT EXISTS (
 		SELECT 1
 		FROM bid b 
-			WHERE author_id = e.id and id = $2
+			WHERE id = $2
+			AND (author_id = e.id OR organization_id IN (
+				SELECT organization_id FROM organization_responsible WHERE user_id = e.id
+			))
 	) AS is_creator
 	FROM employee e
 		WHERE username = $1`, creatorUsername, bidID).Scan(&isCreator)
@@ -93,6 +96,30 @@ func (p *Postgres) ControlUserResponsibility(ctx context.Context, userId string)
 	return err
 }
 
+func (p *Postgres) ControlOrganizationPermissionByID(ctx context.Context, organizationID *models.OrganizationID, userID string) error {
+	var existsRelation bool
+
+	err := p.DB.QueryRow(ctx, `
+	SELECT
+		EXISTS (
+			SELECT 1
+			FROM organization_responsible
+			WHERE user_id = e.id
+			AND organization_id = $2
+		) AS exists_relation
+	FROM employee e
+	WHERE e.id = $1;`, userID, organizationID).Scan(&existsRelation)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrUserNotExist
+	case !existsRelation:
+		return repository.ErrRelationNotExist
+	}
+
+	return err
+}
+
 func (p *Postgres) ControlTendersCreationByName(ctx context.Context, tenderId, creatorUsername string) error {
 	var isCreator bool
 
@@ -171,9 +198,16 @@ func (p *Postgres) ControlUserResponsibilityForAuthorBid(ctx context.Context, bi
 		SELECT 1
 			FROM bid b
 			JOIN organization_responsible orr ON orr.user_id = e.id
-			JOIN organization_responsible o ON o.organization_id = orr.organization_id
 				WHERE b.id = $2
-				AND o.user_id = b.author_id
+				AND CASE
+					WHEN b.organization_id IS NOT NULL THEN orr.organization_id = b.organization_id
+					ELSE EXISTS (
+						SELECT 1
+						FROM organization_responsible o
+						WHERE o.organization_id = orr.organization_id
+						AND o.user_id = b.author_id
+					)
+				END
 	) AS is_related
 	FROM employee e
 		WHERE e.username = $1;`, username, bidID).Scan(&isRelated)
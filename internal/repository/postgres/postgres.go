@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,7 +13,17 @@ type Postgres struct {
 }
 
 func New(cfg *config.PGConfig) (*Postgres, error) {
-	pool, err := pgxpool.New(context.Background(), cfg.Conn)
+	poolCfg, err := pgxpool.ParseConfig(cfg.Conn)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig.Tracer = tracing.NewQueryTracer()
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -23,3 +34,21 @@ func New(cfg *config.PGConfig) (*Postgres, error) {
 
 	return &Postgres{DB: pool}, nil
 }
+
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.DB.Ping(ctx)
+}
+
+// MigrationVersion reads the state golang-migrate keeps in schema_migrations.
+func (p *Postgres) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var version uint
+	var dirty bool
+
+	err := p.DB.QueryRow(ctx, `
+	SELECT
+		version, dirty
+	FROM schema_migrations
+	LIMIT 1;`).Scan(&version, &dirty)
+
+	return version, dirty, err
+}
@@ -0,0 +1,40 @@
+package postgres_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/repository/postgres"
+	"github.com/DarRo9/Tenders/internal/repository/repotest"
+	"github.com/DarRo9/Tenders/internal/server"
+	"github.com/sirupsen/logrus"
+)
+
+// TestConformance needs a disposable database, all its tables are truncated.
+// Set TEST_POSTGRES_CONN to run it.
+func TestConformance(t *testing.T) {
+	conn := os.Getenv("TEST_POSTGRES_CONN")
+	if conn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+
+	cfg := &config.PGConfig{Conn: conn, MaxConns: 10, AutoMigrate: true}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	if _, err := server.Migrate(cfg, log); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := postgres.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(repo.DB.Close)
+
+	repotest.Run(t, func(t *testing.T) repotest.Repository {
+		return repo
+	})
+}
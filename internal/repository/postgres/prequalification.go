@@ -0,0 +1,519 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// querier is what the pool and a transaction have in common, for reads that
+// run in both.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+const questionnaireColumns = `id, organization_id, service_type, created_at, updated_at`
+
+func scanQuestionnaire(row pgx.Row, questionnaire *models.Questionnaire) error {
+	return row.Scan(
+		&questionnaire.ID, &questionnaire.OrganizationID, &questionnaire.ServiceType,
+		&questionnaire.CreatedAt, &questionnaire.UpdatedAt)
+}
+
+// applicationColumns lists the application columns in the order
+// scanApplication reads them.
+const applicationColumns = `id, questionnaire_id, organization_id, COALESCE(submitted_by::text, ''), submitted_at, status,
+		COALESCE(reviewed_by::text, ''), reviewed_at, review_comment, valid_until,
+		COALESCE(revoked_by::text, ''), revoked_at, revocation_reason`
+
+func scanApplication(row pgx.Row, application *models.Application) error {
+	return row.Scan(
+		&application.ID, &application.QuestionnaireID, &application.OrganizationID, &application.SubmittedBy,
+		&application.SubmittedAt, &application.Status,
+		&application.ReviewedBy, &application.ReviewedAt, &application.ReviewComment, &application.ValidUntil,
+		&application.RevokedBy, &application.RevokedAt, &application.RevocationReason)
+}
+
+// SetQuestionnaire creates the questionnaire of the organization for the
+// service type or replaces its questions, as long as nobody has applied yet.
+func (p *Postgres) SetQuestionnaire(ctx context.Context, set *models.QuestionnaireSet) (questionnaire *models.Questionnaire, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	questionnaire = &models.Questionnaire{}
+	err = scanQuestionnaire(tx.QueryRow(ctx, `
+	INSERT INTO prequalification_questionnaire (organization_id, service_type)
+	VALUES ($1, $2)
+	ON CONFLICT (organization_id, service_type) DO UPDATE
+		SET updated_at = NOW()
+	RETURNING `+questionnaireColumns+`;`, set.OrganizationID, set.ServiceType), questionnaire)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		return nil, repository.ErrOrganizationDepencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var applied bool
+	err = tx.QueryRow(ctx, `
+	SELECT EXISTS (
+		SELECT 1
+		FROM prequalification_application
+			WHERE questionnaire_id = $1
+	);`, questionnaire.ID).Scan(&applied)
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		return nil, repository.ErrQuestionnaireLocked
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM prequalification_question WHERE questionnaire_id = $1;`, questionnaire.ID); err != nil {
+		return nil, err
+	}
+
+	positions := make([]int32, len(set.Questions))
+	texts := make([]string, len(set.Questions))
+	types := make([]string, len(set.Questions))
+	required := make([]bool, len(set.Questions))
+	for i, question := range set.Questions {
+		positions[i], texts[i], types[i], required[i] = int32(i), question.Text, string(question.AnswerType), question.Required
+	}
+
+	if _, err = tx.Exec(ctx, `
+	INSERT INTO prequalification_question
+		(questionnaire_id, position, text, answer_type, required)
+	SELECT
+		$1, s.position, s.text, s.answer_type, s.required
+	FROM unnest($2::int[], $3::text[], $4::text[], $5::bool[]) AS s(position, text, answer_type, required);`,
+		questionnaire.ID, positions, texts, types, required); err != nil {
+		return nil, err
+	}
+
+	questionnaire.Questions, err = questionsOf(ctx, tx, questionnaire.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionnaire, nil
+}
+
+func (p *Postgres) GetQuestionnaire(ctx context.Context, questionnaireID string) (*models.Questionnaire, error) {
+	questionnaire := &models.Questionnaire{}
+	err := scanQuestionnaire(p.DB.QueryRow(ctx, `
+	SELECT `+questionnaireColumns+`
+	FROM prequalification_questionnaire
+		WHERE id = $1;`, questionnaireID), questionnaire)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrQuestionnaireNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	questionnaire.Questions, err = questionsOf(ctx, p.DB, questionnaire.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionnaire, nil
+}
+
+func (p *Postgres) FindQuestionnaire(ctx context.Context, organizationID models.OrganizationID, serviceType models.TenderServiceType) (*models.Questionnaire, error) {
+	questionnaire := &models.Questionnaire{}
+	err := scanQuestionnaire(p.DB.QueryRow(ctx, `
+	SELECT `+questionnaireColumns+`
+	FROM prequalification_questionnaire
+		WHERE organization_id = $1
+		AND service_type = $2::service_type;`, organizationID, serviceType), questionnaire)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrQuestionnaireNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	questionnaire.Questions, err = questionsOf(ctx, p.DB, questionnaire.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionnaire, nil
+}
+
+// SubmitApplication stores the application with its answers. An
+// organization with an application under review is ErrApplicationPending.
+func (p *Postgres) SubmitApplication(ctx context.Context, questionnaireID, userID string, create *models.ApplicationCreate) (application *models.Application, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	application = &models.Application{}
+	err = scanApplication(tx.QueryRow(ctx, `
+	INSERT INTO prequalification_application
+		(questionnaire_id, organization_id, submitted_by)
+	VALUES ($1, $2, $3)
+	RETURNING `+applicationColumns+`;`, questionnaireID, create.OrganizationID, userID), application)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == repository.UniqueConstraint:
+			return nil, repository.ErrApplicationPending
+		case pgErr.Code == repository.FKViolation && pgErr.ConstraintName == "prequalification_application_questionnaire_id_fkey":
+			return nil, repository.ErrQuestionnaireNotFound
+		case pgErr.Code == repository.FKViolation:
+			return nil, repository.ErrOrganizationDepencyNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	questionIDs := make([]string, len(create.Answers))
+	values := make([]string, len(create.Answers))
+	for i, answer := range create.Answers {
+		questionIDs[i], values[i] = answer.QuestionID, answer.Value
+	}
+
+	if _, err = tx.Exec(ctx, `
+	INSERT INTO prequalification_answer
+		(application_id, question_id, value)
+	SELECT
+		$1, s.question_id, s.value
+	FROM unnest($2::uuid[], $3::text[]) AS s(question_id, value);`, application.ID, questionIDs, values); err != nil {
+		return nil, err
+	}
+
+	if err = loadAnswers(ctx, tx, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+func (p *Postgres) GetApplication(ctx context.Context, applicationID string) (*models.Application, error) {
+	application := &models.Application{}
+	err := scanApplication(p.DB.QueryRow(ctx, `
+	SELECT `+applicationColumns+`
+	FROM prequalification_application
+		WHERE id = $1;`, applicationID), application)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadAnswers(ctx, p.DB, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+func (p *Postgres) GetApplicationsOfQuestionnaire(ctx context.Context, questionnaireID string, limit, offset int32) ([]*models.Application, error) {
+	return p.queryApplications(ctx, `
+	SELECT `+applicationColumns+`
+	FROM prequalification_application
+		WHERE questionnaire_id = $1
+	ORDER BY submitted_at DESC, id
+	LIMIT $2 OFFSET $3;`, questionnaireID, limit, offset)
+}
+
+// GetApplicationsOfUser returns the applications of the organizations the
+// user is a responsible of.
+func (p *Postgres) GetApplicationsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Application, error) {
+	return p.queryApplications(ctx, `
+	SELECT `+applicationColumns+`
+	FROM prequalification_application
+		WHERE organization_id IN (
+			SELECT organization_id FROM organization_responsible WHERE user_id = $1
+		)
+	ORDER BY submitted_at DESC, id
+	LIMIT $2 OFFSET $3;`, userID, limit, offset)
+}
+
+// ReviewApplication decides on an application under review. ValidUntil is
+// only kept with an approval.
+func (p *Postgres) ReviewApplication(ctx context.Context, applicationID, userID string, review *models.ApplicationReview) (*models.Application, error) {
+	validUntil := review.ValidUntil
+	if review.Decision != models.ApplicationDecisionApproved {
+		validUntil = nil
+	}
+
+	application := &models.Application{}
+	err := scanApplication(p.DB.QueryRow(ctx, `
+	UPDATE prequalification_application
+	SET
+		status = $3,
+		reviewed_by = $2,
+		reviewed_at = NOW(),
+		review_comment = $4,
+		valid_until = $5
+	WHERE id = $1
+		AND status = 'Submitted'
+	RETURNING `+applicationColumns+`;`, applicationID, userID, review.Decision, review.Comment, validUntil), application)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, p.applicationConflict(ctx, applicationID, repository.ErrApplicationNotPending)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadAnswers(ctx, p.DB, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// RevokeApplication ends an approval before it expires.
+func (p *Postgres) RevokeApplication(ctx context.Context, applicationID, userID, reason string) (*models.Application, error) {
+	application := &models.Application{}
+	err := scanApplication(p.DB.QueryRow(ctx, `
+	UPDATE prequalification_application
+	SET
+		status = 'Revoked',
+		revoked_by = $2,
+		revoked_at = NOW(),
+		revocation_reason = $3
+	WHERE id = $1
+		AND status = 'Approved'
+	RETURNING `+applicationColumns+`;`, applicationID, userID, reason), application)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, p.applicationConflict(ctx, applicationID, repository.ErrApplicationNotRevocable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadAnswers(ctx, p.DB, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// AttachToApplication stores a license of an application under review. The
+// application is locked so that a review cannot slip in between.
+func (p *Postgres) AttachToApplication(ctx context.Context, attachment *models.Attachment) (result *models.Attachment, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var status models.ApplicationStatus
+	err = tx.QueryRow(ctx, `
+	SELECT status
+	FROM prequalification_application
+		WHERE id = $1
+	FOR UPDATE;`, *attachment.ApplicationID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != models.ApplicationStatusSubmitted {
+		return nil, repository.ErrApplicationNotPending
+	}
+
+	return insertAttachment(ctx, tx, attachment)
+}
+
+func (p *Postgres) GetApplicationLicenses(ctx context.Context, applicationID string) ([]*models.Attachment, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+attachmentColumns+`
+	FROM attachment
+		WHERE application_id = $1
+	ORDER BY created_at, id;`, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		attachment := &models.Attachment{}
+		if err := scanAttachment(rows, attachment); err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// ControlPrequalification checks that the organization holds an approval for
+// the category of the tender that has neither expired nor been revoked.
+func (p *Postgres) ControlPrequalification(ctx context.Context, tenderID string, organizationID models.OrganizationID) error {
+	var approved bool
+
+	err := p.DB.QueryRow(ctx, `
+	SELECT EXISTS (
+		SELECT 1
+		FROM tender t
+		JOIN prequalification_questionnaire q ON q.organization_id = t.organization_id AND q.service_type = t.service_type
+		JOIN prequalification_application a ON a.questionnaire_id = q.id
+			WHERE t.id = $1
+			AND a.status = 'Approved'
+			AND a.valid_until > NOW()
+			AND a.organization_id = $2
+	);`, tenderID, organizationID).Scan(&approved)
+	if err != nil {
+		return err
+	}
+
+	if !approved {
+		return repository.ErrNotPrequalified
+	}
+
+	return nil
+}
+
+func (p *Postgres) queryApplications(ctx context.Context, query string, args ...any) ([]*models.Application, error) {
+	rows, err := p.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applications := []*models.Application{}
+	for rows.Next() {
+		application := &models.Application{}
+		if err := scanApplication(rows, application); err != nil {
+			return nil, err
+		}
+
+		applications = append(applications, application)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadAnswers(ctx, p.DB, applications...); err != nil {
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+// applicationConflict tells a missing application from one in the wrong
+// status after an update matched no row.
+func (p *Postgres) applicationConflict(ctx context.Context, applicationID string, conflict error) error {
+	var exists bool
+	err := p.DB.QueryRow(ctx, `
+	SELECT EXISTS (
+		SELECT 1 FROM prequalification_application WHERE id = $1
+	);`, applicationID).Scan(&exists)
+	switch {
+	case err != nil:
+		return err
+	case !exists:
+		return repository.ErrApplicationNotFound
+	}
+
+	return conflict
+}
+
+func questionsOf(ctx context.Context, q querier, questionnaireID string) ([]*models.PrequalificationQuestion, error) {
+	rows, err := q.Query(ctx, `
+	SELECT
+		id, text, answer_type, required
+	FROM prequalification_question
+		WHERE questionnaire_id = $1
+	ORDER BY position;`, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []*models.PrequalificationQuestion{}
+	for rows.Next() {
+		question := &models.PrequalificationQuestion{}
+		if err := rows.Scan(&question.ID, &question.Text, &question.AnswerType, &question.Required); err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+// loadAnswers reads the answers of the applications in the order of the
+// questions.
+func loadAnswers(ctx context.Context, q querier, applications ...*models.Application) error {
+	if len(applications) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Application, len(applications))
+	applicationIDs := make([]string, len(applications))
+	for i, application := range applications {
+		application.Answers = []*models.Answer{}
+		byID[application.ID] = application
+		applicationIDs[i] = application.ID
+	}
+
+	rows, err := q.Query(ctx, `
+	SELECT
+		a.application_id, a.question_id, a.value
+	FROM prequalification_answer a
+	JOIN prequalification_question q ON q.id = a.question_id
+		WHERE a.application_id = ANY($1::uuid[])
+	ORDER BY q.position;`, applicationIDs)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var applicationID string
+		answer := &models.Answer{}
+		if err := rows.Scan(&applicationID, &answer.QuestionID, &answer.Value); err != nil {
+			return err
+		}
+
+		application := byID[applicationID]
+		application.Answers = append(application.Answers, answer)
+	}
+
+	return rows.Err()
+}
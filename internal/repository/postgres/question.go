@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// questionColumns lists the tender_question columns in the order
+// scanQuestion reads them.
+const questionColumns = `id, tender_id, asker_id, question, created_at, answer, visibility, answered_at, amended_version`
+
+func scanQuestion(row pgx.Row, question *models.Question) error {
+	return row.Scan(
+		&question.ID, &question.TenderID, &question.AskerID, &question.Question, &question.CreatedAt,
+		&question.Answer, &question.Visibility, &question.AnsweredAt, &question.AmendedVersion)
+}
+
+func (p *Postgres) AskQuestion(ctx context.Context, tenderID, askerID, question string) (*models.Question, error) {
+	result := &models.Question{}
+	err := scanQuestion(p.DB.QueryRow(ctx, `
+	INSERT INTO tender_question (tender_id, asker_id, question)
+	VALUES ($1, $2, $3)
+	RETURNING `+questionColumns+`;`, tenderID, askerID, question), result)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == repository.FKViolation {
+		return nil, repository.ErrTenderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *Postgres) GetQuestion(ctx context.Context, tenderID, questionID string) (*models.Question, error) {
+	question := &models.Question{}
+	err := scanQuestion(p.DB.QueryRow(ctx, `
+	SELECT `+questionColumns+`
+	FROM tender_question
+		WHERE id = $1 AND tender_id = $2;`, questionID, tenderID), question)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrQuestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// GetQuestions lists the questions on a tender, oldest first. Unless all is
+// set only the publicly answered questions and those of the user are
+// listed.
+func (p *Postgres) GetQuestions(ctx context.Context, tenderID, userID string, all bool, limit, offset int32) ([]*models.Question, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT `+questionColumns+`
+	FROM tender_question
+		WHERE tender_id = $1
+		AND ($3 OR asker_id = $2 OR visibility = 'Public')
+	ORDER BY created_at ASC, id ASC
+	LIMIT $4 OFFSET $5;`, tenderID, userID, all, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []*models.Question{}
+	for rows.Next() {
+		question := &models.Question{}
+		if err := scanQuestion(rows, question); err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+// AnswerQuestion answers a question once and notifies the asker. A public
+// answer is also sent to the authors of the open bids on the tender.
+func (p *Postgres) AnswerQuestion(ctx context.Context, tenderID, questionID, userID string, answer *models.QuestionAnswer, amendedVersion *int32) (question *models.Question, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	question = &models.Question{}
+	err = scanQuestion(tx.QueryRow(ctx, `
+	UPDATE tender_question
+	SET
+		answer = $4,
+		visibility = $5,
+		answered_by = $3,
+		answered_at = NOW(),
+		amended_version = $6
+	WHERE id = $1
+		AND tender_id = $2
+		AND answer IS NULL
+	RETURNING `+questionColumns+`;`, questionID, tenderID, userID, answer.Answer, answer.Visibility, amendedVersion), question)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM tender_question
+				WHERE id = $1 AND tender_id = $2
+		);`, questionID, tenderID).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			return nil, repository.ErrQuestionAnswered
+		}
+		return nil, repository.ErrQuestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO notification
+		(recipient_id, kind, tender_id, message)
+	SELECT
+		recipient_id, $2, $3, $4
+	FROM (
+		SELECT $1::uuid AS recipient_id
+		UNION
+		SELECT author_id
+		FROM bid
+			WHERE tender_id = $3
+			AND status IN ('Created', 'Published')
+			AND $5
+	) recipients;`, question.AskerID, models.NotificationKindQuestionAnswered, tenderID, answer.Answer,
+		answer.Visibility == models.QuestionVisibilityPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// tenderColumns lists the tender columns in the order scanTender reads them.
+const tenderColumns = `id, name, description, service_type, status, organization_id, version, created_at, creator_username,
+		COALESCE(cancellation_reason, ''), submission_deadline, sealed, revealed_at,
+		auction_start, auction_step, auction_extension_seconds, attachment_ids, visibility, prequalification`
+
+// tenderVersionColumns is tenderColumns for tender_version. Versions do not
+// track the reveal, the auction, the visibility and the prequalification,
+// which cannot be edited.
+const tenderVersionColumns = `tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username,
+		COALESCE(cancellation_reason, ''), submission_deadline, sealed, NULL::timestamptz,
+		NULL::timestamptz, NULL::numeric, NULL::int, attachment_ids, '', FALSE`
+
+func scanTender(row pgx.Row, tender *models.TenderResponse) error {
+	var (
+		auctionStart     *time.Time
+		auctionStep      *float64
+		auctionExtension *int32
+	)
+
+	err := row.Scan(
+		&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
+		&tender.OrganizationID, &tender.Version, &tender.CreatedAt, &tender.CreatorUsername,
+		&tender.CancellationReason, &tender.SubmissionDeadline, &tender.Sealed, &tender.RevealedAt,
+		&auctionStart, &auctionStep, &auctionExtension, &tender.AttachmentIDs, &tender.Visibility, &tender.Prequalification)
+	if err != nil {
+		return err
+	}
+
+	if auctionStart != nil {
+		tender.Auction = &models.Auction{Start: *auctionStart, Step: *auctionStep, ExtensionSeconds: *auctionExtension}
+	}
+
+	return nil
+}
+
+// bidColumns lists the bid columns in the order scanBid reads them.
+const bidColumns = `id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at,
+		COALESCE(withdrawal_reason, ''), sealed_content IS NOT NULL, price, priced_at, attachment_ids`
+
+// bidVersionColumns is bidColumns for bid_version.
+const bidVersionColumns = `bid_id, name, COALESCE(description, ''), status, tender_id, author_type, author_id, organization_id, version, created_at,
+		COALESCE(withdrawal_reason, ''), sealed_content IS NOT NULL, price, priced_at, attachment_ids`
+
+func scanBid(row pgx.Row, bid *models.BidResponse) error {
+	return row.Scan(
+		&bid.ID, &bid.Name, &bid.Description, &bid.Status, &bid.TenderID,
+		&bid.AuthorType, &bid.AuthorID, &bid.OrganizationID, &bid.Version, &bid.CreatedAt,
+		&bid.WithdrawalReason, &bid.Sealed, &bid.Price, &bid.PricedAt, &bid.AttachmentIDs)
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *Postgres) GetTenderKey(ctx context.Context, tenderID string) ([]byte, error) {
+	var wrappedKey []byte
+	err := p.DB.QueryRow(ctx, `
+	SELECT wrapped_key
+	FROM tender_key
+		WHERE tender_id = $1;`, tenderID).Scan(&wrappedKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	return wrappedKey, err
+}
+
+// ChangeSealedBid replaces the sealed content of a bid, keeping the previous
+// content as a version.
+func (p *Postgres) ChangeSealedBid(ctx context.Context, bidID string, sealedContent []byte) (bid *models.BidResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO bid_version 
+		(bid_id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids) 
+	SELECT
+		id, name, description, status, tender_id, author_type, author_id, organization_id, version, created_at, withdrawal_reason,
+		sealed_content, price, priced_at, attachment_ids
+	FROM bid
+	WHERE id = $1;`, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if pgCmd.RowsAffected() == 0 {
+		return nil, repository.ErrBidNotFound
+	}
+
+	bid = &models.BidResponse{}
+	err = scanBid(tx.QueryRow(ctx, `
+	UPDATE bid
+	SET
+		sealed_content = $2,
+		version = version + 1
+	WHERE id = $1
+	returning `+bidColumns+`;`, bidID, sealedContent), bid)
+	if err != nil {
+		return nil, err
+	}
+
+	return bid, nil
+}
+
+// GetSealedBidContents returns the sealed content of every bid on the tender
+// and of every version of those bids.
+func (p *Postgres) GetSealedBidContents(ctx context.Context, tenderID string) ([]*models.SealedContent, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT id, version, sealed_content
+	FROM bid
+		WHERE tender_id = $1 AND sealed_content IS NOT NULL
+	UNION ALL
+	SELECT bid_id, version, sealed_content
+	FROM bid_version
+		WHERE tender_id = $1 AND sealed_content IS NOT NULL;`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := []*models.SealedContent{}
+	for rows.Next() {
+		content := &models.SealedContent{}
+		if err := rows.Scan(&content.BidID, &content.Version, &content.Content); err != nil {
+			return nil, err
+		}
+
+		contents = append(contents, content)
+	}
+
+	return contents, rows.Err()
+}
+
+// RevealTender writes the decrypted bids in place of their sealed content and
+// records the reveal in the audit log. The reveal is all or nothing: content
+// that was sealed after the bids were read aborts it.
+func (p *Postgres) RevealTender(ctx context.Context, tenderID, username string, bids []*models.RevealedBid) (tender *models.TenderResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	tender = &models.TenderResponse{}
+	err = scanTender(tx.QueryRow(ctx, `
+	UPDATE tender
+	SET revealed_at = NOW()
+	WHERE id = $1
+		AND sealed
+		AND revealed_at IS NULL
+		AND submission_deadline <= NOW()
+	returning `+tenderColumns+`;`, tenderID), tender)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrTenderNotRevealable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(bids))
+	versions := make([]int, 0, len(bids))
+	names := make([]string, 0, len(bids))
+	descriptions := make([]string, 0, len(bids))
+	for _, bid := range bids {
+		ids = append(ids, bid.BidID)
+		versions = append(versions, bid.Version)
+		names = append(names, bid.Name)
+		descriptions = append(descriptions, bid.Description)
+	}
+
+	_, err = tx.Exec(ctx, `
+	WITH revealed AS (
+		SELECT * FROM unnest($1::uuid[], $2::int[], $3::text[], $4::text[]) AS r(bid_id, version, name, description)
+	)
+	UPDATE bid b
+	SET
+		name = r.name,
+		description = r.description,
+		sealed_content = NULL
+	FROM revealed r
+	WHERE b.id = r.bid_id AND b.version = r.version AND b.sealed_content IS NOT NULL;`, ids, versions, names, descriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	WITH revealed AS (
+		SELECT * FROM unnest($1::uuid[], $2::int[], $3::text[], $4::text[]) AS r(bid_id, version, name, description)
+	)
+	UPDATE bid_version bv
+	SET
+		name = r.name,
+		description = r.description,
+		sealed_content = NULL
+	FROM revealed r
+	WHERE bv.bid_id = r.bid_id AND bv.version = r.version AND bv.sealed_content IS NOT NULL;`, ids, versions, names, descriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var sealed bool
+	err = tx.QueryRow(ctx, `
+	SELECT
+		EXISTS (SELECT 1 FROM bid WHERE tender_id = $1 AND sealed_content IS NOT NULL)
+		OR EXISTS (SELECT 1 FROM bid_version WHERE tender_id = $1 AND sealed_content IS NOT NULL);`, tenderID).Scan(&sealed)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		return nil, fmt.Errorf("tender %s still has sealed bids after the reveal", tenderID)
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO audit_log (actor_id, action, tender_id, details)
+	VALUES ((SELECT id FROM employee WHERE username = $1), $2, $3, $4);`, username, models.AuditActionTenderRevealed, tenderID, "sealed bids revealed: "+strconv.Itoa(countBids(bids)))
+	if err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+// countBids counts the bids behind the revealed versions.
+func countBids(bids []*models.RevealedBid) int {
+	ids := make(map[string]struct{}, len(bids))
+	for _, bid := range bids {
+		ids[bid.BidID] = struct{}{}
+	}
+
+	return len(ids)
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/jackc/pgx/v5"
+)
+
+// Seed writes the dataset in one transaction. With reset all tables are
+// truncated first, otherwise the data is added to what is already there.
+func (p *Postgres) Seed(ctx context.Context, d *seed.Dataset, reset bool) (err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if reset {
+		if _, err = tx.Exec(ctx, `
+		TRUNCATE employee, organization, organization_responsible, tender, tender_version,
+			bid, bid_version, bid_decision, bid_message, bid_message_attachment, bid_message_read,
+			notification, tender_key, audit_log, tender_criterion, bid_score, tender_question, attachment,
+			tender_invitation, prequalification_questionnaire, prequalification_question, prequalification_application,
+			prequalification_answer, debarment;`); err != nil {
+			return err
+		}
+	}
+
+	tables := []struct {
+		name    string
+		columns []string
+		rows    [][]any
+	}{
+		{
+			name:    "organization",
+			columns: []string{"id", "name", "description", "type", "created_at", "updated_at"},
+			rows: rowsOf(d.Organizations, func(o int) []any {
+				org := d.Organizations[o]
+				return []any{org.ID, org.Name, org.Description, org.Type, org.CreatedAt, org.UpdatedAt}
+			}),
+		},
+		{
+			name:    "employee",
+			columns: []string{"id", "username", "first_name", "last_name", "created_at", "updated_at"},
+			rows: rowsOf(d.Employees, func(i int) []any {
+				e := d.Employees[i]
+				return []any{e.ID, e.Username, e.FirstName, e.LastName, e.CreatedAt, e.UpdatedAt}
+			}),
+		},
+		{
+			name:    "organization_responsible",
+			columns: []string{"id", "organization_id", "user_id"},
+			rows: rowsOf(d.Responsibles, func(i int) []any {
+				r := d.Responsibles[i]
+				return []any{r.ID, r.OrganizationID, r.UserID}
+			}),
+		},
+		{
+			name:    "tender",
+			columns: []string{"id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "creator_username", "visibility"},
+			rows: rowsOf(d.Tenders, func(i int) []any {
+				t := d.Tenders[i]
+				return []any{t.ID, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID, t.Version, t.CreatedAt, t.CreatorUsername, t.Visibility}
+			}),
+		},
+		{
+			name:    "tender_version",
+			columns: []string{"tender_id", "name", "description", "service_type", "status", "organization_id", "version", "created_at", "creator_username"},
+			rows: rowsOf(d.TenderVersions, func(i int) []any {
+				t := d.TenderVersions[i]
+				return []any{t.ID, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID, t.Version, t.CreatedAt, t.CreatorUsername}
+			}),
+		},
+		{
+			name:    "bid",
+			columns: []string{"id", "name", "description", "status", "tender_id", "author_type", "author_id", "organization_id", "version", "created_at"},
+			rows: rowsOf(d.Bids, func(i int) []any {
+				b := d.Bids[i]
+				return []any{b.ID, b.Name, b.Description, b.Status, b.TenderID, b.AuthorType, b.AuthorID, b.OrganizationID, b.Version, b.CreatedAt}
+			}),
+		},
+		{
+			name:    "bid_version",
+			columns: []string{"bid_id", "name", "description", "status", "tender_id", "author_type", "author_id", "organization_id", "version", "created_at"},
+			rows: rowsOf(d.BidVersions, func(i int) []any {
+				b := d.BidVersions[i]
+				return []any{b.ID, b.Name, b.Description, b.Status, b.TenderID, b.AuthorType, b.AuthorID, b.OrganizationID, b.Version, b.CreatedAt}
+			}),
+		},
+		{
+			name:    "bid_decision",
+			columns: []string{"id", "bid_id", "user_id", "decision"},
+			rows: rowsOf(d.Decisions, func(i int) []any {
+				dec := d.Decisions[i]
+				return []any{dec.ID, dec.BidID, dec.UserID, dec.Decision}
+			}),
+		},
+		{
+			name:    "bid_message",
+			columns: []string{"id", "bid_id", "author_id", "role", "reply_to", "body", "created_at"},
+			rows: rowsOf(d.Messages, func(i int) []any {
+				m := d.Messages[i]
+				return []any{m.ID, m.BidID, m.AuthorID, m.Role, m.ReplyTo, m.Body, m.CreatedAt}
+			}),
+		},
+	}
+
+	// Plain inserts rather than COPY: the enum columns are not registered
+	// with pgx, and only the text protocol encodes them without that.
+	for _, table := range tables {
+		params := make([]string, len(table.columns))
+		for i := range params {
+			params[i] = fmt.Sprintf("$%d", i+1)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+			pgx.Identifier{table.name}.Sanitize(), strings.Join(table.columns, ", "), strings.Join(params, ", "))
+
+		batch := &pgx.Batch{}
+		for _, row := range table.rows {
+			batch.Queue(query, row...)
+		}
+		if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to seed %s: %w", table.name, err)
+		}
+	}
+
+	return nil
+}
+
+func rowsOf[T any](items []T, row func(i int) []any) [][]any {
+	rows := make([][]any, len(items))
+	for i := range items {
+		rows[i] = row(i)
+	}
+
+	return rows
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
@@ -17,7 +18,7 @@ import (
 
 func (p *Postgres) GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error) {
 	rows, err := p.DB.Query(ctx, `
-	SELECT *
+	SELECT `+tenderColumns+`
 	FROM tender
 		WHERE creator_username = $1
 	ORDER BY name ASC
@@ -25,24 +26,11 @@ func (p *Postgres) GetUserTenders(ctx context.Context, username string, limit, o
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	tenders := []*models.TenderResponse{}
-	for rows.Next() {
-		tender := &models.TenderResponse{}
-		if err := rows.Scan(
-			&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
-			&tender.OrganizationID, &tender.Version, &tender.CreatedAt, &tender.CreatorUsername); err != nil {
-			return nil, err
-		}
 
-		tenders = append(tenders, tender)
-	}
-
-	return tenders, nil
+	return collectTenders(rows)
 }
 
-func (p *Postgres) GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, limit, offset int32) ([]*models.TenderResponse, error) {
+func (p *Postgres) GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, userID string, limit, offset int32) ([]*models.TenderResponse, error) {
 	var filter string
 	if len(serviceType) != 0 {
 		var types []string
@@ -57,33 +45,20 @@ func (p *Postgres) GetAllTenders(ctx context.Context, serviceType []models.Tende
 	}
 
 	query := fmt.Sprintf(`
-	SELECT 
-		id, name, description, service_type, status, organization_id, version, created_at
-	FROM tender
+	SELECT `+tenderColumns+`
+	FROM tender t
 	WHERE status = 'Published'
+	AND `+tenderVisibleTo(3)+`
 	%s
 	ORDER BY name ASC 
 	LIMIT $1 OFFSET $2;`, filter)
 
-	rows, err := p.DB.Query(ctx, query, limit, offset)
+	rows, err := p.DB.Query(ctx, query, limit, offset, userID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	tenders := []*models.TenderResponse{}
-	for rows.Next() {
-		tender := &models.TenderResponse{}
-		if err := rows.Scan(
-			&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
-			&tender.OrganizationID, &tender.Version, &tender.CreatedAt); err != nil {
-			return nil, err
-		}
-
-		tenders = append(tenders, tender)
-	}
-
-	return tenders, nil
+	return collectTenders(rows)
 }
 
 
@@ -103,16 +78,37 @@ func (p *Postgres) GetStatusOfTender(ctx context.Context, tenderID string) (*mod
 	return status, organizationID, err
 }
 
-func (p *Postgres) BuildTender(ctx context.Context, tender *models.TenderCreate) (*models.TenderResponse, error) {
+// BuildTender stores the wrapped data key along with a sealed tender and the
+// invitations along with an invitation-only one.
+func (p *Postgres) BuildTender(ctx context.Context, tender *models.TenderCreate, wrappedKey []byte) (*models.TenderResponse, error) {
 	tenderResp := &models.TenderResponse{}
 
-	err := p.DB.QueryRow(ctx, `
-	insert into tender 
-		(name, description, service_type, organization_id, creator_username) 
-	values ($1, $2, $3, $4, $5) returning *;`,
-		tender.Name, tender.Description, tender.ServiceType, tender.OrganizationID, tender.CreatorUsername).Scan(
-		&tenderResp.ID, &tenderResp.Name, &tenderResp.Description, &tenderResp.ServiceType,
-		&tenderResp.Status, &tenderResp.OrganizationID, &tenderResp.Version, &tenderResp.CreatedAt, &tenderResp.CreatorUsername)
+	var (
+		auctionStart     *time.Time
+		auctionStep      *float64
+		auctionExtension *int32
+	)
+	if tender.Auction != nil {
+		auctionStart, auctionStep, auctionExtension = &tender.Auction.Start, &tender.Auction.Step, &tender.Auction.ExtensionSeconds
+	}
+
+	err := scanTender(p.DB.QueryRow(ctx, `
+	with created as (
+		insert into tender 
+			(name, description, service_type, organization_id, creator_username, submission_deadline, sealed,
+			auction_start, auction_step, auction_extension_seconds, visibility, prequalification) 
+		values ($1, $2, $3, $4, $5, $6, $7, $9, $10, $11, $12, $14) returning *
+	), key as (
+		insert into tender_key (tender_id, wrapped_key)
+		select id, $8 from created where $8::bytea is not null
+	), invited as (
+		insert into tender_invitation (tender_id, organization_id)
+		select id, unnest($13::uuid[]) from created
+	)
+	select `+tenderColumns+` from created;`,
+		tender.Name, tender.Description, tender.ServiceType, tender.OrganizationID, tender.CreatorUsername,
+		tender.SubmissionDeadline, tender.Sealed, wrappedKey, auctionStart, auctionStep, auctionExtension,
+		tender.Visibility, tender.InvitedOrganizations, tender.Prequalification), tenderResp)
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
@@ -128,13 +124,11 @@ func (p *Postgres) BuildTender(ctx context.Context, tender *models.TenderCreate)
 func (p *Postgres) RefreshTenderStatus(ctx context.Context, tenderID string, status models.TenderStatus) (*models.TenderResponse, error) {
 	tender := &models.TenderResponse{}
 
-	err := p.DB.QueryRow(ctx, `
+	err := scanTender(p.DB.QueryRow(ctx, `
 	UPDATE tender
 	SET status = $2::tender_status
 	WHERE id = $1
-	returning *;`, tenderID, status).Scan(
-		&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
-		&tender.OrganizationID, &tender.Version, &tender.CreatedAt, &tender.CreatorUsername)
+	returning `+tenderColumns+`;`, tenderID, status), tender)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrTenderNotFound
@@ -178,9 +172,11 @@ func (p *Postgres) UpdateTender(ctx context.Context, tenderID string, tenderEdit
 
 	pgCmd, err := tx.Exec(ctx, `
 	INSERT INTO tender_version 
-		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username) 
+		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids) 
 	SELECT
-    	id, name, description, service_type, status, organization_id, version, created_at, creator_username
+    	id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids
 	FROM tender
 	WHERE id = $1;`, tenderID)
 	if pgCmd.RowsAffected() == 0 {
@@ -206,12 +202,10 @@ func (p *Postgres) UpdateTender(ctx context.Context, tenderID string, tenderEdit
 	}
 
 	values = append(values, tenderID)
-	query := fmt.Sprintf(`update tender set %s, version = version + 1 where id = $%v returning *;`, strings.Join(keys, ", "), len(values))
+	query := fmt.Sprintf(`update tender set %s, version = version + 1 where id = $%v returning `+tenderColumns+`;`, strings.Join(keys, ", "), len(values))
 
 	tender := &models.TenderResponse{}
-	err = tx.QueryRow(ctx, query, values...).Scan(
-		&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
-		&tender.OrganizationID, &tender.Version, &tender.CreatedAt, &tender.CreatorUsername)
+	err = scanTender(tx.QueryRow(ctx, query, values...), tender)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrTenderNotFound
 	}
@@ -235,9 +229,11 @@ func (p *Postgres) RollbackTender(ctx context.Context, tenderID string, version
 
 	pgCmd, err := tx.Exec(ctx, `
 	INSERT INTO tender_version 
-		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username) 
+		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids) 
 	SELECT
-    	id, name, description, service_type, status, organization_id, version, created_at, creator_username
+    	id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids
 	FROM tender
 	WHERE id = $1;`, tenderID)
 	if pgCmd.RowsAffected() == 0 {
@@ -245,28 +241,32 @@ func (p *Postgres) RollbackTender(ctx context.Context, tenderID string, version
 	}
 
 	tender := &models.TenderResponse{}
-	err = tx.QueryRow(ctx, `
+	err = scanTender(tx.QueryRow(ctx, `
 	with tv as (
 		select
-			name, description, service_type, status, organization_id, version, created_at, creator_username
+			name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+			submission_deadline, sealed, attachment_ids
 		from tender_version
 			where tender_id = $1 and version = $2
+	), updated as (
+		update tender t
+		set
+			name = tv.name,
+			description = tv.description,
+			service_type = tv.service_type,
+			status = tv.status,
+			organization_id = tv.organization_id,
+			version = t.version + 1,
+			created_at = tv.created_at,
+			creator_username = tv.creator_username,
+			cancellation_reason = tv.cancellation_reason,
+			sealed = tv.sealed,
+			attachment_ids = tv.attachment_ids
+		from tv
+			where t.id = $1 
+		returning t.*
 	)
-	update tender t
-	set
-		name = tv.name,
-		description = tv.description,
-		service_type = tv.service_type,
-		status = tv.status,
-		organization_id = tv.organization_id,
-		version = t.version + 1,
-		created_at = tv.created_at,
-		creator_username = tv.creator_username
-	from tv
-		where t.id = $1 
-	returning t.*;`, tenderID, version).Scan(
-		&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status,
-		&tender.OrganizationID, &tender.Version, &tender.CreatedAt, &tender.CreatorUsername)
+	select `+tenderColumns+` from updated;`, tenderID, version), tender)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repository.ErrTenderORVersionNotFound
 	}
@@ -274,3 +274,101 @@ func (p *Postgres) RollbackTender(ctx context.Context, tenderID string, version
 	return tender, err
 }
 
+
+func (p *Postgres) CountOpenTendersByServiceType(ctx context.Context) (map[models.TenderServiceType]int, error) {
+	rows, err := p.DB.Query(ctx, `
+	SELECT
+		service_type, COUNT(*)
+	FROM tender
+		WHERE status = 'Published'
+	GROUP BY service_type;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[models.TenderServiceType]int)
+	for rows.Next() {
+		var serviceType models.TenderServiceType
+		var count int
+		if err := rows.Scan(&serviceType, &count); err != nil {
+			return nil, err
+		}
+
+		counts[serviceType] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// CancelTender cancels the tender, moves its open bids to TenderCancelled and
+// notifies their authors, all in one transaction.
+func (p *Postgres) CancelTender(ctx context.Context, tenderID, reason string) (tender *models.TenderResponse, err error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	pgCmd, err := tx.Exec(ctx, `
+	INSERT INTO tender_version 
+		(tender_id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids) 
+	SELECT
+    	id, name, description, service_type, status, organization_id, version, created_at, creator_username, cancellation_reason,
+		submission_deadline, sealed, attachment_ids
+	FROM tender
+	WHERE id = $1;`, tenderID)
+	if pgCmd.RowsAffected() == 0 {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	tender = &models.TenderResponse{}
+	err = scanTender(tx.QueryRow(ctx, `
+	UPDATE tender
+	SET
+		status = 'Cancelled',
+		cancellation_reason = $2,
+		version = version + 1
+	WHERE id = $1
+		AND status IN ('Created', 'Published')
+	returning `+tenderColumns+`;`, tenderID, reason), tender)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrTenderNotCancellable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+	WITH cancelled AS (
+		UPDATE bid
+		SET status = 'TenderCancelled'
+		WHERE tender_id = $1
+			AND status IN ('Created', 'Published')
+		RETURNING id, tender_id, author_id, organization_id
+	)
+	INSERT INTO notification
+		(recipient_id, kind, tender_id, bid_id, message)
+	SELECT
+		recipient_id, $2, tender_id, id, $3
+	FROM (
+		SELECT id, tender_id, author_id AS recipient_id FROM cancelled
+		UNION
+		SELECT c.id, c.tender_id, r.user_id
+		FROM cancelled c
+			JOIN organization_responsible r ON r.organization_id = c.organization_id
+	) recipients;`, tenderID, models.NotificationKindTenderCancelled, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
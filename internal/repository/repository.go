@@ -7,32 +7,37 @@ import (
 )
 
 type TenderRepository interface {
-	GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, limit, offset int32) ([]*models.TenderResponse, error)
-	BuildTender(ctx context.Context, tender *models.TenderCreate) (*models.TenderResponse, error)
+	GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, userID string, limit, offset int32) ([]*models.TenderResponse, error)
+	BuildTender(ctx context.Context, tender *models.TenderCreate, wrappedKey []byte) (*models.TenderResponse, error)
 	GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error)
 	GetStatusOfTender(ctx context.Context, tenderID string) (*models.TenderStatus, *models.OrganizationID, error)
 	RefreshTenderStatus(ctx context.Context, tenderID string, status models.TenderStatus) (*models.TenderResponse, error)
 	UpdateTender(ctx context.Context, tenderID string, tenderEdit *models.TenderEdit) (*models.TenderResponse, error)
 	RollbackTender(ctx context.Context, tenderID string, version int32) (*models.TenderResponse, error)
+	CountOpenTendersByServiceType(ctx context.Context) (map[models.TenderServiceType]int, error)
+	CancelTender(ctx context.Context, tenderID, reason string) (*models.TenderResponse, error)
 
 	ControlOrganizationPermission(ctx context.Context, organizationID *models.OrganizationID, username string) error
+	ControlOrganizationPermissionByID(ctx context.Context, organizationID *models.OrganizationID, userID string) error
 	ControlTendersCreationByName(ctx context.Context, tenderId, creatorUsername string) error
 	ControlUserResponsibility(ctx context.Context, userId string) error
 	ControlBidCreationByID(ctx context.Context, username string) (string, error)
+	ControlTenderVisibility(ctx context.Context, tenderID, userID string) error
 	IsTenderPudlished(ctx context.Context, tenderID string) error
 }
 
 type BidRepository interface {
-	ConstructBid(ctx context.Context, bid *models.BidCreate) (*models.BidResponse, error)
+	ConstructBid(ctx context.Context, bid *models.BidCreate, sealed *models.SealedContent) (*models.BidResponse, error)
 	GetBidsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.BidResponse, error)
 	GetBidsOfTender(ctx context.Context, tenderID string, limit, offset int32) ([]*models.BidResponse, error)
 	GetBidsWithID(ctx context.Context, bidID string) (*models.BidResponse, error)
 	RenewStatusOfBid(ctx context.Context, bidID, username string, status *models.BidStatus) (*models.BidResponse, error)
 	ChangeBid(ctx context.Context, bidID string, bidEdit *models.BidEdit) (*models.BidResponse, error)
 	ApplyBidDecision(ctx context.Context, bidID, username string, decision *models.BidDecision) (*models.BidResponse, error)
-	ApplyBidFeedback(ctx context.Context, bidID string, feedback *models.BidFeedback) error
 	CancelChangesOfBid(ctx context.Context, bidID string, version int32) (*models.BidResponse, error)
 	GetCommentsOfBid(ctx context.Context, tenderID, authorUsername string, limit, offset int32) ([]*models.BidReviewResponse, error)
+	WithdrawBid(ctx context.Context, bidID, reason string) (*models.BidResponse, error)
+	GetBidStatusesOfAuthor(ctx context.Context, tenderID, authorID string, organizationID *models.OrganizationID) ([]models.BidStatus, error)
 	
 	ControlBidCreationByName(ctx context.Context, bidID, creatorUsername string) error
 	ControlUserResponsibilityForTender(ctx context.Context, tenderID, username string) error
@@ -42,8 +47,125 @@ type BidRepository interface {
 	CountApplyedDecisions(ctx context.Context, bidID string) (int, error)
 }
 
+type LoaderRepository interface {
+	GetTendersByIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error)
+	GetTenderVersionsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.TenderResponse, error)
+	GetBidsByIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error)
+	GetBidsByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.BidResponse, error)
+	GetBidVersionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidResponse, error)
+	GetDecisionsByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidDecisionResponse, error)
+	GetFeedbackByBidIDs(ctx context.Context, bidIDs []string) ([]*models.BidReviewResponse, error)
+	GetEmployeesByIDs(ctx context.Context, userIDs []string) ([]*models.Employee, error)
+	GetEmployeesByUsernames(ctx context.Context, usernames []string) ([]*models.Employee, error)
+	GetOrganizationsByIDs(ctx context.Context, organizationIDs []string) ([]*models.Organization, error)
+	GetCriteriaByTenderIDs(ctx context.Context, tenderIDs []string) ([]*models.Criterion, error)
+	GetAttachmentsByIDs(ctx context.Context, attachmentIDs []string) ([]*models.Attachment, error)
+}
+
+type MessageRepository interface {
+	PostBidMessage(ctx context.Context, bidID, authorID string, role models.MessageRole, message *models.BidMessageCreate) (*models.BidMessage, error)
+	GetBidMessages(ctx context.Context, bidID string, limit, offset int32) ([]*models.BidMessage, error)
+	MarkBidMessagesRead(ctx context.Context, bidID, userID string) (int, error)
+}
+
+type AttachmentRepository interface {
+	AttachToTender(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	DetachFromTender(ctx context.Context, tenderID, attachmentID string) (*models.TenderResponse, error)
+	AttachToBid(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	DetachFromBid(ctx context.Context, bidID, attachmentID string) (*models.BidResponse, error)
+	GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error)
+	GetQuarantinedAttachments(ctx context.Context, limit int32) ([]*models.Attachment, error)
+	RecordAttachmentScan(ctx context.Context, attachmentID string, scan *models.AttachmentScan) (*models.Attachment, error)
+}
+
+type NotificationRepository interface {
+	GetNotifications(ctx context.Context, userID string, limit, offset int32) ([]*models.Notification, error)
+}
+
+type SealingRepository interface {
+	GetTenderKey(ctx context.Context, tenderID string) ([]byte, error)
+	ChangeSealedBid(ctx context.Context, bidID string, sealedContent []byte) (*models.BidResponse, error)
+	GetSealedBidContents(ctx context.Context, tenderID string) ([]*models.SealedContent, error)
+	RevealTender(ctx context.Context, tenderID, username string, bids []*models.RevealedBid) (*models.TenderResponse, error)
+}
+
+type AuctionRepository interface {
+	SubmitAuctionPrice(ctx context.Context, bidID string, price float64) (*models.BidResponse, error)
+	ExtendAuction(ctx context.Context, tenderID string) (bool, error)
+	GetAuctionRanking(ctx context.Context, tenderID, userID string) ([]*models.AuctionRank, error)
+	CloseEndedAuctions(ctx context.Context) ([]*models.AuctionResult, error)
+}
+
+type EvaluationRepository interface {
+	SetTenderCriteria(ctx context.Context, tenderID string, criteria []*models.CriterionCreate) ([]*models.Criterion, error)
+	ScoreBid(ctx context.Context, bidID, userID string, scores []*models.CriterionScore) ([]*models.BidScore, error)
+	GetScoresOfTender(ctx context.Context, tenderID string) ([]*models.BidScore, error)
+	AwardBid(ctx context.Context, bidID, username, details string) (*models.BidResponse, error)
+}
+
+type QuestionRepository interface {
+	AskQuestion(ctx context.Context, tenderID, askerID, question string) (*models.Question, error)
+	GetQuestion(ctx context.Context, tenderID, questionID string) (*models.Question, error)
+	GetQuestions(ctx context.Context, tenderID, userID string, all bool, limit, offset int32) ([]*models.Question, error)
+	AnswerQuestion(ctx context.Context, tenderID, questionID, userID string, answer *models.QuestionAnswer, amendedVersion *int32) (*models.Question, error)
+}
+
+type InvitationRepository interface {
+	InviteOrganizations(ctx context.Context, tenderID string, organizationIDs []models.OrganizationID) ([]*models.TenderInvitation, error)
+	GetTenderInvitations(ctx context.Context, tenderID string) ([]*models.TenderInvitation, error)
+	GetInvitedTenders(ctx context.Context, userID string, limit, offset int32) ([]*models.TenderResponse, error)
+}
+
+type DebarmentRepository interface {
+	CreateDebarment(ctx context.Context, userID string, debarment *models.DebarmentCreate, details string) (*models.Debarment, error)
+	GetDebarment(ctx context.Context, debarmentID string) (*models.Debarment, error)
+	// GetDebarments lists the debarments of the organization and the global
+	// ones, or all of them without an organization, the latest first.
+	GetDebarments(ctx context.Context, organizationID *models.OrganizationID, limit, offset int32) ([]*models.Debarment, error)
+	// GetDebarmentsOfUser lists the debarments of the organizations of the
+	// user that are neither lifted nor over.
+	GetDebarmentsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Debarment, error)
+	UpdateDebarment(ctx context.Context, debarmentID, userID string, edit *models.DebarmentEdit, details string) (*models.Debarment, error)
+	LiftDebarment(ctx context.Context, debarmentID, userID, reason string) (*models.Debarment, error)
+	GetDebarmentAudit(ctx context.Context, debarmentID string, limit, offset int32) ([]*models.AuditRecord, error)
+	// ControlDebarment returns a *DebarredError when a debarment in force
+	// blocks the organization, or any organization of the user without one,
+	// from the tender.
+	ControlDebarment(ctx context.Context, tenderID, userID string, organizationID *models.OrganizationID) error
+}
+
+type PrequalificationRepository interface {
+	SetQuestionnaire(ctx context.Context, questionnaire *models.QuestionnaireSet) (*models.Questionnaire, error)
+	GetQuestionnaire(ctx context.Context, questionnaireID string) (*models.Questionnaire, error)
+	FindQuestionnaire(ctx context.Context, organizationID models.OrganizationID, serviceType models.TenderServiceType) (*models.Questionnaire, error)
+	SubmitApplication(ctx context.Context, questionnaireID, userID string, application *models.ApplicationCreate) (*models.Application, error)
+	GetApplication(ctx context.Context, applicationID string) (*models.Application, error)
+	GetApplicationsOfQuestionnaire(ctx context.Context, questionnaireID string, limit, offset int32) ([]*models.Application, error)
+	GetApplicationsOfUser(ctx context.Context, userID string, limit, offset int32) ([]*models.Application, error)
+	ReviewApplication(ctx context.Context, applicationID, userID string, review *models.ApplicationReview) (*models.Application, error)
+	RevokeApplication(ctx context.Context, applicationID, userID, reason string) (*models.Application, error)
+	AttachToApplication(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	GetApplicationLicenses(ctx context.Context, applicationID string) ([]*models.Attachment, error)
+	ControlPrequalification(ctx context.Context, tenderID string, organizationID models.OrganizationID) error
+}
+
+type AuditRepository interface {
+	GetAuditLog(ctx context.Context, tenderID string, limit, offset int32) ([]*models.AuditRecord, error)
+}
 
 type Repository interface {
 	TenderRepository
 	BidRepository
+	LoaderRepository
+	MessageRepository
+	AttachmentRepository
+	NotificationRepository
+	SealingRepository
+	AuctionRepository
+	EvaluationRepository
+	QuestionRepository
+	InvitationRepository
+	PrequalificationRepository
+	DebarmentRepository
+	AuditRepository
 }
@@ -0,0 +1,92 @@
+package repotest
+
+import (
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// The fixture has a buyer organization and a supplier organization with two
+// responsibles each, and an employee who is responsible nowhere.
+const (
+	buyerOrg    models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-000000000001"
+	supplierOrg models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-000000000002"
+	missingOrg  models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-0000000000ff"
+
+	aliceID = "1c9e5b63-3a2f-4d2d-8a1f-000000000001"
+	annaID  = "1c9e5b63-3a2f-4d2d-8a1f-000000000002"
+	bobID   = "1c9e5b63-3a2f-4d2d-8a1f-000000000003"
+	carlID  = "1c9e5b63-3a2f-4d2d-8a1f-000000000004"
+	bellaID = "1c9e5b63-3a2f-4d2d-8a1f-000000000005"
+	nobody  = "1c9e5b63-3a2f-4d2d-8a1f-0000000000ff"
+
+	alice   = "alice"
+	anna    = "anna"
+	bob     = "bob"
+	carl    = "carl"
+	bella   = "bella"
+	unknown = "unknown"
+
+	publishedTender = "2d0f6c74-4b3a-4e3e-9b2a-000000000001"
+	draftTender     = "2d0f6c74-4b3a-4e3e-9b2a-000000000002"
+	closedTender    = "2d0f6c74-4b3a-4e3e-9b2a-000000000003"
+	missingTender   = "2d0f6c74-4b3a-4e3e-9b2a-0000000000ff"
+
+	publishedBid = "3e1a7d85-5c4b-4f4f-8c3b-000000000001"
+	sealedBidID  = "3e1a7d85-5c4b-4f4f-8c3b-000000000002"
+	missingBid   = "3e1a7d85-5c4b-4f4f-8c3b-0000000000ff"
+
+	missingQuestionnaire = "5a3c9fa7-7e6d-4b6b-8e5d-0000000000ff"
+	missingApplication   = "6b4d0ab8-8f7e-4c7c-9f6e-0000000000ff"
+	missingDebarment     = "7c5e1bc9-9a8f-4d8d-8a7f-0000000000ff"
+)
+
+var epoch = time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+
+func fixture() *seed.Dataset {
+	at := func(minutes int) time.Time { return epoch.Add(time.Duration(minutes) * time.Minute) }
+
+	return &seed.Dataset{
+		Organizations: []models.Organization{
+			{ID: buyerOrg, Name: "Buyer", Type: models.OrganizationTypeLLC, CreatedAt: at(0), UpdatedAt: at(0)},
+			{ID: supplierOrg, Name: "Supplier", Type: models.OrganizationTypeIE, CreatedAt: at(0), UpdatedAt: at(0)},
+		},
+		Employees: []models.Employee{
+			{ID: aliceID, Username: alice, CreatedAt: at(1), UpdatedAt: at(1)},
+			{ID: annaID, Username: anna, CreatedAt: at(1), UpdatedAt: at(1)},
+			{ID: bobID, Username: bob, CreatedAt: at(1), UpdatedAt: at(1)},
+			{ID: carlID, Username: carl, CreatedAt: at(1), UpdatedAt: at(1)},
+			{ID: bellaID, Username: bella, CreatedAt: at(1), UpdatedAt: at(1)},
+		},
+		Responsibles: []seed.Responsible{
+			{ID: "4f2b8e96-6d5c-4a5a-9d4c-000000000001", OrganizationID: buyerOrg, UserID: aliceID},
+			{ID: "4f2b8e96-6d5c-4a5a-9d4c-000000000002", OrganizationID: buyerOrg, UserID: annaID},
+			{ID: "4f2b8e96-6d5c-4a5a-9d4c-000000000003", OrganizationID: supplierOrg, UserID: bobID},
+			{ID: "4f2b8e96-6d5c-4a5a-9d4c-000000000004", OrganizationID: supplierOrg, UserID: bellaID},
+		},
+		Tenders: []models.TenderResponse{
+			{
+				ID: publishedTender, Name: "B roof repair", Description: "Roof", ServiceType: models.TenderServiceTypeConstruction,
+				Status: models.TenderStatusPublished, OrganizationID: buyerOrg, Version: 1, CreatedAt: at(2), CreatorUsername: alice,
+				Visibility: models.TenderVisibilityPublic,
+			},
+			{
+				ID: draftTender, Name: "A paper supply", Description: "Paper", ServiceType: models.TenderServiceTypeDelivery,
+				Status: models.TenderStatusCreated, OrganizationID: buyerOrg, Version: 1, CreatedAt: at(3), CreatorUsername: alice,
+				Visibility: models.TenderVisibilityPublic,
+			},
+			{
+				ID: closedTender, Name: "C uniforms", Description: "Uniforms", ServiceType: models.TenderServiceTypeManufacture,
+				Status: models.TenderStatusClosed, OrganizationID: buyerOrg, Version: 1, CreatedAt: at(4), CreatorUsername: anna,
+				Visibility: models.TenderVisibilityPublic,
+			},
+		},
+		Bids: []models.BidResponse{
+			{
+				ID: publishedBid, Name: "Roof offer", Description: "We fix roofs", Status: models.BidStatusPublished,
+				TenderID: publishedTender, AuthorType: models.BidAuthorTypeUser, AuthorID: bobID, Version: 1, CreatedAt: at(5),
+			},
+		},
+	}
+}
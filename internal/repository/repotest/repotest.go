@@ -0,0 +1,1673 @@
+// Package repotest is the conformance suite every repository.Repository
+// implementation has to pass, so that they stay interchangeable.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// Repository is an implementation under test. Seed with reset must leave
+// only the given dataset behind.
+type Repository interface {
+	repository.Repository
+	Seed(ctx context.Context, d *seed.Dataset, reset bool) error
+}
+
+// Run runs the suite. newRepo is called once per test and may return the
+// same repository every time, Run resets it to the fixture.
+func Run(t *testing.T, newRepo func(t *testing.T) Repository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo Repository)
+	}{
+		{"BuildTender", testBuildTender},
+		{"ListTenders", testListTenders},
+		{"TenderStatus", testTenderStatus},
+		{"TenderVersions", testTenderVersions},
+		{"CountOpenTenders", testCountOpenTenders},
+		{"CancelTender", testCancelTender},
+		{"Permissions", testPermissions},
+		{"ConstructBid", testConstructBid},
+		{"ConcurrentConstructBid", testConcurrentConstructBid},
+		{"OrganizationBid", testOrganizationBid},
+		{"ListBids", testListBids},
+		{"BidVersions", testBidVersions},
+		{"Decisions", testDecisions},
+		{"WithdrawBid", testWithdrawBid},
+		{"RevealTender", testRevealTender},
+		{"Auction", testAuction},
+		{"Evaluation", testEvaluation},
+		{"Questions", testQuestions},
+		{"Messages", testMessages},
+		{"Feedback", testFeedback},
+		{"Attachments", testAttachments},
+		{"Visibility", testVisibility},
+		{"Prequalification", testPrequalification},
+		{"Debarment", testDebarment},
+		{"Loaders", testLoaders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t)
+			if err := repo.Seed(context.Background(), fixture(), true); err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+
+			tt.test(t, repo)
+		})
+	}
+}
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func expectErr(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testBuildTender(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	tender, err := repo.BuildTender(ctx, newTender("New", nil), nil)
+	noErr(t, err)
+	if tender.ID == "" || tender.Status != models.TenderStatusCreated || tender.Version != 1 ||
+		tender.OrganizationID != buyerOrg || tender.CreatorUsername != alice {
+		t.Fatalf("unexpected tender %+v", tender)
+	}
+
+	_, err = repo.BuildTender(ctx, newTender("New", func(c *models.TenderCreate) { c.OrganizationID = missingOrg }), nil)
+	expectErr(t, err, repository.ErrOrganizationDepencyNotFound)
+}
+
+// newTender describes a public delivery tender of the buyer, edit adjusts it.
+func newTender(name string, edit func(*models.TenderCreate)) *models.TenderCreate {
+	create := &models.TenderCreate{
+		Name: name, Description: name, ServiceType: models.TenderServiceTypeDelivery,
+		OrganizationID: buyerOrg, CreatorUsername: alice, Visibility: models.TenderVisibilityPublic,
+	}
+	if edit != nil {
+		edit(create)
+	}
+
+	return create
+}
+
+func publishTender(t *testing.T, repo Repository, create *models.TenderCreate, wrappedKey []byte) *models.TenderResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	tender, err := repo.BuildTender(ctx, create, wrappedKey)
+	noErr(t, err)
+
+	tender, err = repo.RefreshTenderStatus(ctx, tender.ID, models.TenderStatusPublished)
+	noErr(t, err)
+
+	return tender
+}
+
+// rollBack changes the tender and rolls it back to its first version, for
+// the attributes that are not versioned.
+func rollBack(t *testing.T, repo Repository, tenderID string) *models.TenderResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := repo.UpdateTender(ctx, tenderID, &models.TenderEdit{Name: ptr("Renamed")})
+	noErr(t, err)
+
+	tender, err := repo.RollbackTender(ctx, tenderID, 1)
+	noErr(t, err)
+
+	return tender
+}
+
+func testListTenders(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	all, err := repo.GetAllTenders(ctx, nil, "", 10, 0)
+	noErr(t, err)
+	if len(all) != 1 || all[0].ID != publishedTender {
+		t.Fatalf("only the published tender expected, got %+v", all)
+	}
+
+	filtered, err := repo.GetAllTenders(ctx, []models.TenderServiceType{models.TenderServiceTypeDelivery}, "", 10, 0)
+	noErr(t, err)
+	if len(filtered) != 0 {
+		t.Fatalf("no published delivery tenders expected, got %d", len(filtered))
+	}
+
+	mine, err := repo.GetUserTenders(ctx, alice, 10, 0)
+	noErr(t, err)
+	if len(mine) != 2 || mine[0].ID != draftTender || mine[1].ID != publishedTender {
+		t.Fatalf("alice's tenders ordered by name expected, got %+v", mine)
+	}
+
+	paged, err := repo.GetUserTenders(ctx, alice, 1, 1)
+	noErr(t, err)
+	if len(paged) != 1 || paged[0].ID != publishedTender {
+		t.Fatalf("second page expected, got %+v", paged)
+	}
+}
+
+func testTenderStatus(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	status, organizationID, err := repo.GetStatusOfTender(ctx, draftTender)
+	if err != nil || *status != models.TenderStatusCreated || *organizationID != buyerOrg {
+		t.Fatalf("unexpected status %v, %v, %v", status, organizationID, err)
+	}
+
+	_, _, err = repo.GetStatusOfTender(ctx, missingTender)
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	expectErr(t, repo.IsTenderPudlished(ctx, draftTender), repository.ErrTenderClosed)
+	expectErr(t, repo.IsTenderPudlished(ctx, missingTender), repository.ErrTenderNotFound)
+
+	tender, err := repo.RefreshTenderStatus(ctx, draftTender, models.TenderStatusPublished)
+	noErr(t, err)
+	if tender.Status != models.TenderStatusPublished || tender.Version != 1 {
+		t.Fatalf("status change must not bump the version, got %+v", tender)
+	}
+	if err := repo.IsTenderPudlished(ctx, draftTender); err != nil {
+		t.Fatalf("tender must be published: %v", err)
+	}
+
+	_, err = repo.RefreshTenderStatus(ctx, missingTender, models.TenderStatusClosed)
+	expectErr(t, err, repository.ErrTenderNotFound)
+}
+
+func testTenderVersions(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	edited, err := repo.UpdateTender(ctx, publishedTender, &models.TenderEdit{
+		Name: ptr("Edited"), ServiceType: ptr(models.TenderServiceTypeDelivery),
+	})
+	noErr(t, err)
+	if edited.Name != "Edited" || edited.Description != "Roof" ||
+		edited.ServiceType != models.TenderServiceTypeDelivery || edited.Version != 2 {
+		t.Fatalf("unexpected edited tender %+v", edited)
+	}
+
+	_, err = repo.UpdateTender(ctx, missingTender, &models.TenderEdit{Name: ptr("x")})
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	_, err = repo.RollbackTender(ctx, publishedTender, 7)
+	expectErr(t, err, repository.ErrTenderORVersionNotFound)
+
+	rolledBack, err := repo.RollbackTender(ctx, publishedTender, 1)
+	noErr(t, err)
+	if rolledBack.Name != "B roof repair" || rolledBack.ServiceType != models.TenderServiceTypeConstruction || rolledBack.Version != 3 {
+		t.Fatalf("rollback must restore version 1 as version 3, got %+v", rolledBack)
+	}
+
+	versions, err := repo.GetTenderVersionsByTenderIDs(ctx, []string{publishedTender})
+	noErr(t, err)
+	if len(versions) != 2 || versions[0].Version != 1 || versions[1].Version != 2 || versions[1].Name != "Edited" {
+		t.Fatalf("versions 1 and 2 expected, got %+v", versions)
+	}
+}
+
+func testCountOpenTenders(t *testing.T, repo Repository) {
+	counts, err := repo.CountOpenTendersByServiceType(context.Background())
+	noErr(t, err)
+	if len(counts) != 1 || counts[models.TenderServiceTypeConstruction] != 1 {
+		t.Fatalf("one open construction tender expected, got %v", counts)
+	}
+}
+
+func testCancelTender(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	draft, err := repo.ConstructBid(ctx, newBid(publishedTender, carlID), nil)
+	noErr(t, err)
+
+	offer := newBid(publishedTender, bellaID)
+	offer.AuthorType, offer.OrganizationID = models.BidAuthorTypeOrganization, ptr(supplierOrg)
+	organizationBid, err := repo.ConstructBid(ctx, offer, nil)
+	noErr(t, err)
+
+	_, err = repo.CancelTender(ctx, closedTender, "late")
+	expectErr(t, err, repository.ErrTenderNotCancellable)
+
+	_, err = repo.CancelTender(ctx, missingTender, "late")
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	tender, err := repo.CancelTender(ctx, publishedTender, "budget cut")
+	noErr(t, err)
+	if tender.Status != models.TenderStatusCancelled || tender.CancellationReason != "budget cut" || tender.Version != 2 {
+		t.Fatalf("unexpected cancelled tender %+v", tender)
+	}
+
+	_, err = repo.CancelTender(ctx, publishedTender, "again")
+	expectErr(t, err, repository.ErrTenderNotCancellable)
+
+	versions, err := repo.GetTenderVersionsByTenderIDs(ctx, []string{publishedTender})
+	noErr(t, err)
+	if len(versions) != 1 || versions[0].Status != models.TenderStatusPublished || versions[0].CancellationReason != "" {
+		t.Fatalf("the published version expected, got %+v", versions)
+	}
+
+	bids, err := repo.GetBidsByIDs(ctx, []string{publishedBid, draft.ID})
+	noErr(t, err)
+	for _, bid := range bids {
+		if bid.Status != models.BidStatusTenderCancelled {
+			t.Fatalf("bid %s must be TenderCancelled, got %s", bid.ID, bid.Status)
+		}
+	}
+
+	// Every responsible of the organization manages its bid and is notified,
+	// the author once.
+	for recipient, bidIDs := range map[string][]string{
+		bobID:   {publishedBid, organizationBid.ID},
+		carlID:  {draft.ID},
+		bellaID: {organizationBid.ID},
+	} {
+		notifications, err := repo.GetNotifications(ctx, recipient, 10, 0)
+		noErr(t, err)
+		if len(notifications) != len(bidIDs) {
+			t.Fatalf("%d cancellation notifications expected for %s, got %+v", len(bidIDs), recipient, notifications)
+		}
+		for _, bidID := range bidIDs {
+			notified := false
+			for _, n := range notifications {
+				notified = notified || n.BidID == bidID && n.Kind == models.NotificationKindTenderCancelled &&
+					n.TenderID == publishedTender && n.Message == "budget cut"
+			}
+			if !notified {
+				t.Fatalf("a cancellation notification of bid %s expected for %s, got %+v", bidID, recipient, notifications)
+			}
+		}
+	}
+
+	notifications, err := repo.GetNotifications(ctx, aliceID, 10, 0)
+	noErr(t, err)
+	if len(notifications) != 0 {
+		t.Fatalf("the buyer must not be notified, got %+v", notifications)
+	}
+}
+
+func testPermissions(t *testing.T, repo Repository) {
+	ctx := context.Background()
+	org := buyerOrg
+
+	checks := []struct {
+		name  string
+		check func() error
+		want  error
+	}{
+		{"organization responsible", func() error { return repo.ControlOrganizationPermission(ctx, &org, alice) }, nil},
+		{"organization stranger", func() error { return repo.ControlOrganizationPermission(ctx, &org, bob) }, repository.ErrRelationNotExist},
+		{"organization unknown user", func() error { return repo.ControlOrganizationPermission(ctx, &org, unknown) }, repository.ErrUserNotExist},
+
+		{"tender creator", func() error { return repo.ControlTendersCreationByName(ctx, publishedTender, alice) }, nil},
+		{"tender colleague", func() error { return repo.ControlTendersCreationByName(ctx, publishedTender, anna) }, repository.ErrRelationNotExist},
+		{"tender unknown user", func() error { return repo.ControlTendersCreationByName(ctx, publishedTender, unknown) }, repository.ErrUserNotExist},
+
+		{"responsible somewhere", func() error { return repo.ControlUserResponsibility(ctx, bobID) }, nil},
+		{"responsible nowhere", func() error { return repo.ControlUserResponsibility(ctx, carlID) }, repository.ErrRelationNotExist},
+		{"responsible unknown user", func() error { return repo.ControlUserResponsibility(ctx, nobody) }, repository.ErrUserNotExist},
+
+		{"tender responsible", func() error { return repo.ControlUserResponsibilityForTender(ctx, publishedTender, anna) }, nil},
+		{"tender supplier", func() error { return repo.ControlUserResponsibilityForTender(ctx, publishedTender, bob) }, repository.ErrRelationNotExist},
+		{"tender missing", func() error { return repo.ControlUserResponsibilityForTender(ctx, missingTender, anna) }, repository.ErrRelationNotExist},
+		{"tender responsible unknown user", func() error { return repo.ControlUserResponsibilityForTender(ctx, publishedTender, unknown) }, repository.ErrUserNotExist},
+
+		{"bid author", func() error { return repo.ControlBidCreationByName(ctx, publishedBid, bob) }, nil},
+		{"bid not author", func() error { return repo.ControlBidCreationByName(ctx, publishedBid, alice) }, repository.ErrRelationNotExist},
+		{"bid author unknown user", func() error { return repo.ControlBidCreationByName(ctx, publishedBid, unknown) }, repository.ErrUserNotExist},
+
+		{"bid author side", func() error { return repo.ControlUserResponsibilityForAuthorBid(ctx, publishedBid, bob) }, nil},
+		{"bid buyer side", func() error { return repo.ControlUserResponsibilityForAuthorBid(ctx, publishedBid, alice) }, repository.ErrRelationNotExist},
+		{"bid author side unknown user", func() error { return repo.ControlUserResponsibilityForAuthorBid(ctx, publishedBid, unknown) }, repository.ErrUserNotExist},
+
+		{"bid tender responsible", func() error { return repo.ControlUserResponsibilityForTenderByBidID(ctx, publishedBid, anna) }, nil},
+		{"bid tender supplier", func() error { return repo.ControlUserResponsibilityForTenderByBidID(ctx, publishedBid, bob) }, repository.ErrRelationNotExist},
+		{"bid tender unknown user", func() error { return repo.ControlUserResponsibilityForTenderByBidID(ctx, publishedBid, unknown) }, repository.ErrRelationNotExist},
+	}
+
+	for _, c := range checks {
+		if err := c.check(); !errors.Is(err, c.want) || (c.want == nil && err != nil) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	id, err := repo.ControlBidCreationByID(ctx, bob)
+	if err != nil || id != bobID {
+		t.Fatalf("bob's id expected, got %q, %v", id, err)
+	}
+	_, err = repo.ControlBidCreationByID(ctx, unknown)
+	expectErr(t, err, repository.ErrUserNotExist)
+}
+
+func newBid(tenderID, authorID string) *models.BidCreate {
+	return &models.BidCreate{
+		Name: "Offer", Description: "Offer", TenderID: tenderID,
+		AuthorType: models.BidAuthorTypeUser, AuthorId: authorID,
+	}
+}
+
+func testConstructBid(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	bid, err := repo.ConstructBid(ctx, newBid(publishedTender, carlID), nil)
+	noErr(t, err)
+	if bid.ID == "" || bid.Status != models.BidStatusCreated || bid.Version != 1 || bid.AuthorID != carlID {
+		t.Fatalf("unexpected bid %+v", bid)
+	}
+
+	_, err = repo.ConstructBid(ctx, newBid(publishedTender, carlID), nil)
+	expectErr(t, err, repository.ErrBidUnique)
+
+	_, err = repo.ConstructBid(ctx, newBid(missingTender, carlID), nil)
+	expectErr(t, err, repository.ErrBidDependencyNotFound)
+
+	_, err = repo.ConstructBid(ctx, newBid(publishedTender, nobody), nil)
+	expectErr(t, err, repository.ErrBidDependencyNotFound)
+}
+
+func newOrganizationBid(tenderID, authorID string, organizationID models.OrganizationID) *models.BidCreate {
+	bid := newBid(tenderID, authorID)
+	bid.AuthorType = models.BidAuthorTypeOrganization
+	bid.OrganizationID = &organizationID
+
+	return bid
+}
+
+func testOrganizationBid(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	bid, err := repo.ConstructBid(ctx, newOrganizationBid(publishedTender, bobID, supplierOrg), nil)
+	noErr(t, err)
+	if bid.AuthorType != models.BidAuthorTypeOrganization || bid.OrganizationID == nil || *bid.OrganizationID != supplierOrg {
+		t.Fatalf("unexpected organization bid %+v", bid)
+	}
+
+	_, err = repo.ConstructBid(ctx, newOrganizationBid(publishedTender, bellaID, supplierOrg), nil)
+	expectErr(t, err, repository.ErrBidUnique)
+
+	_, err = repo.ConstructBid(ctx, newOrganizationBid(publishedTender, bellaID, missingOrg), nil)
+	expectErr(t, err, repository.ErrBidDependencyNotFound)
+
+	noErr(t, repo.ControlBidCreationByName(ctx, bid.ID, bella))
+	expectErr(t, repo.ControlBidCreationByName(ctx, publishedBid, bella), repository.ErrRelationNotExist)
+	expectErr(t, repo.ControlBidCreationByName(ctx, bid.ID, alice), repository.ErrRelationNotExist)
+
+	noErr(t, repo.ControlUserResponsibilityForAuthorBid(ctx, bid.ID, bella))
+	expectErr(t, repo.ControlUserResponsibilityForAuthorBid(ctx, bid.ID, alice), repository.ErrRelationNotExist)
+
+	noErr(t, repo.ControlOrganizationPermissionByID(ctx, ptr(supplierOrg), bellaID))
+	expectErr(t, repo.ControlOrganizationPermissionByID(ctx, ptr(buyerOrg), bellaID), repository.ErrRelationNotExist)
+	expectErr(t, repo.ControlOrganizationPermissionByID(ctx, ptr(supplierOrg), nobody), repository.ErrUserNotExist)
+
+	mine, err := repo.GetBidsOfUser(ctx, bellaID, 10, 0)
+	noErr(t, err)
+	if len(mine) != 1 || mine[0].ID != bid.ID {
+		t.Fatalf("the organization bid expected, got %+v", mine)
+	}
+
+	statuses, err := repo.GetBidStatusesOfAuthor(ctx, publishedTender, bellaID, ptr(supplierOrg))
+	noErr(t, err)
+	if len(statuses) != 1 || statuses[0] != models.BidStatusCreated {
+		t.Fatalf("the organization bid status expected, got %v", statuses)
+	}
+
+	statuses, err = repo.GetBidStatusesOfAuthor(ctx, publishedTender, bellaID, nil)
+	noErr(t, err)
+	if len(statuses) != 0 {
+		t.Fatalf("bella has no user bids, got %v", statuses)
+	}
+
+	loaded, err := repo.GetBidsByIDs(ctx, []string{bid.ID})
+	noErr(t, err)
+	if len(loaded) != 1 || loaded[0].OrganizationID == nil || *loaded[0].OrganizationID != supplierOrg {
+		t.Fatalf("the organization must be loaded, got %+v", loaded)
+	}
+
+	edited, err := repo.ChangeBid(ctx, bid.ID, &models.BidEdit{Name: ptr("Edited")})
+	noErr(t, err)
+	rolledBack, err := repo.CancelChangesOfBid(ctx, bid.ID, 1)
+	noErr(t, err)
+	if edited.OrganizationID == nil || rolledBack.OrganizationID == nil || *rolledBack.OrganizationID != supplierOrg {
+		t.Fatalf("the organization must survive edits, got %+v and %+v", edited, rolledBack)
+	}
+}
+
+func testConcurrentConstructBid(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.ConstructBid(ctx, newBid(draftTender, carlID), nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created int
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, repository.ErrBidUnique):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("exactly one bid expected, %d created", created)
+	}
+}
+
+func testListBids(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.ConstructBid(ctx, newBid(publishedTender, carlID), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bids, err := repo.GetBidsOfTender(ctx, publishedTender, 10, 0)
+	noErr(t, err)
+	if len(bids) != 1 || bids[0].ID != publishedBid {
+		t.Fatalf("only the published bid expected, got %+v", bids)
+	}
+
+	_, err = repo.GetBidsOfTender(ctx, draftTender, 10, 0)
+	expectErr(t, err, repository.ErrBidTenderNotFound)
+
+	mine, err := repo.GetBidsOfUser(ctx, carlID, 10, 0)
+	noErr(t, err)
+	if len(mine) != 1 || mine[0].Status != models.BidStatusCreated {
+		t.Fatalf("carl's draft bid expected, got %+v", mine)
+	}
+
+	found, err := repo.GetBidsWithID(ctx, publishedBid)
+	noErr(t, err)
+	if found.TenderID != publishedTender {
+		t.Fatalf("unexpected bid %+v", found)
+	}
+	_, err = repo.GetBidsWithID(ctx, missingBid)
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	renewed, err := repo.RenewStatusOfBid(ctx, publishedBid, bob, &models.BidStatusCanceled)
+	noErr(t, err)
+	if renewed.Status != models.BidStatusCanceled || renewed.Version != 1 {
+		t.Fatalf("status change must not bump the version, got %+v", renewed)
+	}
+	_, err = repo.RenewStatusOfBid(ctx, missingBid, bob, &models.BidStatusCanceled)
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	// A final decision is final: neither a rejected bid nor the bids of a
+	// closed tender can be brought back.
+	_, err = repo.RenewStatusOfBid(ctx, publishedBid, bob, &models.BidStatusPublished)
+	expectErr(t, err, repository.ErrBidStatusFinal)
+
+	closed, err := repo.ConstructBid(ctx, newBid(closedTender, bobID), nil)
+	noErr(t, err)
+	_, err = repo.RenewStatusOfBid(ctx, closed.ID, bob, &models.BidStatusPublished)
+	expectErr(t, err, repository.ErrBidStatusFinal)
+
+	found, err = repo.GetBidsWithID(ctx, publishedBid)
+	noErr(t, err)
+	if found.Status != models.BidStatusCanceled {
+		t.Fatalf("the bid must stay canceled, got %+v", found)
+	}
+}
+
+func testBidVersions(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	edited, err := repo.ChangeBid(ctx, publishedBid, &models.BidEdit{Description: ptr("Cheaper")})
+	noErr(t, err)
+	if edited.Name != "Roof offer" || edited.Description != "Cheaper" || edited.Version != 2 {
+		t.Fatalf("unexpected edited bid %+v", edited)
+	}
+
+	_, err = repo.ChangeBid(ctx, missingBid, &models.BidEdit{Name: ptr("x")})
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	_, err = repo.CancelChangesOfBid(ctx, publishedBid, 7)
+	expectErr(t, err, repository.ErrBidORVersionNotFound)
+
+	rolledBack, err := repo.CancelChangesOfBid(ctx, publishedBid, 1)
+	noErr(t, err)
+	if rolledBack.Description != "We fix roofs" || rolledBack.Version != 3 {
+		t.Fatalf("rollback must restore version 1 as version 3, got %+v", rolledBack)
+	}
+
+	versions, err := repo.GetBidVersionsByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(versions) != 2 || versions[0].Version != 1 || versions[1].Description != "Cheaper" {
+		t.Fatalf("versions 1 and 2 expected, got %+v", versions)
+	}
+}
+
+func testWithdrawBid(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	_, err := repo.ApplyBidDecision(ctx, publishedBid, alice, &models.BidDecisionApproved)
+	noErr(t, err)
+
+	_, err = repo.WithdrawBid(ctx, missingBid, "changed plans")
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	bid, err := repo.WithdrawBid(ctx, publishedBid, "changed plans")
+	noErr(t, err)
+	if bid.Status != models.BidStatusWithdrawn || bid.WithdrawalReason != "changed plans" || bid.Version != 2 {
+		t.Fatalf("unexpected withdrawn bid %+v", bid)
+	}
+
+	_, err = repo.WithdrawBid(ctx, publishedBid, "again")
+	expectErr(t, err, repository.ErrBidNotWithdrawable)
+
+	approved, err := repo.CountApplyedDecisions(ctx, publishedBid)
+	noErr(t, err)
+	if approved != 0 {
+		t.Fatalf("the decisions on a withdrawn bid must not count, got %d", approved)
+	}
+
+	decisions, err := repo.GetDecisionsByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(decisions) != 1 || decisions[0].InvalidatedAt == nil {
+		t.Fatalf("one invalidated decision expected, got %+v", decisions)
+	}
+
+	versions, err := repo.GetBidVersionsByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(versions) != 1 || versions[0].Status != models.BidStatusPublished || versions[0].WithdrawalReason != "" {
+		t.Fatalf("the published version expected, got %+v", versions)
+	}
+
+	resubmitted, err := repo.ConstructBid(ctx, newBid(publishedTender, bobID), nil)
+	noErr(t, err)
+
+	_, err = repo.ConstructBid(ctx, newBid(publishedTender, bobID), nil)
+	expectErr(t, err, repository.ErrBidUnique)
+
+	statuses, err := repo.GetBidStatusesOfAuthor(ctx, publishedTender, bobID, nil)
+	noErr(t, err)
+	if len(statuses) != 2 || statuses[0] != models.BidStatusWithdrawn || statuses[1] != models.BidStatusCreated {
+		t.Fatalf("the withdrawn and the new bid expected, got %v", statuses)
+	}
+
+	_, err = repo.CancelTender(ctx, publishedTender, "budget cut")
+	noErr(t, err)
+
+	_, err = repo.WithdrawBid(ctx, resubmitted.ID, "too late")
+	expectErr(t, err, repository.ErrBidNotWithdrawable)
+}
+
+func testDecisions(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	count, err := repo.CountOrganizationsByBid(ctx, publishedBid)
+	noErr(t, err)
+	if count != 2 {
+		t.Fatalf("two buyer responsibles expected, got %d", count)
+	}
+
+	bid, err := repo.ApplyBidDecision(ctx, publishedBid, alice, &models.BidDecisionApproved)
+	noErr(t, err)
+	if bid.ID != publishedBid {
+		t.Fatalf("unexpected bid %+v", bid)
+	}
+
+	_, err = repo.ApplyBidDecision(ctx, publishedBid, alice, &models.BidDecisionRejected)
+	expectErr(t, err, repository.ErrBidDecisionUnique)
+
+	_, err = repo.ApplyBidDecision(ctx, missingBid, anna, &models.BidDecisionApproved)
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	_, err = repo.ApplyBidDecision(ctx, publishedBid, unknown, &models.BidDecisionApproved)
+	expectErr(t, err, repository.ErrUserNotExist)
+
+	if _, err := repo.ApplyBidDecision(ctx, publishedBid, anna, &models.BidDecisionRejected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	approved, err := repo.CountApplyedDecisions(ctx, publishedBid)
+	noErr(t, err)
+	if approved != 1 {
+		t.Fatalf("one approval expected, got %d", approved)
+	}
+
+	decisions, err := repo.GetDecisionsByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(decisions) != 2 {
+		t.Fatalf("two decisions expected, got %d", len(decisions))
+	}
+}
+
+func testRevealTender(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	newSealedTender := func(deadline time.Time) *models.TenderResponse {
+		return publishTender(t, repo, newTender("Sealed", func(c *models.TenderCreate) {
+			c.SubmissionDeadline, c.Sealed = &deadline, true
+		}), []byte("wrapped key"))
+	}
+
+	tender := newSealedTender(time.Now().Add(-time.Minute))
+	if !tender.Sealed || tender.SubmissionDeadline == nil || tender.RevealedAt != nil {
+		t.Fatalf("unexpected sealed tender %+v", tender)
+	}
+
+	key, err := repo.GetTenderKey(ctx, tender.ID)
+	noErr(t, err)
+	if string(key) != "wrapped key" {
+		t.Fatalf("got key %q", key)
+	}
+
+	_, err = repo.GetTenderKey(ctx, publishedTender)
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	bid, err := repo.ConstructBid(ctx, newBid(tender.ID, carlID), &models.SealedContent{BidID: sealedBidID, Version: 1, Content: []byte("first")})
+	noErr(t, err)
+	if bid.ID != sealedBidID {
+		t.Fatalf("a sealed bid must take the ID it was sealed for, got %s", bid.ID)
+	}
+	if !bid.Sealed || bid.Name != "" || bid.Description != "" {
+		t.Fatalf("a sealed bid must not store its contents in the clear, got %+v", bid)
+	}
+
+	bid, err = repo.ChangeSealedBid(ctx, bid.ID, []byte("second"))
+	noErr(t, err)
+	if !bid.Sealed || bid.Version != 2 {
+		t.Fatalf("unexpected changed sealed bid %+v", bid)
+	}
+
+	contents, err := repo.GetSealedBidContents(ctx, tender.ID)
+	noErr(t, err)
+	sealed := map[int]string{}
+	for _, c := range contents {
+		if c.BidID != bid.ID {
+			t.Fatalf("unexpected sealed content of bid %s", c.BidID)
+		}
+		sealed[c.Version] = string(c.Content)
+	}
+	if len(sealed) != 2 || sealed[1] != "first" || sealed[2] != "second" {
+		t.Fatalf("unexpected sealed contents %v", sealed)
+	}
+
+	_, err = repo.RevealTender(ctx, tender.ID, alice, []*models.RevealedBid{
+		{BidID: bid.ID, Version: 2, Name: "Second", Description: "Second offer"},
+	})
+	if err == nil {
+		t.Fatal("a reveal that leaves a version sealed must fail")
+	}
+
+	bids, err := repo.GetBidsByIDs(ctx, []string{bid.ID})
+	noErr(t, err)
+	if len(bids) != 1 || !bids[0].Sealed || bids[0].Name != "" {
+		t.Fatalf("a failed reveal must change nothing, got %+v", bids)
+	}
+
+	revealed, err := repo.RevealTender(ctx, tender.ID, alice, []*models.RevealedBid{
+		{BidID: bid.ID, Version: 1, Name: "First", Description: "First offer"},
+		{BidID: bid.ID, Version: 2, Name: "Second", Description: "Second offer"},
+	})
+	noErr(t, err)
+	if revealed.RevealedAt == nil {
+		t.Fatalf("unexpected revealed tender %+v", revealed)
+	}
+
+	bids, err = repo.GetBidsByIDs(ctx, []string{bid.ID})
+	noErr(t, err)
+	if len(bids) != 1 || bids[0].Sealed || bids[0].Name != "Second" || bids[0].Description != "Second offer" {
+		t.Fatalf("unexpected revealed bid %+v", bids)
+	}
+
+	versions, err := repo.GetBidVersionsByBidIDs(ctx, []string{bid.ID})
+	noErr(t, err)
+	if len(versions) != 1 || versions[0].Sealed || versions[0].Name != "First" {
+		t.Fatalf("unexpected revealed versions %+v", versions)
+	}
+
+	contents, err = repo.GetSealedBidContents(ctx, tender.ID)
+	noErr(t, err)
+	if len(contents) != 0 {
+		t.Fatalf("nothing must stay sealed after the reveal, got %d contents", len(contents))
+	}
+
+	_, err = repo.RevealTender(ctx, tender.ID, alice, nil)
+	expectErr(t, err, repository.ErrTenderNotRevealable)
+
+	_, err = repo.RevealTender(ctx, publishedTender, alice, nil)
+	expectErr(t, err, repository.ErrTenderNotRevealable)
+
+	open := newSealedTender(time.Now().Add(time.Hour))
+	_, err = repo.RevealTender(ctx, open.ID, alice, nil)
+	expectErr(t, err, repository.ErrTenderNotRevealable)
+
+	records, err := repo.GetAuditLog(ctx, tender.ID, 10, 0)
+	noErr(t, err)
+	if len(records) != 1 || records[0].Action != models.AuditActionTenderRevealed ||
+		records[0].ActorID == nil || *records[0].ActorID != aliceID {
+		t.Fatalf("unexpected audit log %+v", records)
+	}
+}
+
+func testAuction(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	newAuction := func(start, end time.Time) *models.TenderResponse {
+		return publishTender(t, repo, newTender("Auction", func(c *models.TenderCreate) {
+			c.SubmissionDeadline, c.Auction = &end, &models.Auction{Start: start, Step: 10, ExtensionSeconds: 60}
+		}), nil)
+	}
+	newPricedBid := func(tenderID, authorID string, price float64) *models.BidResponse {
+		create := newBid(tenderID, authorID)
+		create.Price = &price
+
+		bid, err := repo.ConstructBid(ctx, create, nil)
+		noErr(t, err)
+
+		bid, err = repo.RenewStatusOfBid(ctx, bid.ID, "", &models.BidStatusPublished)
+		noErr(t, err)
+
+		return bid
+	}
+
+	running := newAuction(time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	if running.Auction == nil || running.Auction.Step != 10 || running.Auction.ExtensionSeconds != 60 {
+		t.Fatalf("unexpected auction %+v", running.Auction)
+	}
+
+	carlBid := newPricedBid(running.ID, carlID, 100)
+	newPricedBid(running.ID, bobID, 90)
+	if carlBid.Price == nil || *carlBid.Price != 100 || carlBid.PricedAt == nil {
+		t.Fatalf("unexpected priced bid %+v", carlBid)
+	}
+
+	_, err := repo.SubmitAuctionPrice(ctx, carlBid.ID, 95)
+	expectErr(t, err, repository.ErrPriceStepTooSmall)
+
+	_, err = repo.SubmitAuctionPrice(ctx, missingBid, 50)
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	dropped, err := repo.SubmitAuctionPrice(ctx, carlBid.ID, 85)
+	noErr(t, err)
+	if *dropped.Price != 85 || dropped.Version != 2 {
+		t.Fatalf("unexpected bid after a price drop %+v", dropped)
+	}
+
+	versions, err := repo.GetBidVersionsByBidIDs(ctx, []string{carlBid.ID})
+	noErr(t, err)
+	if len(versions) != 1 || versions[0].Price == nil || *versions[0].Price != 100 {
+		t.Fatalf("every price drop must keep the previous price as a version, got %+v", versions)
+	}
+
+	ranking, err := repo.GetAuctionRanking(ctx, running.ID, carlID)
+	noErr(t, err)
+	if len(ranking) != 2 || ranking[0].Rank != 1 || ranking[0].Price != 85 || !ranking[0].Own ||
+		ranking[1].Rank != 2 || ranking[1].Price != 90 || ranking[1].Own {
+		t.Fatalf("unexpected ranking %+v", ranking)
+	}
+
+	extended, err := repo.ExtendAuction(ctx, running.ID)
+	noErr(t, err)
+	if extended {
+		t.Fatal("an auction far from its end must not be extended")
+	}
+
+	closing := newAuction(time.Now().Add(-time.Minute), time.Now().Add(10*time.Second))
+	extended, err = repo.ExtendAuction(ctx, closing.ID)
+	noErr(t, err)
+	tenders, err := repo.GetTendersByIDs(ctx, []string{closing.ID})
+	noErr(t, err)
+	if !extended || len(tenders) != 1 || time.Until(*tenders[0].SubmissionDeadline) < 50*time.Second {
+		t.Fatalf("an auction close to its end must be extended, got %+v", tenders)
+	}
+
+	ended := newAuction(time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+	winner := newPricedBid(ended.ID, carlID, 50)
+	newPricedBid(ended.ID, bobID, 50)
+	empty := newAuction(time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+
+	results, err := repo.CloseEndedAuctions(ctx)
+	noErr(t, err)
+	won := map[string]string{}
+	for _, r := range results {
+		won[r.TenderID] = r.BidID
+	}
+	if len(won) != 2 || won[ended.ID] != winner.ID || won[empty.ID] != "" {
+		t.Fatalf("the earliest lowest price must win, got %+v", won)
+	}
+
+	bids, err := repo.GetBidsByIDs(ctx, []string{winner.ID})
+	noErr(t, err)
+	if len(bids) != 1 || bids[0].Status != models.BidStatusApproved {
+		t.Fatalf("unexpected winning bid %+v", bids)
+	}
+
+	status, _, err := repo.GetStatusOfTender(ctx, ended.ID)
+	noErr(t, err)
+	if *status != models.TenderStatusClosed {
+		t.Fatalf("an awarded auction must be closed, got %s", *status)
+	}
+
+	results, err = repo.CloseEndedAuctions(ctx)
+	noErr(t, err)
+	if len(results) != 0 {
+		t.Fatalf("an auction must be closed once, got %+v", results)
+	}
+
+	records, err := repo.GetAuditLog(ctx, ended.ID, 10, 0)
+	noErr(t, err)
+	if len(records) != 1 || records[0].Action != models.AuditActionAuctionClosed || records[0].BidID != winner.ID {
+		t.Fatalf("unexpected audit log %+v", records)
+	}
+}
+
+func testEvaluation(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	criteria, err := repo.SetTenderCriteria(ctx, publishedTender, []*models.CriterionCreate{
+		{Name: "Delivery time", Weight: 50},
+		{Name: "Price", Weight: 50},
+	})
+	noErr(t, err)
+
+	criteria, err = repo.SetTenderCriteria(ctx, publishedTender, []*models.CriterionCreate{
+		{Name: "Price", Weight: 60},
+		{Name: "Experience", Weight: 40},
+	})
+	noErr(t, err)
+	if len(criteria) != 2 || criteria[0].Name != "Price" || criteria[0].Weight != 60 ||
+		criteria[1].Name != "Experience" || criteria[0].TenderID != publishedTender {
+		t.Fatalf("unexpected criteria %+v", criteria)
+	}
+
+	loaded, err := repo.GetCriteriaByTenderIDs(ctx, []string{publishedTender, draftTender})
+	noErr(t, err)
+	if len(loaded) != 2 || loaded[0].ID != criteria[0].ID || loaded[1].ID != criteria[1].ID {
+		t.Fatalf("criteria must replace the earlier ones in order, got %+v", loaded)
+	}
+
+	_, err = repo.SetTenderCriteria(ctx, missingTender, []*models.CriterionCreate{{Name: "Price", Weight: 100}})
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	_, err = repo.SetTenderCriteria(ctx, draftTender, []*models.CriterionCreate{
+		{Name: "Price", Weight: 50},
+		{Name: "Price", Weight: 50},
+	})
+	expectErr(t, err, repository.ErrInvalidCriteria)
+
+	other, err := repo.SetTenderCriteria(ctx, draftTender, []*models.CriterionCreate{{Name: "Price", Weight: 100}})
+	noErr(t, err)
+
+	scores, err := repo.ScoreBid(ctx, publishedBid, aliceID, []*models.CriterionScore{
+		{CriterionID: criteria[1].ID, Score: 6},
+		{CriterionID: criteria[0].ID, Score: 8},
+	})
+	noErr(t, err)
+	if len(scores) != 2 || scores[0].CriterionID != criteria[0].ID || scores[0].Score != 8 || scores[0].UserID != aliceID {
+		t.Fatalf("unexpected scores %+v", scores)
+	}
+
+	scores, err = repo.ScoreBid(ctx, publishedBid, aliceID, []*models.CriterionScore{{CriterionID: criteria[0].ID, Score: 9}})
+	noErr(t, err)
+	if len(scores) != 2 || scores[0].Score != 9 || scores[1].Score != 6 {
+		t.Fatalf("scoring a criterion again must replace the score, got %+v", scores)
+	}
+
+	_, err = repo.ScoreBid(ctx, publishedBid, annaID, []*models.CriterionScore{
+		{CriterionID: criteria[0].ID, Score: 5},
+		{CriterionID: other[0].ID, Score: 5},
+	})
+	expectErr(t, err, repository.ErrCriterionNotFound)
+
+	tenderScores, err := repo.GetScoresOfTender(ctx, publishedTender)
+	noErr(t, err)
+	if len(tenderScores) != 2 {
+		t.Fatalf("a rejected scoring must not keep any score, got %+v", tenderScores)
+	}
+
+	_, err = repo.SetTenderCriteria(ctx, publishedTender, []*models.CriterionCreate{{Name: "Price", Weight: 100}})
+	expectErr(t, err, repository.ErrCriteriaLocked)
+
+	bid, err := repo.AwardBid(ctx, publishedBid, anna, "weighted score: 7.80")
+	noErr(t, err)
+	if bid.Status != models.BidStatusApproved {
+		t.Fatalf("unexpected awarded bid %+v", bid)
+	}
+
+	status, _, err := repo.GetStatusOfTender(ctx, publishedTender)
+	noErr(t, err)
+	if *status != models.TenderStatusClosed {
+		t.Fatalf("an awarded tender must be closed, got %s", *status)
+	}
+
+	_, err = repo.AwardBid(ctx, publishedBid, anna, "weighted score: 7.80")
+	expectErr(t, err, repository.ErrTenderClosed)
+
+	records, err := repo.GetAuditLog(ctx, publishedTender, 10, 0)
+	noErr(t, err)
+	if len(records) != 1 || records[0].Action != models.AuditActionTenderAwarded || records[0].BidID != publishedBid ||
+		records[0].ActorID == nil || *records[0].ActorID != annaID || records[0].Details != "weighted score: 7.80" {
+		t.Fatalf("unexpected audit log %+v", records)
+	}
+}
+
+func testQuestions(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	first, err := repo.AskQuestion(ctx, publishedTender, bobID, "Which roofs?")
+	noErr(t, err)
+	if first.AskerID != bobID || first.Question != "Which roofs?" || first.Answer != nil {
+		t.Fatalf("unexpected question %+v", first)
+	}
+
+	second, err := repo.AskQuestion(ctx, publishedTender, carlID, "Is scaffolding provided?")
+	noErr(t, err)
+
+	_, err = repo.AskQuestion(ctx, missingTender, bobID, "Anyone there?")
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	questions, err := repo.GetQuestions(ctx, publishedTender, carlID, false, 10, 0)
+	noErr(t, err)
+	if len(questions) != 1 || questions[0].ID != second.ID {
+		t.Fatalf("an unanswered question must only be listed to its asker, got %+v", questions)
+	}
+
+	answered, err := repo.AnswerQuestion(ctx, publishedTender, first.ID, aliceID, &models.QuestionAnswer{
+		Answer: "Flat ones", Visibility: models.QuestionVisibilityPublic,
+	}, nil)
+	noErr(t, err)
+	if answered.Answer == nil || *answered.Answer != "Flat ones" || answered.AnsweredAt == nil ||
+		*answered.Visibility != models.QuestionVisibilityPublic || answered.AmendedVersion != nil {
+		t.Fatalf("unexpected answered question %+v", answered)
+	}
+
+	_, err = repo.AnswerQuestion(ctx, publishedTender, first.ID, aliceID, &models.QuestionAnswer{
+		Answer: "Any", Visibility: models.QuestionVisibilityPublic,
+	}, nil)
+	expectErr(t, err, repository.ErrQuestionAnswered)
+
+	_, err = repo.AnswerQuestion(ctx, draftTender, second.ID, aliceID, &models.QuestionAnswer{
+		Answer: "Any", Visibility: models.QuestionVisibilityPublic,
+	}, nil)
+	expectErr(t, err, repository.ErrQuestionNotFound)
+
+	_, err = repo.GetQuestion(ctx, publishedTender, missingBid)
+	expectErr(t, err, repository.ErrQuestionNotFound)
+
+	questions, err = repo.GetQuestions(ctx, publishedTender, carlID, false, 10, 0)
+	noErr(t, err)
+	if len(questions) != 2 || questions[0].ID != first.ID || questions[1].ID != second.ID {
+		t.Fatalf("a public answer must be listed to everybody, got %+v", questions)
+	}
+
+	bobNotifications, err := repo.GetNotifications(ctx, bobID, 10, 0)
+	noErr(t, err)
+	if len(bobNotifications) != 1 || bobNotifications[0].Kind != models.NotificationKindQuestionAnswered ||
+		bobNotifications[0].TenderID != publishedTender || bobNotifications[0].Message != "Flat ones" {
+		t.Fatalf("the asker, who is also a bidder, must be notified once, got %+v", bobNotifications)
+	}
+
+	_, err = repo.AnswerQuestion(ctx, publishedTender, second.ID, aliceID, &models.QuestionAnswer{
+		Answer: "Yes", Visibility: models.QuestionVisibilityPrivate,
+	}, ptr(int32(2)))
+	noErr(t, err)
+
+	questions, err = repo.GetQuestions(ctx, publishedTender, bobID, false, 10, 0)
+	noErr(t, err)
+	if len(questions) != 1 || questions[0].ID != first.ID {
+		t.Fatalf("a private answer must only be listed to its asker, got %+v", questions)
+	}
+
+	questions, err = repo.GetQuestions(ctx, publishedTender, aliceID, true, 10, 0)
+	noErr(t, err)
+	if len(questions) != 2 || questions[1].AmendedVersion == nil || *questions[1].AmendedVersion != 2 {
+		t.Fatalf("unexpected questions %+v", questions)
+	}
+
+	bobNotifications, err = repo.GetNotifications(ctx, bobID, 10, 0)
+	noErr(t, err)
+	carlNotifications, err := repo.GetNotifications(ctx, carlID, 10, 0)
+	noErr(t, err)
+	if len(bobNotifications) != 1 || len(carlNotifications) != 1 || carlNotifications[0].Message != "Yes" {
+		t.Fatalf("a private answer must only notify its asker, got %+v and %+v", bobNotifications, carlNotifications)
+	}
+}
+
+func testMessages(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	question, err := repo.PostBidMessage(ctx, publishedBid, aliceID, models.MessageRoleBuyer, &models.BidMessageCreate{
+		Body:        "Can you deliver in May?",
+		Attachments: []*models.MessageAttachment{{Name: "schedule.pdf", URL: "https://files.example.com/schedule.pdf"}},
+	})
+	noErr(t, err)
+	if question.AuthorID == nil || *question.AuthorID != aliceID || question.Role != models.MessageRoleBuyer || len(question.Attachments) != 1 {
+		t.Fatalf("the buyer message of alice with its attachment expected, got %+v", question)
+	}
+
+	answer, err := repo.PostBidMessage(ctx, publishedBid, bobID, models.MessageRoleBidder, &models.BidMessageCreate{
+		Body:    "Yes, in the second week.",
+		ReplyTo: &question.ID,
+	})
+	noErr(t, err)
+	if answer.ReplyTo == nil || *answer.ReplyTo != question.ID {
+		t.Fatalf("a reply to the question expected, got %+v", answer)
+	}
+
+	_, err = repo.PostBidMessage(ctx, publishedBid, bobID, models.MessageRoleBidder, &models.BidMessageCreate{Body: "lost", ReplyTo: ptr(missingBid)})
+	expectErr(t, err, repository.ErrMessageNotFound)
+
+	_, err = repo.PostBidMessage(ctx, missingBid, aliceID, models.MessageRoleBuyer, &models.BidMessageCreate{Body: "lost"})
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	read, err := repo.MarkBidMessagesRead(ctx, publishedBid, bobID)
+	noErr(t, err)
+	if read != 1 {
+		t.Fatalf("bob reads the question only, got %d", read)
+	}
+
+	read, err = repo.MarkBidMessagesRead(ctx, publishedBid, bobID)
+	noErr(t, err)
+	if read != 0 {
+		t.Fatalf("nothing left to read, got %d", read)
+	}
+
+	messages, err := repo.GetBidMessages(ctx, publishedBid, 10, 0)
+	noErr(t, err)
+	if len(messages) != 2 || messages[0].ID != question.ID || messages[1].ID != answer.ID {
+		t.Fatalf("the question and the answer in order expected, got %+v", messages)
+	}
+	if len(messages[0].Attachments) != 1 || messages[0].Attachments[0].Name != "schedule.pdf" {
+		t.Fatalf("the attachment expected, got %+v", messages[0].Attachments)
+	}
+	if len(messages[0].ReadBy) != 1 || messages[0].ReadBy[0].UserID != bobID || len(messages[1].ReadBy) != 0 {
+		t.Fatalf("a read receipt of bob on the question only expected, got %+v and %+v", messages[0].ReadBy, messages[1].ReadBy)
+	}
+
+	paged, err := repo.GetBidMessages(ctx, publishedBid, 1, 1)
+	noErr(t, err)
+	if len(paged) != 1 || paged[0].ID != answer.ID {
+		t.Fatalf("the answer expected on the second page, got %+v", paged)
+	}
+
+	feedback, err := repo.GetFeedbackByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(feedback) != 1 || feedback[0].ID != question.ID || feedback[0].Description != "Can you deliver in May?" {
+		t.Fatalf("the buyer message as feedback expected, got %+v", feedback)
+	}
+}
+
+func testFeedback(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	_, err := repo.GetCommentsOfBid(ctx, publishedTender, bob, 10, 0)
+	expectErr(t, err, repository.ErrBidReviewsNotFound)
+
+	for _, text := range []string{"first", "second"} {
+		_, err := repo.PostBidMessage(ctx, publishedBid, aliceID, models.MessageRoleBuyer, &models.BidMessageCreate{Body: text})
+		noErr(t, err)
+	}
+
+	// The answers of the bidder are not feedback.
+	_, err = repo.PostBidMessage(ctx, publishedBid, bobID, models.MessageRoleBidder, &models.BidMessageCreate{Body: "answer"})
+	noErr(t, err)
+
+	reviews, err := repo.GetCommentsOfBid(ctx, publishedTender, bob, 10, 0)
+	noErr(t, err)
+	if len(reviews) != 2 || reviews[0].Description != "first" || reviews[1].Description != "second" {
+		t.Fatalf("both reviews in order expected, got %+v", reviews)
+	}
+
+	paged, err := repo.GetCommentsOfBid(ctx, publishedTender, bob, 1, 1)
+	noErr(t, err)
+	if len(paged) != 1 || paged[0].Description != "second" {
+		t.Fatalf("second page expected, got %+v", paged)
+	}
+
+	_, err = repo.GetCommentsOfBid(ctx, publishedTender, carl, 10, 0)
+	expectErr(t, err, repository.ErrBidReviewsNotFound)
+}
+
+func newAttachment(tenderID string, bidID *string, name string) *models.Attachment {
+	return &models.Attachment{
+		TenderID: tenderID, BidID: bidID, Name: name, ContentType: "application/pdf", Size: 3,
+		SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", StorageKey: name + "-key",
+		UploadedBy: aliceID,
+	}
+}
+
+func testAttachments(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	plan, err := repo.AttachToTender(ctx, newAttachment(publishedTender, nil, "plan"))
+	noErr(t, err)
+	if plan.ID == "" || plan.TenderID != publishedTender || plan.BidID != nil || plan.UploadedBy != aliceID || plan.Size != 3 {
+		t.Fatalf("unexpected attachment %+v", plan)
+	}
+
+	specs, err := repo.AttachToTender(ctx, newAttachment(publishedTender, nil, "specs"))
+	noErr(t, err)
+
+	_, err = repo.AttachToTender(ctx, newAttachment(missingTender, nil, "lost"))
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	tender, err := repo.GetTendersByIDs(ctx, []string{publishedTender})
+	noErr(t, err)
+	if len(tender) != 1 || tender[0].Version != 3 || len(tender[0].AttachmentIDs) != 2 ||
+		tender[0].AttachmentIDs[0] != plan.ID || tender[0].AttachmentIDs[1] != specs.ID {
+		t.Fatalf("version 3 with both attachments expected, got %+v", tender)
+	}
+
+	detached, err := repo.DetachFromTender(ctx, publishedTender, plan.ID)
+	noErr(t, err)
+	if detached.Version != 4 || len(detached.AttachmentIDs) != 1 || detached.AttachmentIDs[0] != specs.ID {
+		t.Fatalf("version 4 with the specs only expected, got %+v", detached)
+	}
+
+	_, err = repo.DetachFromTender(ctx, publishedTender, plan.ID)
+	expectErr(t, err, repository.ErrAttachmentNotFound)
+
+	rolledBack, err := repo.RollbackTender(ctx, publishedTender, 3)
+	noErr(t, err)
+	if rolledBack.Version != 5 || len(rolledBack.AttachmentIDs) != 2 {
+		t.Fatalf("rollback must restore both attachments, got %+v", rolledBack)
+	}
+
+	versions, err := repo.GetTenderVersionsByTenderIDs(ctx, []string{publishedTender})
+	noErr(t, err)
+	if len(versions) != 4 || len(versions[0].AttachmentIDs) != 0 || len(versions[1].AttachmentIDs) != 1 {
+		t.Fatalf("the older versions must keep their attachments, got %+v", versions)
+	}
+
+	offer, err := repo.AttachToBid(ctx, newAttachment(publishedTender, ptr(publishedBid), "offer"))
+	noErr(t, err)
+	if offer.BidID == nil || *offer.BidID != publishedBid {
+		t.Fatalf("an attachment of the bid expected, got %+v", offer)
+	}
+
+	_, err = repo.AttachToBid(ctx, newAttachment(publishedTender, ptr(missingBid), "lost"))
+	expectErr(t, err, repository.ErrBidNotFound)
+
+	bid, err := repo.GetBidsWithID(ctx, publishedBid)
+	noErr(t, err)
+	if bid.Version != 2 || len(bid.AttachmentIDs) != 1 || bid.AttachmentIDs[0] != offer.ID {
+		t.Fatalf("version 2 of the bid with the offer expected, got %+v", bid)
+	}
+
+	bidDetached, err := repo.DetachFromBid(ctx, publishedBid, offer.ID)
+	noErr(t, err)
+	if bidDetached.Version != 3 || len(bidDetached.AttachmentIDs) != 0 {
+		t.Fatalf("version 3 of the bid without attachments expected, got %+v", bidDetached)
+	}
+
+	_, err = repo.DetachFromBid(ctx, publishedBid, offer.ID)
+	expectErr(t, err, repository.ErrAttachmentNotFound)
+
+	got, err := repo.GetAttachment(ctx, offer.ID)
+	noErr(t, err)
+	if got.Name != "offer" || got.StorageKey != "offer-key" || got.SHA256 != offer.SHA256 {
+		t.Fatalf("the detached attachment must be kept, got %+v", got)
+	}
+
+	quarantined, err := repo.GetQuarantinedAttachments(ctx, 2)
+	noErr(t, err)
+	if len(quarantined) != 2 || quarantined[0].ID != plan.ID || quarantined[1].ID != specs.ID ||
+		quarantined[0].ScanStatus != models.ScanStatusQuarantined {
+		t.Fatalf("the oldest two uploads in quarantine expected, got %+v", quarantined)
+	}
+
+	scanned, err := repo.RecordAttachmentScan(ctx, plan.ID, &models.AttachmentScan{Status: models.ScanStatusInfected, Signature: "Eicar-Test-Signature"})
+	noErr(t, err)
+	if scanned.ScanStatus != models.ScanStatusInfected || scanned.ScanSignature != "Eicar-Test-Signature" || scanned.ScannedAt == nil {
+		t.Fatalf("an infected attachment expected, got %+v", scanned)
+	}
+
+	_, err = repo.RecordAttachmentScan(ctx, plan.ID, &models.AttachmentScan{Status: models.ScanStatusClean})
+	expectErr(t, err, repository.ErrAttachmentNotFound)
+
+	quarantined, err = repo.GetQuarantinedAttachments(ctx, 10)
+	noErr(t, err)
+	if len(quarantined) != 2 || quarantined[0].ID != specs.ID || quarantined[1].ID != offer.ID {
+		t.Fatalf("the unscanned uploads expected, got %+v", quarantined)
+	}
+
+	_, err = repo.GetAttachment(ctx, missingBid)
+	expectErr(t, err, repository.ErrAttachmentNotFound)
+
+	attachments, err := repo.GetAttachmentsByIDs(ctx, []string{specs.ID, missingBid, plan.ID})
+	noErr(t, err)
+	if len(attachments) != 2 || attachments[0].ID != specs.ID || attachments[1].ID != plan.ID {
+		t.Fatalf("the attachments in the order asked expected, got %+v", attachments)
+	}
+}
+
+func testVisibility(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	invitation := publishTender(t, repo, newTender("D invited", func(c *models.TenderCreate) {
+		c.Visibility, c.InvitedOrganizations = models.TenderVisibilityInvitation, []models.OrganizationID{supplierOrg}
+	}), nil)
+	if invitation.Visibility != models.TenderVisibilityInvitation {
+		t.Fatalf("invitation-only tender expected, got %+v", invitation)
+	}
+
+	internal := publishTender(t, repo, newTender("E internal", func(c *models.TenderCreate) {
+		c.Visibility = models.TenderVisibilityInternal
+	}), nil)
+
+	listed := func(userID string, want ...string) {
+		t.Helper()
+		tenders, err := repo.GetAllTenders(ctx, nil, userID, 10, 0)
+		noErr(t, err)
+		if len(tenders) != len(want) {
+			t.Fatalf("user %q: %d tenders expected, got %+v", userID, len(want), tenders)
+		}
+		for i, tender := range tenders {
+			if tender.ID != want[i] {
+				t.Fatalf("user %q: tender %s expected at %d, got %s", userID, want[i], i, tender.ID)
+			}
+		}
+	}
+	listed("", publishedTender)
+	listed(carlID, publishedTender)
+	listed(bobID, publishedTender, invitation.ID)
+	listed(aliceID, publishedTender, invitation.ID, internal.ID)
+
+	invited, err := repo.GetInvitedTenders(ctx, bellaID, 10, 0)
+	noErr(t, err)
+	if len(invited) != 1 || invited[0].ID != invitation.ID {
+		t.Fatalf("the invitation-only tender expected, got %+v", invited)
+	}
+
+	invited, err = repo.GetInvitedTenders(ctx, aliceID, 10, 0)
+	noErr(t, err)
+	if len(invited) != 0 {
+		t.Fatalf("no invitations of the buyer expected, got %+v", invited)
+	}
+
+	expectErr(t, repo.ControlTenderVisibility(ctx, publishedTender, ""), nil)
+	expectErr(t, repo.ControlTenderVisibility(ctx, invitation.ID, bobID), nil)
+	expectErr(t, repo.ControlTenderVisibility(ctx, invitation.ID, carlID), repository.ErrRelationNotExist)
+	expectErr(t, repo.ControlTenderVisibility(ctx, internal.ID, annaID), nil)
+	expectErr(t, repo.ControlTenderVisibility(ctx, internal.ID, bobID), repository.ErrRelationNotExist)
+	expectErr(t, repo.ControlTenderVisibility(ctx, missingTender, bobID), repository.ErrTenderNotFound)
+
+	invitations, err := repo.InviteOrganizations(ctx, invitation.ID, []models.OrganizationID{supplierOrg})
+	noErr(t, err)
+	if len(invitations) != 1 || invitations[0].OrganizationID != supplierOrg || invitations[0].TenderID != invitation.ID {
+		t.Fatalf("the existing invitation expected, got %+v", invitations)
+	}
+
+	_, err = repo.InviteOrganizations(ctx, invitation.ID, []models.OrganizationID{missingOrg})
+	expectErr(t, err, repository.ErrOrganizationDepencyNotFound)
+
+	_, err = repo.GetTenderInvitations(ctx, missingTender)
+	expectErr(t, err, repository.ErrTenderNotFound)
+
+	invitations, err = repo.GetTenderInvitations(ctx, internal.ID)
+	noErr(t, err)
+	if len(invitations) != 0 {
+		t.Fatalf("no invitations expected, got %+v", invitations)
+	}
+
+	_, err = repo.BuildTender(ctx, newTender("F invited", func(c *models.TenderCreate) {
+		c.Visibility, c.InvitedOrganizations = models.TenderVisibilityInvitation, []models.OrganizationID{missingOrg}
+	}), nil)
+	expectErr(t, err, repository.ErrOrganizationDepencyNotFound)
+
+	// The visibility is not versioned and survives a rollback.
+	if rolledBack := rollBack(t, repo, internal.ID); rolledBack.Visibility != models.TenderVisibilityInternal {
+		t.Fatalf("internal tender expected after the rollback, got %+v", rolledBack)
+	}
+}
+
+func testPrequalification(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	_, err := repo.FindQuestionnaire(ctx, buyerOrg, models.TenderServiceTypeConstruction)
+	expectErr(t, err, repository.ErrQuestionnaireNotFound)
+
+	set := &models.QuestionnaireSet{
+		OrganizationID: buyerOrg, ServiceType: models.TenderServiceTypeConstruction,
+		Questions: []*models.PrequalificationQuestionCreate{
+			{Text: "Years on the market", AnswerType: models.AnswerTypeNumber, Required: true},
+		},
+	}
+	first, err := repo.SetQuestionnaire(ctx, set)
+	noErr(t, err)
+
+	// Until somebody applies the questions can be replaced.
+	set.Questions = append(set.Questions,
+		&models.PrequalificationQuestionCreate{Text: "Licensed", AnswerType: models.AnswerTypeBoolean, Required: true},
+		&models.PrequalificationQuestionCreate{Text: "Comments", AnswerType: models.AnswerTypeText})
+	questionnaire, err := repo.SetQuestionnaire(ctx, set)
+	noErr(t, err)
+	if questionnaire.ID != first.ID || len(questionnaire.Questions) != 3 || questionnaire.Questions[1].Text != "Licensed" {
+		t.Fatalf("the replaced questions expected, got %+v", questionnaire)
+	}
+
+	found, err := repo.FindQuestionnaire(ctx, buyerOrg, models.TenderServiceTypeConstruction)
+	noErr(t, err)
+	if found.ID != questionnaire.ID || len(found.Questions) != 3 {
+		t.Fatalf("the questionnaire expected, got %+v", found)
+	}
+
+	_, err = repo.SetQuestionnaire(ctx, &models.QuestionnaireSet{
+		OrganizationID: missingOrg, ServiceType: models.TenderServiceTypeConstruction, Questions: set.Questions,
+	})
+	expectErr(t, err, repository.ErrOrganizationDepencyNotFound)
+
+	_, err = repo.GetQuestionnaire(ctx, missingQuestionnaire)
+	expectErr(t, err, repository.ErrQuestionnaireNotFound)
+
+	expectErr(t, repo.ControlPrequalification(ctx, publishedTender, supplierOrg), repository.ErrNotPrequalified)
+
+	questions := questionnaire.Questions
+	create := &models.ApplicationCreate{
+		OrganizationID: supplierOrg,
+		Answers: []*models.Answer{
+			{QuestionID: questions[1].ID, Value: "true"},
+			{QuestionID: questions[0].ID, Value: "12"},
+		},
+	}
+	application, err := repo.SubmitApplication(ctx, questionnaire.ID, bobID, create)
+	noErr(t, err)
+	if application.Status != models.ApplicationStatusSubmitted || application.SubmittedBy != bobID ||
+		len(application.Answers) != 2 || application.Answers[0].QuestionID != questions[0].ID {
+		t.Fatalf("the answers in the order of the questions expected, got %+v", application)
+	}
+
+	_, err = repo.SubmitApplication(ctx, questionnaire.ID, bellaID, create)
+	expectErr(t, err, repository.ErrApplicationPending)
+
+	_, err = repo.SubmitApplication(ctx, missingQuestionnaire, bobID, create)
+	expectErr(t, err, repository.ErrQuestionnaireNotFound)
+
+	_, err = repo.SetQuestionnaire(ctx, set)
+	expectErr(t, err, repository.ErrQuestionnaireLocked)
+
+	license := newAttachment("", nil, "license")
+	license.ApplicationID = &application.ID
+	license.UploadedBy = bobID
+	license, err = repo.AttachToApplication(ctx, license)
+	noErr(t, err)
+	if license.TenderID != "" || *license.ApplicationID != application.ID {
+		t.Fatalf("a license of the application expected, got %+v", license)
+	}
+
+	licenses, err := repo.GetApplicationLicenses(ctx, application.ID)
+	noErr(t, err)
+	if len(licenses) != 1 || licenses[0].ID != license.ID {
+		t.Fatalf("the license expected, got %+v", licenses)
+	}
+
+	validUntil := time.Now().Add(24 * time.Hour)
+	approved, err := repo.ReviewApplication(ctx, application.ID, aliceID, &models.ApplicationReview{
+		Decision: models.ApplicationDecisionApproved, ValidUntil: &validUntil, Comment: "Welcome",
+	})
+	noErr(t, err)
+	if approved.Status != models.ApplicationStatusApproved || approved.ReviewedBy != aliceID ||
+		approved.ValidUntil == nil || approved.ReviewComment != "Welcome" {
+		t.Fatalf("an approved application expected, got %+v", approved)
+	}
+
+	_, err = repo.ReviewApplication(ctx, application.ID, aliceID, &models.ApplicationReview{Decision: models.ApplicationDecisionRejected})
+	expectErr(t, err, repository.ErrApplicationNotPending)
+
+	_, err = repo.AttachToApplication(ctx, &models.Attachment{
+		ApplicationID: &application.ID, Name: "late", ContentType: "application/pdf", Size: 3,
+		SHA256: license.SHA256, StorageKey: "late-key", UploadedBy: bobID,
+	})
+	expectErr(t, err, repository.ErrApplicationNotPending)
+
+	expectErr(t, repo.ControlPrequalification(ctx, publishedTender, supplierOrg), nil)
+	expectErr(t, repo.ControlPrequalification(ctx, publishedTender, buyerOrg), repository.ErrNotPrequalified)
+	expectErr(t, repo.ControlPrequalification(ctx, missingTender, supplierOrg), repository.ErrNotPrequalified)
+	// The approval covers the service type of the questionnaire only.
+	expectErr(t, repo.ControlPrequalification(ctx, closedTender, supplierOrg), repository.ErrNotPrequalified)
+
+	mine, err := repo.GetApplicationsOfUser(ctx, bellaID, 10, 0)
+	noErr(t, err)
+	if len(mine) != 1 || mine[0].ID != application.ID {
+		t.Fatalf("the application of the supplier expected, got %+v", mine)
+	}
+
+	revoked, err := repo.RevokeApplication(ctx, application.ID, annaID, "Forged license")
+	noErr(t, err)
+	if revoked.Status != models.ApplicationStatusRevoked || revoked.RevokedBy != annaID || revoked.RevocationReason != "Forged license" {
+		t.Fatalf("a revoked application expected, got %+v", revoked)
+	}
+
+	_, err = repo.RevokeApplication(ctx, application.ID, annaID, "Again")
+	expectErr(t, err, repository.ErrApplicationNotRevocable)
+	expectErr(t, repo.ControlPrequalification(ctx, publishedTender, supplierOrg), repository.ErrNotPrequalified)
+
+	// An expired approval does not count either.
+	renewal, err := repo.SubmitApplication(ctx, questionnaire.ID, bobID, create)
+	noErr(t, err)
+	expired := time.Now().Add(-time.Minute)
+	_, err = repo.ReviewApplication(ctx, renewal.ID, aliceID, &models.ApplicationReview{
+		Decision: models.ApplicationDecisionApproved, ValidUntil: &expired,
+	})
+	noErr(t, err)
+	expectErr(t, repo.ControlPrequalification(ctx, publishedTender, supplierOrg), repository.ErrNotPrequalified)
+
+	applications, err := repo.GetApplicationsOfQuestionnaire(ctx, questionnaire.ID, 10, 0)
+	noErr(t, err)
+	if len(applications) != 2 || applications[0].ID != renewal.ID {
+		t.Fatalf("the latest application first expected, got %+v", applications)
+	}
+
+	_, err = repo.GetApplication(ctx, missingApplication)
+	expectErr(t, err, repository.ErrApplicationNotFound)
+
+	// The requirement is not versioned and survives a rollback.
+	tender, err := repo.BuildTender(ctx, newTender("D foundation", func(c *models.TenderCreate) {
+		c.ServiceType, c.Prequalification = models.TenderServiceTypeConstruction, true
+	}), nil)
+	noErr(t, err)
+	if !tender.Prequalification {
+		t.Fatalf("a tender requiring prequalification expected, got %+v", tender)
+	}
+
+	if rolledBack := rollBack(t, repo, tender.ID); !rolledBack.Prequalification {
+		t.Fatalf("prequalification expected after the rollback, got %+v", rolledBack)
+	}
+}
+
+func testDebarment(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, bobID, nil), nil)
+
+	_, err := repo.CreateDebarment(ctx, aliceID, &models.DebarmentCreate{
+		SupplierID: missingOrg, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(buyerOrg), Reason: "Fraud",
+	}, "created")
+	expectErr(t, err, repository.ErrOrganizationDepencyNotFound)
+
+	validUntil := time.Now().Add(24 * time.Hour)
+	debarment, err := repo.CreateDebarment(ctx, aliceID, &models.DebarmentCreate{
+		SupplierID: supplierOrg, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(buyerOrg),
+		Reason: "Late deliveries", ValidUntil: &validUntil,
+	}, "created")
+	noErr(t, err)
+	if debarment.CreatedBy != aliceID || debarment.Scope != models.DebarmentScopeOrganization ||
+		debarment.ValidUntil == nil || !debarment.InForce(time.Now()) {
+		t.Fatalf("a debarment in force expected, got %+v", debarment)
+	}
+
+	// A debarment by another organization does not concern its tenders.
+	other, err := repo.CreateDebarment(ctx, bobID, &models.DebarmentCreate{
+		SupplierID: buyerOrg, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(supplierOrg), Reason: "Unpaid invoices",
+	}, "created")
+	noErr(t, err)
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, aliceID, nil), nil)
+
+	err = repo.ControlDebarment(ctx, publishedTender, bellaID, nil)
+	var debarred *repository.DebarredError
+	if !errors.As(err, &debarred) || debarred.Debarment.ID != debarment.ID || debarred.Debarment.Reason != "Late deliveries" {
+		t.Fatalf("the debarment behind the error expected, got %v", err)
+	}
+	expectErr(t, err, repository.ErrSupplierDebarred)
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, bobID, ptr(supplierOrg)), repository.ErrSupplierDebarred)
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, bobID, ptr(buyerOrg)), nil)
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, carlID, nil), nil)
+
+	// A global debarment without an end outlasts the others.
+	global, err := repo.CreateDebarment(ctx, carlID, &models.DebarmentCreate{
+		SupplierID: supplierOrg, Scope: models.DebarmentScopeGlobal, Reason: "Sanctions",
+	}, "created")
+	noErr(t, err)
+	if global.OrganizationID != nil || global.ValidUntil != nil {
+		t.Fatalf("a global debarment without an end expected, got %+v", global)
+	}
+
+	err = repo.ControlDebarment(ctx, closedTender, bobID, nil)
+	if !errors.As(err, &debarred) || debarred.Debarment.ID != global.ID {
+		t.Fatalf("the global debarment expected, got %v", err)
+	}
+
+	debarments, err := repo.GetDebarments(ctx, ptr(buyerOrg), 10, 0)
+	noErr(t, err)
+	if len(debarments) != 2 || debarments[0].ID != global.ID || debarments[1].ID != debarment.ID {
+		t.Fatalf("the debarments of the buyer, the latest first, expected, got %+v", debarments)
+	}
+
+	debarments, err = repo.GetDebarments(ctx, nil, 10, 0)
+	noErr(t, err)
+	if len(debarments) != 3 {
+		t.Fatalf("all the debarments expected, got %+v", debarments)
+	}
+
+	mine, err := repo.GetDebarmentsOfUser(ctx, bellaID, 10, 0)
+	noErr(t, err)
+	if len(mine) != 2 || mine[0].ID != debarment.ID {
+		t.Fatalf("the debarments of the supplier expected, got %+v", mine)
+	}
+
+	lifted, err := repo.LiftDebarment(ctx, global.ID, carlID, "Sanctions lifted")
+	noErr(t, err)
+	if lifted.LiftedAt == nil || lifted.LiftedBy != carlID || lifted.LiftReason != "Sanctions lifted" {
+		t.Fatalf("a lifted debarment expected, got %+v", lifted)
+	}
+
+	_, err = repo.LiftDebarment(ctx, global.ID, carlID, "Again")
+	expectErr(t, err, repository.ErrDebarmentLifted)
+	_, err = repo.UpdateDebarment(ctx, global.ID, carlID, &models.DebarmentEdit{Reason: ptr("Too late")}, "changed")
+	expectErr(t, err, repository.ErrDebarmentLifted)
+
+	err = repo.ControlDebarment(ctx, closedTender, bobID, nil)
+	if !errors.As(err, &debarred) || debarred.Debarment.ID != debarment.ID {
+		t.Fatalf("the debarment of the buyer expected after the lift, got %v", err)
+	}
+
+	// An expired debarment does not block either.
+	expired := time.Now().Add(-time.Minute)
+	changed, err := repo.UpdateDebarment(ctx, debarment.ID, annaID, &models.DebarmentEdit{ValidUntil: &expired}, "valid until now")
+	noErr(t, err)
+	if changed.Reason != "Late deliveries" || !changed.ValidUntil.Before(time.Now()) {
+		t.Fatalf("an expired debarment expected, got %+v", changed)
+	}
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, bobID, nil), nil)
+
+	mine, err = repo.GetDebarmentsOfUser(ctx, bobID, 10, 0)
+	noErr(t, err)
+	if len(mine) != 0 {
+		t.Fatalf("no debarments in force expected, got %+v", mine)
+	}
+
+	// Nor does one that has not started yet.
+	validFrom := time.Now().Add(time.Hour)
+	_, err = repo.CreateDebarment(ctx, aliceID, &models.DebarmentCreate{
+		SupplierID: supplierOrg, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(buyerOrg),
+		Reason: "Repeated fraud", ValidFrom: &validFrom,
+	}, "created")
+	noErr(t, err)
+	expectErr(t, repo.ControlDebarment(ctx, publishedTender, bobID, nil), nil)
+
+	audit, err := repo.GetDebarmentAudit(ctx, global.ID, 10, 0)
+	noErr(t, err)
+	if len(audit) != 2 || audit[0].Action != models.AuditActionDebarmentCreated ||
+		audit[1].Action != models.AuditActionDebarmentLifted || audit[1].Details != "Sanctions lifted" ||
+		*audit[1].ActorID != carlID || audit[1].DebarmentID != global.ID {
+		t.Fatalf("the creation and the lift expected, got %+v", audit)
+	}
+
+	audit, err = repo.GetDebarmentAudit(ctx, other.ID, 10, 0)
+	noErr(t, err)
+	if len(audit) != 1 || audit[0].Details != "created" {
+		t.Fatalf("the creation expected, got %+v", audit)
+	}
+
+	_, err = repo.GetDebarment(ctx, missingDebarment)
+	expectErr(t, err, repository.ErrDebarmentNotFound)
+	_, err = repo.LiftDebarment(ctx, missingDebarment, aliceID, "None")
+	expectErr(t, err, repository.ErrDebarmentNotFound)
+}
+
+func testLoaders(t *testing.T, repo Repository) {
+	ctx := context.Background()
+
+	tenders, err := repo.GetTendersByIDs(ctx, []string{publishedTender, closedTender, missingTender})
+	noErr(t, err)
+	if len(tenders) != 2 {
+		t.Fatalf("two tenders expected, got %d", len(tenders))
+	}
+
+	bids, err := repo.GetBidsByIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(bids) != 1 || bids[0].AuthorID != bobID {
+		t.Fatalf("unexpected bids %+v", bids)
+	}
+
+	byTender, err := repo.GetBidsByTenderIDs(ctx, []string{publishedTender, draftTender})
+	noErr(t, err)
+	if len(byTender) != 1 {
+		t.Fatalf("one bid expected, got %d", len(byTender))
+	}
+
+	employees, err := repo.GetEmployeesByIDs(ctx, []string{aliceID, bobID, nobody})
+	noErr(t, err)
+	if len(employees) != 2 {
+		t.Fatalf("two employees expected, got %d", len(employees))
+	}
+
+	byName, err := repo.GetEmployeesByUsernames(ctx, []string{anna, unknown})
+	noErr(t, err)
+	if len(byName) != 1 || byName[0].ID != annaID {
+		t.Fatalf("anna expected, got %+v", byName)
+	}
+
+	organizations, err := repo.GetOrganizationsByIDs(ctx, []string{string(buyerOrg), string(missingOrg)})
+	noErr(t, err)
+	if len(organizations) != 1 || organizations[0].Type != models.OrganizationTypeLLC {
+		t.Fatalf("the buyer organization expected, got %+v", organizations)
+	}
+
+	feedback, err := repo.GetFeedbackByBidIDs(ctx, []string{publishedBid})
+	noErr(t, err)
+	if len(feedback) != 0 {
+		t.Fatalf("no feedback expected, got %d", len(feedback))
+	}
+}
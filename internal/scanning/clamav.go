@@ -0,0 +1,102 @@
+package scanning
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+// chunkSize stays well below the StreamMaxLength of clamd, which defaults
+// to 25 MiB per file, not per chunk.
+const chunkSize = 64 << 10
+
+// ClamAV streams the contents to clamd with the INSTREAM command of its TCP
+// protocol.
+type ClamAV struct {
+	address string
+	timeout time.Duration
+}
+
+func NewClamAV(cfg *config.ClamAVConfig) *ClamAV {
+	return &ClamAV{address: cfg.Address, timeout: cfg.Timeout}
+}
+
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", fmt.Errorf("scanning: connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	// clamd replies and hangs up as soon as a stream exceeds its limit, so
+	// the reply is read even when sending failed.
+	streamErr := stream(conn, r)
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		if streamErr != nil {
+			return "", fmt.Errorf("scanning: send to clamd: %w", streamErr)
+		}
+		return "", fmt.Errorf("scanning: read clamd reply: %w", err)
+	}
+
+	return parseReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// stream sends r as length-prefixed chunks and ends with an empty one.
+func stream(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, err := w.Write(buf[:4+n]); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseReply reads "stream: OK", "stream: <threat> FOUND" or an error such
+// as "INSTREAM size limit exceeded. ERROR".
+func parseReply(reply string) (string, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrRejected, strings.TrimSpace(reply))
+	}
+}
@@ -0,0 +1,253 @@
+package scanning
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+// readStream is the clamd side of INSTREAM, it returns the size of every
+// chunk and the contents put together.
+func readStream(r io.Reader) ([]int, []byte, error) {
+	command := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(r, command); err != nil {
+		return nil, nil, err
+	}
+	if string(command) != "zINSTREAM\x00" {
+		return nil, nil, errors.New("unexpected command " + string(command))
+	}
+
+	var sizes []int
+	var contents bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, nil, err
+		}
+		sizes = append(sizes, int(size))
+		if size == 0 {
+			return sizes, contents.Bytes(), nil
+		}
+
+		if _, err := io.CopyN(&contents, r, int64(size)); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		sizes []int
+	}{
+		{"empty", 0, []int{0}},
+		{"small", 10, []int{10, 0}},
+		{"one full chunk", chunkSize, []int{chunkSize, 0}},
+		{"several chunks", 2*chunkSize + 100, []int{chunkSize, chunkSize, 100, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents := bytes.Repeat([]byte("offer "), tt.size/6+1)[:tt.size]
+
+			var sent bytes.Buffer
+			if err := stream(&sent, bytes.NewReader(contents)); err != nil {
+				t.Fatalf("stream: %v", err)
+			}
+
+			sizes, got, err := readStream(&sent)
+			if err != nil {
+				t.Fatalf("read stream: %v", err)
+			}
+			if !slices.Equal(sizes, tt.sizes) {
+				t.Fatalf("got chunks %v, want %v", sizes, tt.sizes)
+			}
+			if !bytes.Equal(got, contents) {
+				t.Fatal("the chunks must put the contents back together")
+			}
+			if sent.Len() != 0 {
+				t.Fatalf("%d bytes after the terminating chunk", sent.Len())
+			}
+		})
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		reply  string
+		threat string
+		err    error
+	}{
+		{"stream: OK", "", nil},
+		{"stream: Eicar-Test-Signature FOUND", "Eicar-Test-Signature", nil},
+		{"stream: Win.Trojan.Agent-1 FOUND", "Win.Trojan.Agent-1", nil},
+		{"INSTREAM size limit exceeded. ERROR", "", ErrRejected},
+		{"stream: Can't allocate memory ERROR", "", ErrRejected},
+		{"UNKNOWN COMMAND", "", ErrRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			threat, err := parseReply(tt.reply)
+			if threat != tt.threat || !errors.Is(err, tt.err) {
+				t.Fatalf("got %q, %v, want %q, %v", threat, err, tt.threat, tt.err)
+			}
+		})
+	}
+}
+
+// fakeClamd accepts a single connection and hands it to serve.
+func fakeClamd(t *testing.T, serve func(conn net.Conn)) *ClamAV {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(conn)
+	}()
+
+	return NewClamAV(&config.ClamAVConfig{Address: listener.Addr().String(), Timeout: 5 * time.Second})
+}
+
+// replyAfterStream reads the whole stream, then replies.
+func replyAfterStream(reply string) func(net.Conn) {
+	return func(conn net.Conn) {
+		if _, _, err := readStream(conn); err != nil {
+			return
+		}
+		io.WriteString(conn, reply+"\x00")
+	}
+}
+
+func TestClamAVScan(t *testing.T) {
+	tests := []struct {
+		name   string
+		serve  func(net.Conn)
+		threat string
+		err    error
+	}{
+		{"clean", replyAfterStream("stream: OK"), "", nil},
+		{"found", replyAfterStream("stream: Eicar-Test-Signature FOUND"), "Eicar-Test-Signature", nil},
+		{"error", replyAfterStream("INSTREAM size limit exceeded. ERROR"), "", ErrRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd := fakeClamd(t, tt.serve)
+
+			threat, err := clamd.Scan(context.Background(), strings.NewReader("contents"))
+			if threat != tt.threat || !errors.Is(err, tt.err) {
+				t.Fatalf("got %q, %v, want %q, %v", threat, err, tt.threat, tt.err)
+			}
+		})
+	}
+}
+
+func TestClamAVScanStreamsContents(t *testing.T) {
+	contents := bytes.Repeat([]byte{0xAB}, chunkSize+1)
+	received := make(chan []byte, 1)
+
+	clamd := fakeClamd(t, func(conn net.Conn) {
+		_, got, err := readStream(conn)
+		if err != nil {
+			received <- nil
+			return
+		}
+		received <- got
+		io.WriteString(conn, "stream: OK\x00")
+	})
+
+	if _, err := clamd.Scan(context.Background(), bytes.NewReader(contents)); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, contents) {
+		t.Fatalf("clamd got %d bytes, want %d", len(got), len(contents))
+	}
+}
+
+// TestClamAVScanEarlyHangUp has clamd reply and hang up in the middle of a
+// stream that is too long for it, the reply must win over the failed send.
+func TestClamAVScanEarlyHangUp(t *testing.T) {
+	clamd := fakeClamd(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		if _, err := r.Discard(len("zINSTREAM\x00") + 4 + chunkSize); err != nil {
+			return
+		}
+		io.WriteString(conn, "INSTREAM size limit exceeded. ERROR\x00")
+	})
+
+	// Far more than the socket buffers take, so that sending fails.
+	contents := io.LimitReader(zeros{}, 64<<20)
+
+	_, err := clamd.Scan(context.Background(), contents)
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("got %v, want %v", err, ErrRejected)
+	}
+}
+
+func TestClamAVScanFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		serve func(net.Conn)
+	}{
+		{"hang-up without a reply", func(conn net.Conn) {}},
+		{"reply cut short", func(conn net.Conn) {
+			readStream(conn)
+			io.WriteString(conn, "stream: O")
+		}},
+		{"no reply in time", func(conn net.Conn) {
+			readStream(conn)
+			time.Sleep(time.Second)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd := fakeClamd(t, tt.serve)
+			clamd.timeout = 200 * time.Millisecond
+
+			threat, err := clamd.Scan(context.Background(), strings.NewReader("contents"))
+			if err == nil || errors.Is(err, ErrRejected) || threat != "" {
+				t.Fatalf("got %q, %v, want a failure to scan", threat, err)
+			}
+		})
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	listener.Close()
+
+	clamd := NewClamAV(&config.ClamAVConfig{Address: listener.Addr().String(), Timeout: time.Second})
+	if _, err := clamd.Scan(context.Background(), strings.NewReader("contents")); err == nil {
+		t.Fatal("scanning without clamd must fail")
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
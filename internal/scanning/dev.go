@@ -0,0 +1,54 @@
+package scanning
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// Noop declares everything clean without looking at it.
+type Noop struct{}
+
+func (Noop) Scan(ctx context.Context, r io.Reader) (string, error) {
+	_, err := io.Copy(io.Discard, r)
+	return "", err
+}
+
+// eicarSignature is the standard antivirus test file. It is harmless, but
+// every scanner reports it, which makes it handy to try the quarantine out.
+const eicarSignature = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// EICARThreat is the name ClamAV reports the test file under.
+const EICARThreat = "Eicar-Test-Signature"
+
+// EICAR only detects the EICAR test file, anywhere in the contents.
+type EICAR struct{}
+
+func (EICAR) Scan(ctx context.Context, r io.Reader) (string, error) {
+	signature := []byte(eicarSignature)
+	buf := make([]byte, 32<<10)
+
+	// kept carries the end of the previous chunk over, so that a signature
+	// split between two reads is found as well.
+	var kept []byte
+	for {
+		n, err := r.Read(buf)
+		window := append(kept, buf[:n]...)
+		if bytes.Contains(window, signature) {
+			return EICARThreat, nil
+		}
+
+		if len(window) >= len(signature) {
+			kept = append(kept[:0], window[len(window)-len(signature)+1:]...)
+		} else {
+			kept = window
+		}
+
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package scanning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEICAR(t *testing.T) {
+	padding := strings.Repeat("offer ", 10<<10)
+
+	tests := []struct {
+		name   string
+		r      io.Reader
+		threat string
+	}{
+		{"test file", strings.NewReader(eicarSignature), EICARThreat},
+		{"inside the contents", strings.NewReader(padding + eicarSignature + padding), EICARThreat},
+		{"split between reads", iotest.OneByteReader(strings.NewReader(padding + eicarSignature)), EICARThreat},
+		{"across the buffer", strings.NewReader(strings.Repeat("x", 32<<10-10) + eicarSignature), EICARThreat},
+		{"at the very end", iotest.HalfReader(strings.NewReader(padding + eicarSignature)), EICARThreat},
+		{"clean", strings.NewReader(padding), ""},
+		{"truncated signature", strings.NewReader(padding + eicarSignature[:len(eicarSignature)-1]), ""},
+		{"empty", strings.NewReader(""), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threat, err := EICAR{}.Scan(context.Background(), tt.r)
+			if err != nil || threat != tt.threat {
+				t.Fatalf("got %q, %v, want %q", threat, err, tt.threat)
+			}
+		})
+	}
+}
+
+func TestEICARReadError(t *testing.T) {
+	failure := errors.New("disk gone")
+
+	_, err := EICAR{}.Scan(context.Background(), io.MultiReader(strings.NewReader("offer"), iotest.ErrReader(failure)))
+	if !errors.Is(err, failure) {
+		t.Fatalf("got %v, want %v", err, failure)
+	}
+}
+
+func TestNoop(t *testing.T) {
+	r := bytes.NewReader([]byte(eicarSignature))
+
+	threat, err := Noop{}.Scan(context.Background(), r)
+	if err != nil || threat != "" {
+		t.Fatalf("got %q, %v, want everything clean", threat, err)
+	}
+	if r.Len() != 0 {
+		t.Fatal("the contents must be read to the end")
+	}
+}
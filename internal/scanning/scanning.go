@@ -0,0 +1,36 @@
+// Package scanning checks the contents of attachments for malware before
+// they can be downloaded.
+package scanning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+// ErrRejected is returned for contents the scanner refuses to scan, e.g.
+// because they exceed its size limit. Trying again does not help.
+var ErrRejected = errors.New("scanning: rejected by the scanner")
+
+type Scanner interface {
+	// Scan reads r to the end and returns the name of the threat found in
+	// it, or an empty string when it is clean. An error means the contents
+	// could not be scanned and says nothing about them.
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+func New(cfg *config.ScanningConfig) (Scanner, error) {
+	switch cfg.Driver {
+	case config.ScanningNoop:
+		return Noop{}, nil
+	case config.ScanningEICAR:
+		return EICAR{}, nil
+	case config.ScanningClamAV:
+		return NewClamAV(&cfg.ClamAV), nil
+	default:
+		return nil, fmt.Errorf("scanning: unknown driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,114 @@
+// Package sealing encrypts the contents of the bids on sealed tenders. Every
+// tender has its own data key, which is stored wrapped by the master key.
+package sealing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+const keySize = 32
+
+var errMalformed = errors.New("sealing: malformed ciphertext")
+
+type Sealer struct {
+	master cipher.AEAD
+}
+
+// New returns nil when no master key is configured, which disables sealed
+// tenders.
+func New(cfg *config.SealingConfig) (*Sealer, error) {
+	if cfg.MasterKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.MasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("sealing: decode master key: %w", err)
+	}
+
+	master, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sealer{master: master}, nil
+}
+
+// NewKey generates a data key for a tender and returns it wrapped.
+func (s *Sealer) NewKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return seal(s.master, key, nil)
+}
+
+// Seal encrypts plaintext with the wrapped data key. The additional data is
+// authenticated, so a ciphertext cannot be moved to another tender.
+func (s *Sealer) Seal(wrappedKey, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := s.unwrap(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(aead, plaintext, additionalData)
+}
+
+func (s *Sealer) Open(wrappedKey, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := s.unwrap(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, ciphertext, additionalData)
+}
+
+func (s *Sealer) unwrap(wrappedKey []byte) (cipher.AEAD, error) {
+	key, err := open(s.master, wrappedKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sealing: unwrap data key: %w", err)
+	}
+
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("sealing: key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal prefixes the ciphertext with a random nonce.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errMalformed
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
@@ -0,0 +1,118 @@
+package sealing
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+func newSealer(t *testing.T, fill byte) *Sealer {
+	t.Helper()
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, keySize))
+	sealer, err := New(&config.SealingConfig{MasterKey: key})
+	if err != nil {
+		t.Fatalf("new sealer: %v", err)
+	}
+
+	return sealer
+}
+
+func TestNew(t *testing.T) {
+	sealer, err := New(&config.SealingConfig{})
+	if err != nil || sealer != nil {
+		t.Fatalf("no master key must disable sealing, got %v, %v", sealer, err)
+	}
+
+	for name, key := range map[string]string{
+		"not base64": "not a key!",
+		"short key":  base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	} {
+		if _, err := New(&config.SealingConfig{MasterKey: key}); err == nil {
+			t.Fatalf("%s: error expected", name)
+		}
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	sealer := newSealer(t, 1)
+	plaintext, ad := []byte(`{"name":"Offer"}`), []byte("tender/bid/1")
+
+	wrappedKey, err := sealer.NewKey()
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+
+	ciphertext, err := sealer.Seal(wrappedKey, plaintext, ad)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("the plaintext must not show in the ciphertext")
+	}
+
+	again, err := sealer.Seal(wrappedKey, plaintext, ad)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Equal(ciphertext, again) {
+		t.Fatal("every seal must take a fresh nonce")
+	}
+
+	opened, err := sealer.Open(wrappedKey, ciphertext, ad)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("got %q, want %q", opened, plaintext)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	sealer := newSealer(t, 1)
+	ad := []byte("tender/bid/1")
+
+	wrappedKey, err := sealer.NewKey()
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+	otherKey, err := sealer.NewKey()
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+
+	ciphertext, err := sealer.Seal(wrappedKey, []byte("secret"), ad)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		sealer     *Sealer
+		wrappedKey []byte
+		ciphertext []byte
+		ad         []byte
+	}{
+		{"wrong additional data", sealer, wrappedKey, ciphertext, []byte("tender/bid/2")},
+		{"missing additional data", sealer, wrappedKey, ciphertext, nil},
+		{"tampered ciphertext", sealer, wrappedKey, tampered, ad},
+		{"short ciphertext", sealer, wrappedKey, ciphertext[:8], ad},
+		{"empty ciphertext", sealer, wrappedKey, nil, ad},
+		{"data key of another tender", sealer, otherKey, ciphertext, ad},
+		{"wrong master key", newSealer(t, 2), wrappedKey, ciphertext, ad},
+		{"malformed wrapped key", sealer, wrappedKey[:4], ciphertext, ad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if opened, err := tt.sealer.Open(tt.wrappedKey, tt.ciphertext, tt.ad); err == nil {
+				t.Fatalf("open must fail, got %q", opened)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Package seed generates a reproducible demo dataset. Everything, the
+// identifiers included, is derived from a single random seed, so the same
+// options always produce the same data.
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// Responsible links an employee to the organization they act for.
+type Responsible struct {
+	ID             string
+	OrganizationID models.OrganizationID
+	UserID         string
+}
+
+type Dataset struct {
+	Organizations  []models.Organization
+	Employees      []models.Employee
+	Responsibles   []Responsible
+	Tenders        []models.TenderResponse
+	TenderVersions []models.TenderResponse
+	Bids           []models.BidResponse
+	BidVersions    []models.BidResponse
+	Decisions      []models.BidDecisionResponse
+	Messages       []models.BidMessage
+}
+
+var (
+	organizationTypes = []models.OrganizationType{
+		models.OrganizationTypeIE,
+		models.OrganizationTypeLLC,
+		models.OrganizationTypeJSC,
+	}
+	serviceTypes = []models.TenderServiceType{
+		models.TenderServiceTypeConstruction,
+		models.TenderServiceTypeDelivery,
+		models.TenderServiceTypeManufacture,
+	}
+
+	firstNames = []string{"Ivan", "Anna", "Sergey", "Olga", "Dmitry", "Maria", "Alexey", "Elena", "Pavel", "Irina"}
+	lastNames  = []string{"Petrov", "Ivanova", "Smirnov", "Kuznetsova", "Popov", "Sokolova", "Lebedev", "Novikova", "Morozov", "Volkova"}
+
+	companyWords = []string{"North", "Stone", "River", "Vector", "Prime", "Atlas", "Granite", "Arctic", "Delta", "Summit"}
+	companyKinds = []string{"Build", "Logistics", "Works", "Supply", "Systems", "Industries", "Trade", "Engineering"}
+
+	subjects = map[models.TenderServiceType][]string{
+		models.TenderServiceTypeConstruction: {"warehouse extension", "office renovation", "road repair", "roof replacement"},
+		models.TenderServiceTypeDelivery:     {"office supplies", "cement delivery", "fuel supply", "IT equipment"},
+		models.TenderServiceTypeManufacture:  {"steel frames", "uniforms", "packaging", "spare parts"},
+	}
+)
+
+// epoch is the creation time of the oldest generated record.
+var epoch = time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+
+type generator struct {
+	rnd  *rand.Rand
+	now  time.Time
+	data *Dataset
+
+	employeesByOrg map[models.OrganizationID][]models.Employee
+	orgOf          map[string]models.OrganizationID
+}
+
+// Generate builds a dataset sized by cfg.
+func Generate(cfg *config.SeedConfig) *Dataset {
+	g := &generator{
+		rnd:            rand.New(rand.NewPCG(cfg.RandomSeed, cfg.RandomSeed^0x5eed)),
+		now:            epoch,
+		data:           &Dataset{},
+		employeesByOrg: make(map[models.OrganizationID][]models.Employee),
+		orgOf:          make(map[string]models.OrganizationID),
+	}
+
+	for _, orgType := range organizationTypes {
+		for i := 0; i < cfg.OrganizationsPerType; i++ {
+			org := g.organization(orgType)
+			for j := 0; j < cfg.EmployeesPerOrganization; j++ {
+				g.employee(org.ID)
+			}
+		}
+	}
+
+	organizations := g.data.Organizations
+	for _, org := range organizations {
+		for i := 0; i < cfg.TendersPerOrganization; i++ {
+			tender := g.tender(org.ID)
+			if tender.Status != models.TenderStatusCreated {
+				g.bids(tender, cfg.BidsPerTender)
+			}
+		}
+	}
+
+	return g.data
+}
+
+func (g *generator) id() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.rnd.UintN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// tick advances the clock, so that records are created in a plausible order.
+func (g *generator) tick() time.Time {
+	g.now = g.now.Add(time.Duration(1+g.rnd.IntN(180)) * time.Minute)
+	return g.now
+}
+
+func pick[T any](g *generator, items []T) T {
+	return items[g.rnd.IntN(len(items))]
+}
+
+func (g *generator) organization(orgType models.OrganizationType) models.Organization {
+	name := fmt.Sprintf("%s %s %s", pick(g, companyWords), pick(g, companyKinds), orgType)
+	createdAt := g.tick()
+
+	org := models.Organization{
+		ID:          models.OrganizationID(g.id()),
+		Name:        name,
+		Description: fmt.Sprintf("Demo organization #%d", len(g.data.Organizations)+1),
+		Type:        orgType,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	g.data.Organizations = append(g.data.Organizations, org)
+
+	return org
+}
+
+func (g *generator) employee(orgID models.OrganizationID) {
+	first, last := pick(g, firstNames), pick(g, lastNames)
+	createdAt := g.tick()
+
+	employee := models.Employee{
+		ID:        g.id(),
+		Username:  fmt.Sprintf("%s_%s%d", strings.ToLower(first), strings.ToLower(last), len(g.data.Employees)+1),
+		FirstName: first,
+		LastName:  last,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	g.data.Employees = append(g.data.Employees, employee)
+	g.data.Responsibles = append(g.data.Responsibles, Responsible{
+		ID:             g.id(),
+		OrganizationID: orgID,
+		UserID:         employee.ID,
+	})
+	g.employeesByOrg[orgID] = append(g.employeesByOrg[orgID], employee)
+	g.orgOf[employee.ID] = orgID
+}
+
+// tender creates a tender with its version history. A quarter of the
+// tenders stay Created, a quarter are Closed and the rest are Published.
+func (g *generator) tender(orgID models.OrganizationID) models.TenderResponse {
+	serviceType := pick(g, serviceTypes)
+	subject := pick(g, subjects[serviceType])
+
+	status := models.TenderStatusPublished
+	switch g.rnd.IntN(4) {
+	case 0:
+		status = models.TenderStatusCreated
+	case 1:
+		status = models.TenderStatusClosed
+	}
+
+	tender := models.TenderResponse{
+		ID:              g.id(),
+		Name:            fmt.Sprintf("%s: %s", serviceType, subject),
+		Description:     fmt.Sprintf("Procurement of %s.", subject),
+		ServiceType:     serviceType,
+		Status:          models.TenderStatusCreated,
+		OrganizationID:  orgID,
+		Version:         1,
+		CreatedAt:       g.tick(),
+		CreatorUsername: pick(g, g.employeesByOrg[orgID]).Username,
+	}
+
+	// Every change moves the previous state into tender_version, the last
+	// one sets the final status.
+	edits := g.rnd.IntN(3)
+	if status != models.TenderStatusCreated {
+		edits++
+	}
+	for i := 0; i < edits; i++ {
+		g.data.TenderVersions = append(g.data.TenderVersions, tender)
+		tender.Version++
+		if i == edits-1 {
+			tender.Status = status
+		} else {
+			tender.Description = fmt.Sprintf("Procurement of %s, revision %d.", subject, tender.Version)
+		}
+	}
+
+	// The versions do not track the visibility.
+	tender.Visibility = models.TenderVisibilityPublic
+	g.data.Tenders = append(g.data.Tenders, tender)
+
+	return tender
+}
+
+// bids creates up to n bids from employees of other organizations. On a
+// Closed tender one bid is approved by a quorum of the tender's responsibles.
+func (g *generator) bids(tender models.TenderResponse, n int) {
+	var candidates []models.Employee
+	for _, org := range g.data.Organizations {
+		if org.ID != tender.OrganizationID {
+			candidates = append(candidates, g.employeesByOrg[org.ID]...)
+		}
+	}
+	g.rnd.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+
+	responsibles := g.employeesByOrg[tender.OrganizationID]
+	quorum := min(3, len(responsibles))
+
+	winner := -1
+	if tender.Status == models.TenderStatusClosed && n > 0 {
+		winner = g.rnd.IntN(n)
+	}
+
+	// Half of the bids are made on behalf of the author's organization, at
+	// most one per organization.
+	bidders := make(map[models.OrganizationID]bool)
+
+	for i, author := range candidates[:n] {
+		status := models.BidStatusPublished
+		switch {
+		case i == winner:
+			status = models.BidStatusApproved
+		case g.rnd.IntN(5) == 0:
+			status = models.BidStatusCreated
+		case g.rnd.IntN(5) == 0:
+			status = models.BidStatusCanceled
+		}
+
+		var organizationID *models.OrganizationID
+		if orgID := g.orgOf[author.ID]; !bidders[orgID] && g.rnd.IntN(2) == 0 {
+			bidders[orgID] = true
+			organizationID = &orgID
+		}
+
+		bid := g.bid(tender, author, organizationID, status)
+
+		switch status {
+		case models.BidStatusApproved:
+			for _, user := range responsibles[:quorum] {
+				g.decision(bid.ID, user.ID, models.BidDecisionApproved)
+			}
+		case models.BidStatusCanceled:
+			g.decision(bid.ID, pick(g, responsibles).ID, models.BidDecisionRejected)
+		case models.BidStatusPublished:
+			// Stay below the quorum, otherwise the tender would be closed.
+			for _, user := range responsibles[:g.rnd.IntN(quorum)] {
+				g.decision(bid.ID, user.ID, models.BidDecisionApproved)
+			}
+		}
+
+		if status != models.BidStatusCreated {
+			for j := g.rnd.IntN(3); j > 0; j-- {
+				question := g.message(bid.ID, pick(g, responsibles).ID, models.MessageRoleBuyer, nil, pick(g, feedbackTexts))
+				if g.rnd.IntN(2) == 0 {
+					g.message(bid.ID, author.ID, models.MessageRoleBidder, &question.ID, pick(g, replyTexts))
+				}
+			}
+		}
+	}
+}
+
+func (g *generator) bid(tender models.TenderResponse, author models.Employee, organizationID *models.OrganizationID, status models.BidStatus) models.BidResponse {
+	bid := models.BidResponse{
+		ID:          g.id(),
+		Name:        fmt.Sprintf("Offer from %s %s", author.FirstName, author.LastName),
+		Description: fmt.Sprintf("Our offer for %q.", tender.Name),
+		Status:      models.BidStatusCreated,
+		TenderID:    tender.ID,
+		AuthorType:  models.BidAuthorTypeUser,
+		AuthorID:    author.ID,
+		Version:     1,
+		CreatedAt:   g.tick(),
+	}
+	if organizationID != nil {
+		bid.AuthorType = models.BidAuthorTypeOrganization
+		bid.OrganizationID = organizationID
+	}
+
+	edits := g.rnd.IntN(2)
+	if status != models.BidStatusCreated {
+		edits++
+	}
+	for i := 0; i < edits; i++ {
+		g.data.BidVersions = append(g.data.BidVersions, bid)
+		bid.Version++
+		if i == edits-1 {
+			bid.Status = status
+		} else {
+			bid.Description = fmt.Sprintf("Our revised offer for %q.", tender.Name)
+		}
+	}
+
+	g.data.Bids = append(g.data.Bids, bid)
+
+	return bid
+}
+
+func (g *generator) decision(bidID, userID string, decision models.BidDecision) {
+	g.data.Decisions = append(g.data.Decisions, models.BidDecisionResponse{
+		ID:       g.id(),
+		BidID:    bidID,
+		UserID:   userID,
+		Decision: decision,
+	})
+}
+
+var feedbackTexts = []string{
+	"Please clarify the delivery schedule.",
+	"The price is above our budget.",
+	"Good offer, waiting for the certificates.",
+	"Payment terms are acceptable.",
+}
+
+var replyTexts = []string{
+	"Thank you, the details are attached to the offer.",
+	"We will come back with an updated offer.",
+	"Agreed.",
+}
+
+func (g *generator) message(bidID, authorID string, role models.MessageRole, replyTo *string, body string) models.BidMessage {
+	message := models.BidMessage{
+		ID:        g.id(),
+		BidID:     bidID,
+		AuthorID:  &authorID,
+		Role:      role,
+		ReplyTo:   replyTo,
+		Body:      body,
+		CreatedAt: g.tick(),
+	}
+	g.data.Messages = append(g.data.Messages, message)
+
+	return message
+}
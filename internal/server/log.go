@@ -4,21 +4,47 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/logger"
 	"github.com/sirupsen/logrus"
 )
 
-func SetupLogger() *logrus.Logger {
+// SetupLogger builds the application logger. The json format writes one
+// compact object per line for production; pretty is meant for local
+// debugging and adds indentation and caller info.
+func SetupLogger(cfg *config.LogConfig) (*logrus.Logger, error) {
 	log := logrus.New()
-	log.SetReportCaller(true)
-	log.Formatter = &logrus.JSONFormatter{
-		CallerPrettyfier: func(f *runtime.Frame) (function string, file string) {
-			s := strings.Split(f.Function, ".")
-			fcname := s[len(s)-1]
-			return fcname, fmt.Sprintf("%s:%d", f.File, f.Line)
-		},
-		PrettyPrint: true,
+
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	log.SetLevel(level)
+
+	var formatter logrus.Formatter
+	switch cfg.Format {
+	case "json":
+		formatter = &logrus.JSONFormatter{}
+	case "pretty":
+		log.SetReportCaller(true)
+		formatter = &logrus.JSONFormatter{
+			CallerPrettyfier: func(f *runtime.Frame) (function string, file string) {
+				s := strings.Split(f.Function, ".")
+				fcname := s[len(s)-1]
+				return fcname, fmt.Sprintf("%s:%d", f.File, f.Line)
+			},
+			PrettyPrint: true,
+		}
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	if cfg.SampleInitial > 0 {
+		formatter = logger.NewSamplingFormatter(formatter, cfg.SampleInitial, cfg.SampleThereafter, time.Second)
 	}
+	log.Formatter = formatter
 
-	return log
+	return log, nil
 }
@@ -1,33 +1,172 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/sirupsen/logrus"
 )
 
-func Migrate(cfg *config.PGConfig, log *logrus.Logger) error {
-	m, err := migrate.New(
-		"file://migrations",
-		cfg.Conn)
+// Migrator manages the database schema with the migrations embedded into the
+// binary.
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+func NewMigrator(cfg *config.PGConfig) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
 	}
 
-	if err := m.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			log.Info("no new migrations to apply.")
-			return nil 
+	m, err := migrate.NewWithSourceInstance("iofs", src, cfg.Conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	// The source is owned by m, a second instance is used to read the latest
+	// version without moving m's cursor.
+	latest, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	return &Migrator{m: m, source: latest}, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr, m.source.Close())
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return noChange(m.m.Up())
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+
+	return noChange(m.m.Steps(-n))
+}
+
+// Goto migrates up or down to the given version.
+func (m *Migrator) Goto(version uint) error {
+	return noChange(m.m.Migrate(version))
+}
+
+// Force sets the version without running migrations and clears the dirty
+// flag. It is the way out after a migration failed halfway.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// MigrationStatus describes the schema state of the database.
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	latest, err := m.Latest()
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	return &MigrationStatus{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() (uint, error) {
+	version, err := m.source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	for {
+		next, err := m.source.Next(version)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return version, nil
+		case err != nil:
+			return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
 		}
+		version = next
+	}
+}
+
+func noChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+// Migrate prepares the schema on boot. With auto-migrate enabled it applies
+// all pending migrations, otherwise it leaves the database untouched. Either
+// way it returns the schema version this binary expects.
+func Migrate(cfg *config.PGConfig, log *logrus.Logger) (uint, error) {
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer m.Close()
 
-		return fmt.Errorf("failed to apply migrations: %w", err)
+	if !cfg.AutoMigrate {
+		log.Info("auto-migrate is disabled, run the migrate command to update the schema.")
+		return m.Latest()
 	}
 
+	if err := m.Up(); err != nil {
+		return 0, fmt.Errorf("failed to apply migrations: %w", err)
+	}
 	log.Info("migrations applied successfully.")
-	return nil
+
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	return status.Version, nil
+}
+
+type MigrationVersioner interface {
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+}
+
+// MigrationCheck fails when the database schema is dirty or differs from the
+// version this binary migrated to, e.g. after a rollback by another replica.
+func MigrationCheck(db MigrationVersioner, expected uint) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		version, dirty, err := db.MigrationVersion(ctx)
+		switch {
+		case err != nil:
+			return err
+		case dirty:
+			return fmt.Errorf("schema version %d is dirty", version)
+		case version != expected:
+			return fmt.Errorf("schema version %d, expected %d", version, expected)
+		}
+
+		return nil
+	}
 }
@@ -6,20 +6,27 @@ import (
 	"time"
 
 	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/health"
 )
 
 type Server struct {
 	httpServer *http.Server
+	probe      *health.Probe
+	cfg        *config.ServerConfig
 }
 
-func New(handler http.Handler, cfg *config.ServerConfig) *Server {
+func New(handler http.Handler, cfg *config.ServerConfig, probe *health.Probe) *Server {
 	return &Server{
 		httpServer: &http.Server{
-			Addr:         cfg.Address,
-			Handler:      handler,
-			ReadTimeout:  10 * time.Second,
-			WriteTimeout: 10 * time.Second,
+			Addr:              cfg.Address,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
 		},
+		probe: probe,
+		cfg:   cfg,
 	}
 }
 
@@ -27,6 +34,19 @@ func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// Shutdown first reports the instance as not ready and waits for the load
+// balancers to notice, then drains in-flight requests.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.probe.SetShuttingDown()
+
+	select {
+	case <-time.After(s.cfg.ShutdownDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+	defer cancel()
+
 	return s.httpServer.Shutdown(ctx)
 }
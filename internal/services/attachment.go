@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"slices"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/internal/scanning"
+	"github.com/DarRo9/Tenders/internal/storage"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AttachToTender streams a file into the blob store and adds it to a new
+// version of the tender. Only the creator of the tender attaches files, the
+// same way only the creator edits it.
+func (s *Service) AttachToTender(ctx context.Context, tenderID, username string, upload *models.AttachmentUpload, body io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := startSpan(ctx, "AttachToTender")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, repository.ErrAttachmentsDisabled
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.storeFile(ctx, upload, body)
+	if err != nil {
+		return nil, err
+	}
+	attachment.TenderID = tenderID
+	attachment.UploadedBy = userID
+
+	result, err := s.repo.AttachToTender(ctx, attachment)
+	if err != nil {
+		s.deleteFile(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"tender_id":     tenderID,
+		"attachment_id": result.ID,
+		"size":          result.Size,
+	}).Info("file attached to tender")
+
+	return result, nil
+}
+
+// DetachFromTender removes a file from a new version of the tender. The file
+// is kept for the older versions.
+func (s *Service) DetachFromTender(ctx context.Context, tenderID, attachmentID, username string) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "DetachFromTender")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.DetachFromTender(ctx, tenderID, attachmentID)
+}
+
+// GetTenderAttachments lists the files of the current version of a tender
+// to everybody who can read the tender.
+func (s *Service) GetTenderAttachments(ctx context.Context, tenderID, username string) (_ []*models.Attachment, err error) {
+	ctx, span := startSpan(ctx, "GetTenderAttachments")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.checkTenderReadable(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAttachmentsByIDs(ctx, tender.AttachmentIDs)
+}
+
+// OpenTenderAttachment opens a file of any version of a tender for
+// download. The caller closes the reader.
+func (s *Service) OpenTenderAttachment(ctx context.Context, tenderID, attachmentID, username string) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := startSpan(ctx, "OpenTenderAttachment")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, nil, repository.ErrAttachmentsDisabled
+	}
+
+	if _, err := s.checkTenderReadable(ctx, tenderID, username); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.TenderID != tenderID || attachment.BidID != nil {
+		return nil, nil, repository.ErrAttachmentNotFound
+	}
+
+	return s.openFile(ctx, attachment)
+}
+
+// AttachToBid streams a file into the blob store and adds it to a new
+// version of the bid. The bid must still be open for changes.
+func (s *Service) AttachToBid(ctx context.Context, bidID, username string, upload *models.AttachmentUpload, body io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := startSpan(ctx, "AttachToBid")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, repository.ErrAttachmentsDisabled
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
+		return nil, err
+	}
+
+	bid, _, err := s.checkBidOpen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.storeFile(ctx, upload, body)
+	if err != nil {
+		return nil, err
+	}
+	attachment.TenderID = bid.TenderID
+	attachment.BidID = &bid.ID
+	attachment.UploadedBy = userID
+
+	result, err := s.repo.AttachToBid(ctx, attachment)
+	if err != nil {
+		s.deleteFile(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"bid_id":        bidID,
+		"attachment_id": result.ID,
+		"size":          result.Size,
+	}).Info("file attached to bid")
+
+	return result, nil
+}
+
+// DetachFromBid removes a file from a new version of the bid.
+func (s *Service) DetachFromBid(ctx context.Context, bidID, attachmentID, username string) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "DetachFromBid")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := s.checkBidOpen(ctx, bidID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.DetachFromBid(ctx, bidID, attachmentID)
+}
+
+// GetBidAttachments lists the files of the current version of a bid to
+// both sides of its thread.
+func (s *Service) GetBidAttachments(ctx context.Context, bidID, username string) (_ []*models.Attachment, err error) {
+	ctx, span := startSpan(ctx, "GetBidAttachments")
+	defer func() { endSpan(span, err) }()
+
+	bid, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bidThreadRole(ctx, bid, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAttachmentsByIDs(ctx, bid.AttachmentIDs)
+}
+
+// OpenBidAttachment opens a file of any version of a bid for download. The
+// caller closes the reader.
+func (s *Service) OpenBidAttachment(ctx context.Context, bidID, attachmentID, username string) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := startSpan(ctx, "OpenBidAttachment")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, nil, repository.ErrAttachmentsDisabled
+	}
+
+	bid, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := s.bidThreadRole(ctx, bid, username); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.BidID == nil || *attachment.BidID != bidID {
+		return nil, nil, repository.ErrAttachmentNotFound
+	}
+
+	return s.openFile(ctx, attachment)
+}
+
+// storeFile checks the limits of an upload and streams it into a new blob,
+// hashing it on the way.
+func (s *Service) storeFile(ctx context.Context, upload *models.AttachmentUpload, body io.Reader) (*models.Attachment, error) {
+	if upload.Size > s.attachments.MaxSize {
+		return nil, repository.ErrAttachmentTooLarge
+	}
+
+	contentType, _, err := mime.ParseMediaType(upload.ContentType)
+	if err != nil || !slices.Contains(s.attachments.AllowedTypes, contentType) {
+		return nil, repository.ErrAttachmentType
+	}
+
+	key := uuid.NewString()
+	digest := sha256.New()
+	if err := s.store.Put(ctx, key, io.TeeReader(io.LimitReader(body, upload.Size), digest), upload.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	return &models.Attachment{
+		Name:        upload.Name,
+		ContentType: contentType,
+		Size:        upload.Size,
+		SHA256:      hex.EncodeToString(digest.Sum(nil)),
+		StorageKey:  key,
+	}, nil
+}
+
+// openFile only opens files that were scanned clean.
+func (s *Service) openFile(ctx context.Context, attachment *models.Attachment) (*models.Attachment, io.ReadCloser, error) {
+	switch attachment.ScanStatus {
+	case models.ScanStatusClean:
+	case models.ScanStatusQuarantined:
+		return nil, nil, repository.ErrAttachmentQuarantined
+	default:
+		return nil, nil, repository.ErrAttachmentBlocked
+	}
+
+	reader, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, repository.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, reader, nil
+}
+
+// scanBatch is how many quarantined attachments one run scans.
+const scanBatch = 20
+
+// ScanQuarantinedAttachments scans the oldest attachments in quarantine and
+// records the outcome. A file the scanner cannot be reached for stays in
+// quarantine until the next run.
+func (s *Service) ScanQuarantinedAttachments(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "ScanQuarantinedAttachments")
+	defer func() { endSpan(span, err) }()
+
+	attachments, err := s.repo.GetQuarantinedAttachments(ctx, scanBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		scan, err := s.scanFile(ctx, attachment)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.repo.RecordAttachmentScan(ctx, attachment.ID, scan); err != nil {
+			return err
+		}
+
+		entry := s.logger(ctx).WithFields(logrus.Fields{
+			"attachment_id": attachment.ID,
+			"tender_id":     attachment.TenderID,
+			"scan_status":   scan.Status,
+		})
+		if scan.Status == models.ScanStatusClean {
+			entry.Info("attachment released from quarantine")
+		} else {
+			entry.WithField("signature", scan.Signature).Warn("attachment kept in quarantine")
+		}
+	}
+
+	return nil
+}
+
+// RunAttachmentScanner scans quarantined attachments every interval until
+// ctx is canceled. beat is called after every successful run.
+func (s *Service) RunAttachmentScanner(ctx context.Context, interval time.Duration, beat func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.ScanQuarantinedAttachments(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.log.WithError(err).Warn("failed to scan quarantined attachments")
+		} else if err == nil {
+			beat()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanFile streams the contents of an attachment through the scanner. Files
+// that cannot ever be scanned fail the scan instead of returning an error.
+func (s *Service) scanFile(ctx context.Context, attachment *models.Attachment) (*models.AttachmentScan, error) {
+	reader, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return &models.AttachmentScan{Status: models.ScanStatusFailed, Signature: "the contents are missing"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	threat, err := s.scanner.Scan(ctx, reader)
+	switch {
+	case errors.Is(err, scanning.ErrRejected):
+		return &models.AttachmentScan{Status: models.ScanStatusFailed, Signature: err.Error()}, nil
+	case err != nil:
+		return nil, err
+	case threat != "":
+		return &models.AttachmentScan{Status: models.ScanStatusInfected, Signature: threat}, nil
+	default:
+		return &models.AttachmentScan{Status: models.ScanStatusClean}, nil
+	}
+}
+
+// deleteFile removes a blob nothing refers to. A failure only leaves an
+// orphan behind, so it is logged rather than returned.
+func (s *Service) deleteFile(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		s.logger(ctx).WithError(err).WithField("storage_key", key).Warn("failed to delete an unused file")
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SubmitBidPrice lowers the price of an auction bid. A published bid that
+// drops its price close to the end extends the auction.
+func (s *Service) SubmitBidPrice(ctx context.Context, bidID, username string, price float64) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "SubmitBidPrice")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
+		return nil, err
+	}
+
+	_, tender, err := s.checkBidOpen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Auction == nil {
+		return nil, repository.ErrNotAuction
+	}
+
+	if err := checkAuctionStarted(tender); err != nil {
+		return nil, err
+	}
+
+	bid, err := s.repo.SubmitAuctionPrice(ctx, bidID, price)
+	if err != nil {
+		return nil, err
+	}
+
+	if bid.Status == models.BidStatusPublished {
+		if err := s.extendAuction(ctx, tender.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bid, nil
+}
+
+// GetAuctionRanking shows the live prices of an auction to those who may
+// read the tender, marking the bids of the user.
+func (s *Service) GetAuctionRanking(ctx context.Context, tenderID, username string) (_ []*models.AuctionRank, err error) {
+	ctx, span := startSpan(ctx, "GetAuctionRanking")
+	defer func() { endSpan(span, err) }()
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.checkTenderReadable(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if tender.Auction == nil {
+		return nil, repository.ErrNotAuction
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAuctionRanking(ctx, tenderID, userID)
+}
+
+// CloseEndedAuctions awards the auctions that are past their end.
+func (s *Service) CloseEndedAuctions(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "CloseEndedAuctions")
+	defer func() { endSpan(span, err) }()
+
+	results, err := s.repo.CloseEndedAuctions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.BidID != "" {
+			s.metrics.TenderAwarded()
+		}
+
+		s.logger(ctx).WithFields(logrus.Fields{
+			"tender_id": result.TenderID,
+			"bid_id":    result.BidID,
+		}).Info("auction closed")
+	}
+
+	return nil
+}
+
+// RunAuctionCloser closes ended auctions every interval until ctx is
+// canceled. beat is called after every successful run.
+func (s *Service) RunAuctionCloser(ctx context.Context, interval time.Duration, beat func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.CloseEndedAuctions(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.log.WithError(err).Warn("failed to close ended auctions")
+		} else if err == nil {
+			beat()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) extendAuction(ctx context.Context, tenderID string) error {
+	extended, err := s.repo.ExtendAuction(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	if extended {
+		s.logger(ctx).WithField("tender_id", tenderID).Info("auction extended by a late price drop")
+	}
+
+	return nil
+}
+
+func validAuction(tender *models.TenderCreate) bool {
+	return tender.ServiceType == models.TenderServiceTypeDelivery && !tender.Sealed &&
+		tender.SubmissionDeadline != nil && tender.Auction.Start.Before(*tender.SubmissionDeadline)
+}
+
+func checkAuctionStarted(tender *models.TenderResponse) error {
+	if tender.Auction.Start.After(time.Now()) {
+		return repository.ErrAuctionNotStarted
+	}
+
+	return nil
+}
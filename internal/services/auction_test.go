@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// auction builds a published auction of the buyer that ends at the deadline.
+func (e *testEnv) auction(t *testing.T, deadline time.Time, step float64, extensionSeconds int32) *models.TenderResponse {
+	t.Helper()
+
+	return e.tender(t, func(c *models.TenderCreate) {
+		c.ServiceType, c.SubmissionDeadline = models.TenderServiceTypeDelivery, &deadline
+		c.Auction = &models.Auction{Start: time.Now().Add(-time.Minute), Step: step, ExtensionSeconds: extensionSeconds}
+	}, true)
+}
+
+// auctionBid makes a personal bid at the price and publishes it.
+func (e *testEnv) auctionBid(t *testing.T, tenderID, authorID, author string, price float64) *models.BidResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	bid, err := e.srv.ConstructBid(ctx, &models.BidCreate{
+		Name: "Offer", Description: "We deliver", TenderID: tenderID,
+		AuthorType: models.BidAuthorTypeUser, AuthorId: authorID, Price: &price,
+	})
+	noErr(t, err)
+
+	bid, err = e.srv.RenewStatusOfBid(ctx, bid.ID, author, &models.BidStatusPublished)
+	noErr(t, err)
+
+	return bid
+}
+
+func (e *testEnv) deadline(t *testing.T, tenderID string) time.Time {
+	t.Helper()
+
+	tender, err := e.srv.getTender(context.Background(), tenderID)
+	noErr(t, err)
+
+	return *tender.SubmissionDeadline
+}
+
+func TestSubmitBidPriceStep(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender := env.auction(t, time.Now().Add(time.Hour), 10, 0)
+	bid := env.auctionBid(t, tender.ID, bobID, bob, 100)
+
+	_, err := env.srv.SubmitBidPrice(ctx, bid.ID, bob, 95)
+	expectErr(t, err, repository.ErrPriceStepTooSmall)
+
+	_, err = env.srv.SubmitBidPrice(ctx, bid.ID, bob, 105)
+	expectErr(t, err, repository.ErrPriceStepTooSmall)
+
+	bid, err = env.srv.SubmitBidPrice(ctx, bid.ID, bob, 90)
+	noErr(t, err)
+	if *bid.Price != 90 || bid.Version != 2 {
+		t.Fatalf("a price drop by the full step must stick as a new version, got %+v", bid)
+	}
+
+	_, err = env.srv.SubmitBidPrice(ctx, bid.ID, bob, 80.5)
+	expectErr(t, err, repository.ErrPriceStepTooSmall)
+
+	_, err = env.srv.SubmitBidPrice(ctx, bid.ID, bella, 70)
+	expectErr(t, err, repository.ErrRelationNotExist)
+
+	plain := env.tender(t, nil, true)
+	offer := env.publishedBid(t, plain.ID, bobID, bob)
+	_, err = env.srv.SubmitBidPrice(ctx, offer.ID, bob, 70)
+	expectErr(t, err, repository.ErrNotAuction)
+}
+
+func TestLatePriceDropExtendsAuction(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	const extension = 120 * time.Second
+
+	early := env.auction(t, time.Now().Add(time.Hour), 1, int32(extension.Seconds()))
+	bid := env.auctionBid(t, early.ID, bobID, bob, 100)
+	before := env.deadline(t, early.ID)
+	_, err := env.srv.SubmitBidPrice(ctx, bid.ID, bob, 90)
+	noErr(t, err)
+	if got := env.deadline(t, early.ID); !got.Equal(before) {
+		t.Fatalf("an early price drop must not move the deadline, got %v, want %v", got, before)
+	}
+
+	late := env.auction(t, time.Now().Add(30*time.Second), 1, int32(extension.Seconds()))
+	draft, err := env.srv.ConstructBid(ctx, &models.BidCreate{
+		Name: "Offer", Description: "We deliver", TenderID: late.ID,
+		AuthorType: models.BidAuthorTypeUser, AuthorId: bellaID, Price: ptr(100.0),
+	})
+	noErr(t, err)
+	before = env.deadline(t, late.ID)
+	_, err = env.srv.SubmitBidPrice(ctx, draft.ID, bella, 90)
+	noErr(t, err)
+	if got := env.deadline(t, late.ID); !got.Equal(before) {
+		t.Fatalf("a drop of an unpublished bid must not move the deadline, got %v, want %v", got, before)
+	}
+
+	bid = env.auctionBid(t, late.ID, bobID, bob, 100)
+	droppedAt := time.Now()
+	_, err = env.srv.SubmitBidPrice(ctx, bid.ID, bob, 90)
+	noErr(t, err)
+	got := env.deadline(t, late.ID)
+	if got.Before(droppedAt.Add(extension)) || got.After(time.Now().Add(extension)) {
+		t.Fatalf("a late price drop must move the deadline %v after it, got %v", extension, got)
+	}
+}
+
+func TestCloseEndedAuctions(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	tender := env.auction(t, deadline, 5, 0)
+	empty := env.auction(t, deadline, 5, 0)
+	running := env.auction(t, time.Now().Add(time.Hour), 5, 0)
+
+	// Both end at the same price, bob got there first although bella bid
+	// first.
+	first := env.auctionBid(t, tender.ID, bellaID, bella, 100)
+	second := env.auctionBid(t, tender.ID, bobID, bob, 100)
+	_, err := env.srv.SubmitBidPrice(ctx, second.ID, bob, 90)
+	noErr(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = env.srv.SubmitBidPrice(ctx, first.ID, bella, 90)
+	noErr(t, err)
+	env.auctionBid(t, running.ID, bobID, bob, 100)
+
+	time.Sleep(time.Until(deadline))
+	noErr(t, env.srv.CloseEndedAuctions(ctx))
+
+	winner, err := env.repo.GetBidsWithID(ctx, second.ID)
+	noErr(t, err)
+	if winner.Status != models.BidStatusApproved {
+		t.Fatalf("the earliest of the lowest prices must win, got %s", winner.Status)
+	}
+	loser, err := env.repo.GetBidsWithID(ctx, first.ID)
+	noErr(t, err)
+	if loser.Status != models.BidStatusPublished {
+		t.Fatalf("the later bid must not be awarded, got %s", loser.Status)
+	}
+
+	for id, want := range map[string]models.TenderStatus{
+		tender.ID:  models.TenderStatusClosed,
+		empty.ID:   models.TenderStatusClosed,
+		running.ID: models.TenderStatusPublished,
+	} {
+		if status := env.tenderStatus(t, id); status != want {
+			t.Fatalf("tender %s: got %s, want %s", id, status, want)
+		}
+	}
+	if env.metrics.awarded != 1 {
+		t.Fatalf("one award expected, got %d", env.metrics.awarded)
+	}
+
+	noErr(t, env.srv.CloseEndedAuctions(ctx))
+	if env.metrics.awarded != 1 {
+		t.Fatalf("a closed auction must not be awarded again, got %d", env.metrics.awarded)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 
+	"github.com/DarRo9/Tenders/internal/config"
 	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
-func (s *Service) ConstructBid(ctx context.Context, bid *models.BidCreate) (*models.BidResponse, error) {
+func (s *Service) ConstructBid(ctx context.Context, bid *models.BidCreate) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "ConstructBid")
+	defer func() { endSpan(span, err) }()
 
 	if err := s.repo.IsTenderPudlished(ctx, bid.TenderID); err != nil {
 		return nil, err
@@ -19,11 +24,77 @@ func (s *Service) ConstructBid(ctx context.Context, bid *models.BidCreate) (*mod
 		return nil, err
 	}
 
-	return s.repo.ConstructBid(ctx, bid)
+	if err := s.repo.ControlTenderVisibility(ctx, bid.TenderID, bid.AuthorId); err != nil {
+		return nil, err
+	}
+
+	if bid.AuthorType == models.BidAuthorTypeOrganization {
+		if err := s.checkOrganizationBid(ctx, bid); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkResubmission(ctx, bid); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, bid.TenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+
+	if tender.Visibility == models.TenderVisibilityInvitation && bid.AuthorType == models.BidAuthorTypeOrganization {
+		if err := s.checkInvited(ctx, tender.ID, *bid.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkDebarment(ctx, tender.ID, bid); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPrequalification(ctx, tender, bid); err != nil {
+		return nil, err
+	}
+
+	if tender.Auction != nil {
+		if bid.Price == nil {
+			return nil, repository.ErrPriceRequired
+		}
+
+		if err := checkAuctionStarted(tender); err != nil {
+			return nil, err
+		}
+	}
+
+	// A sealed bid gets its ID here, the ID is bound into the ciphertext.
+	var sealed *models.SealedContent
+	if tender.Sealed {
+		sealed = &models.SealedContent{BidID: uuid.NewString(), Version: 1}
+		sealed.Content, err = s.sealBid(ctx, tender.ID, sealed.BidID, sealed.Version, &sealedBid{Name: bid.Name, Description: bid.Description})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bidResp, err := s.repo.ConstructBid(ctx, bid, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.BidCreated()
+
+	return bidResp, nil
 }
 
 
-func (s *Service) GetBidsOfUser(ctx context.Context, username string, limit, offset int32) ([]*models.BidResponse, error) {
+func (s *Service) GetBidsOfUser(ctx context.Context, username string, limit, offset int32) (_ []*models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "GetBidsOfUser")
+	defer func() { endSpan(span, err) }()
 
 	userId, err := s.repo.ControlBidCreationByID(ctx, username)
 	if err != nil {
@@ -33,15 +104,30 @@ func (s *Service) GetBidsOfUser(ctx context.Context, username string, limit, off
 	return s.repo.GetBidsOfUser(ctx, userId, limit, offset)
 }
 
-func (s *Service) CancelChangesOfBid(ctx context.Context, bidID, username string, version int32) (*models.BidResponse, error) {
+func (s *Service) CancelChangesOfBid(ctx context.Context, bidID, username string, version int32) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "CancelChangesOfBid")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
 		return nil, err
 	}
 
+	_, tender, err := s.checkBidOpen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Auction != nil {
+		return nil, repository.ErrAuctionRollback
+	}
+
 	return s.repo.CancelChangesOfBid(ctx, bidID, version)
 }
 
-func (s *Service) GetCommentsOfBid(ctx context.Context, tenderID, authorUsername, requesterUsername string, limit, offset int32) ([]*models.BidReviewResponse, error) {
+func (s *Service) GetCommentsOfBid(ctx context.Context, tenderID, authorUsername, requesterUsername string, limit, offset int32) (_ []*models.BidReviewResponse, err error) {
+	ctx, span := startSpan(ctx, "GetCommentsOfBid")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, requesterUsername); err != nil {
 		return nil, err
 	}
@@ -49,7 +135,9 @@ func (s *Service) GetCommentsOfBid(ctx context.Context, tenderID, authorUsername
 	return s.repo.GetCommentsOfBid(ctx, tenderID, authorUsername, limit, offset)
 }
 
-func (s *Service) GetBidsOfTender(ctx context.Context, tenderID, username string, limit, offset int32) ([]*models.BidResponse, error) {
+func (s *Service) GetBidsOfTender(ctx context.Context, tenderID, username string, limit, offset int32) (_ []*models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "GetBidsOfTender")
+	defer func() { endSpan(span, err) }()
 
 	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
 		return nil, err
@@ -58,7 +146,10 @@ func (s *Service) GetBidsOfTender(ctx context.Context, tenderID, username string
 }
 
 
-func (s *Service) GetStatusOfBids(ctx context.Context, bidID string, username string) (*models.BidStatus, error) {
+func (s *Service) GetStatusOfBids(ctx context.Context, bidID string, username string) (_ *models.BidStatus, err error) {
+	ctx, span := startSpan(ctx, "GetStatusOfBids")
+	defer func() { endSpan(span, err) }()
+
 	bid, err := s.repo.GetBidsWithID(ctx, bidID)
 	if err != nil {
 		return nil, err
@@ -84,32 +175,101 @@ func (s *Service) GetStatusOfBids(ctx context.Context, bidID string, username st
 	return &bid.Status, nil
 }
 
-func (s *Service) RenewStatusOfBid(ctx context.Context, bidID, username string, status *models.BidStatus) (*models.BidResponse, error) {
+func (s *Service) RenewStatusOfBid(ctx context.Context, bidID, username string, status *models.BidStatus) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "RenewStatusOfBid")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
 		return nil, err
 	}
 
-	return s.repo.RenewStatusOfBid(ctx, bidID, username, status)
+	_, tender, err := s.checkBidOpen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.repo.RenewStatusOfBid(ctx, bidID, username, status)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Auction != nil && bid.Status == models.BidStatusPublished && bid.Price != nil {
+		if err := s.extendAuction(ctx, tender.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bid, nil
 }
 
-func (s *Service) ChangeBid(ctx context.Context, bidID, username string, bid *models.BidEdit) (*models.BidResponse, error) {
+func (s *Service) ChangeBid(ctx context.Context, bidID, username string, bid *models.BidEdit) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "ChangeBid")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
 		return nil, err
 	}
 
-	return s.repo.ChangeBid(ctx, bidID, bid)
+	current, tender, err := s.checkBidOpen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !current.Sealed {
+		return s.repo.ChangeBid(ctx, bidID, bid)
+	}
+
+	sealedContent, err := s.resealBid(ctx, current, tender.ID, bid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ChangeSealedBid(ctx, bidID, sealedContent)
 }
 
-func (s *Service) ApplyBidDecision(ctx context.Context, bidID, username string, decision *models.BidDecision) (*models.BidResponse, error) {
+func (s *Service) ApplyBidDecision(ctx context.Context, bidID, username string, decision *models.BidDecision) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "ApplyBidDecision")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlUserResponsibilityForTenderByBidID(ctx, bidID, username); err != nil {
 		return nil, err
 	}
 
+	current, tender, err := s.checkBidNotFrozen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotSealed(tender); err != nil {
+		return nil, err
+	}
+
+	if tender.Auction != nil {
+		return nil, repository.ErrAuctionDecision
+	}
+
+	if *decision == models.BidDecisionApproved {
+		if err := s.checkWinnerNotDebarred(ctx, current); err != nil {
+			return nil, err
+		}
+
+		criteria, err := s.repo.GetCriteriaByTenderIDs(ctx, []string{tender.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(criteria) != 0 {
+			return nil, repository.ErrCriteriaDecision
+		}
+	}
+
 	bid, err := s.repo.ApplyBidDecision(ctx, bidID, username, decision)
 	if err != nil {
 		return nil, err
 	}
 
+	s.metrics.DecisionApplied(*decision)
+
 	if *decision == models.BidDecisionRejected {
 		return s.repo.RenewStatusOfBid(ctx, bidID, username, &models.BidStatusCanceled)
 	}
@@ -121,32 +281,169 @@ func (s *Service) ApplyBidDecision(ctx context.Context, bidID, username string,
 
 	approvedCount, err := s.repo.CountApplyedDecisions(ctx, bidID)
 	if err != nil {
-		s.log.Info(err)
 		return nil, err
 	}
 
 	if approvedCount >= quorum {
 		bid, err := s.repo.RenewStatusOfBid(ctx, bidID, username, &models.BidStatusApproved)
 		if err != nil {
-			s.log.Info(err)
 			return nil, err
 		}
-		_, err = s.repo.RefreshTenderStatus(ctx, bid.TenderID, models.TenderStatusClosed)
-		s.log.Info(err)
-		return bid, err
 
+		if _, err := s.repo.RefreshTenderStatus(ctx, bid.TenderID, models.TenderStatusClosed); err != nil {
+			return nil, err
+		}
+
+		s.metrics.TenderAwarded()
+
+		s.logger(ctx).WithFields(logrus.Fields{
+			"bid_id":    bid.ID,
+			"tender_id": bid.TenderID,
+		}).Info("bid approved by quorum, tender closed")
+
+		return bid, nil
 	}
 
 	return bid, nil
 }
 
+// checkBidNotFrozen freezes withdrawn bids and the bids of a cancelled
+// tender, so that neither the author nor the buyer can revive one of them.
+func (s *Service) checkBidNotFrozen(ctx context.Context, bidID string) (*models.BidResponse, *models.TenderResponse, error) {
+	bid, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bid.Status == models.BidStatusWithdrawn {
+		return nil, nil, repository.ErrBidWithdrawn
+	}
+
+	tender, err := s.getTender(ctx, bid.TenderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tender.Status == models.TenderStatusCancelled {
+		return nil, nil, repository.ErrTenderCancelled
+	}
+
+	return bid, tender, nil
+}
+
+// checkBidOpen is checkBidNotFrozen for the author, who can only change a
+// bid until the submission deadline.
+func (s *Service) checkBidOpen(ctx context.Context, bidID string) (*models.BidResponse, *models.TenderResponse, error) {
+	bid, tender, err := s.checkBidNotFrozen(ctx, bidID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkSubmissionOpen(tender); err != nil {
+		return nil, nil, err
+	}
+
+	return bid, tender, nil
+}
+
+// checkOrganizationBid makes sure the author speaks for the organization and
+// the organization does not bid on its own tender.
+func (s *Service) checkOrganizationBid(ctx context.Context, bid *models.BidCreate) error {
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, bid.OrganizationID, bid.AuthorId); err != nil {
+		return err
+	}
+
+	_, tenderOrganizationID, err := s.repo.GetStatusOfTender(ctx, bid.TenderID)
+	if err != nil {
+		return err
+	}
+
+	if *tenderOrganizationID == *bid.OrganizationID {
+		return repository.ErrBidOwnTender
+	}
+
+	return nil
+}
+
+// checkResubmission applies the resubmission policy to the earlier bids of
+// the author, or of the organization, on the tender. A live bid always
+// blocks a new one.
+func (s *Service) checkResubmission(ctx context.Context, bid *models.BidCreate) error {
+	statuses, err := s.repo.GetBidStatusesOfAuthor(ctx, bid.TenderID, bid.AuthorId, bid.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		switch status {
+		case models.BidStatusWithdrawn:
+			if s.bids.Resubmission == config.ResubmissionNever {
+				return repository.ErrBidResubmissionForbidden
+			}
+		case models.BidStatusCanceled, models.BidStatusRejected, models.BidStatusTenderCancelled:
+			if s.bids.Resubmission != config.ResubmissionAlways {
+				return repository.ErrBidResubmissionForbidden
+			}
+		default:
+			return repository.ErrBidUnique
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) WithdrawBid(ctx context.Context, bidID, username, reason string) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "WithdrawBid")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlBidCreationByName(ctx, bidID, username); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, current.TenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+
+	bid, err := s.repo.WithdrawBid(ctx, bidID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.BidWithdrawn()
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"bid_id":    bid.ID,
+		"tender_id": bid.TenderID,
+	}).Info("bid withdrawn by its author")
+
+	return bid, nil
+}
+
 func (s *Service) getQuorum(ctx context.Context, bidID string) (int, error) {
 	count, err := s.repo.CountOrganizationsByBid(ctx, bidID)
 	return min(3, count), err
 }
 
 
-func (s *Service) ApplyBidFeedback(ctx context.Context, bidID, username string, feedback *models.BidFeedback) (*models.BidResponse, error) {
+func (s *Service) ApplyBidFeedback(ctx context.Context, bidID, username string, feedback *models.BidFeedback) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "ApplyBidFeedback")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
 	bid, err := s.repo.GetBidsWithID(ctx, bidID)
 	if err != nil {
 		return nil, err
@@ -156,6 +453,18 @@ func (s *Service) ApplyBidFeedback(ctx context.Context, bidID, username string,
 		return nil, err
 	}
 
-	return bid, s.repo.ApplyBidFeedback(ctx, bidID, feedback)
+	tender, err := s.getTender(ctx, bid.TenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotSealed(tender); err != nil {
+		return nil, err
+	}
+
+	// Feedback is a buyer message in the thread of the bid.
+	_, err = s.repo.PostBidMessage(ctx, bidID, userID, models.MessageRoleBuyer, &models.BidMessageCreate{Body: string(*feedback)})
+
+	return bid, err
 }
 
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func TestApplyBidDecisionQuorum(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	bid := env.publishedBid(t, tender.ID, bobID, bob)
+
+	// The buyer has three responsibles, each of them has to approve.
+	for _, username := range []string{alice, anna} {
+		if got := env.decide(t, bid.ID, username, models.BidDecisionApproved); got.Status != models.BidStatusPublished {
+			t.Fatalf("%s: the bid must wait for the quorum, got %s", username, got.Status)
+		}
+		if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusPublished {
+			t.Fatalf("%s: the tender must stay published, got %s", username, status)
+		}
+	}
+
+	_, err := env.srv.ApplyBidDecision(ctx, bid.ID, alice, ptr(models.BidDecisionApproved))
+	expectErr(t, err, repository.ErrBidDecisionUnique)
+
+	_, err = env.srv.ApplyBidDecision(ctx, bid.ID, bob, ptr(models.BidDecisionApproved))
+	expectErr(t, err, repository.ErrRelationNotExist)
+
+	if got := env.decide(t, bid.ID, amy, models.BidDecisionApproved); got.Status != models.BidStatusApproved {
+		t.Fatalf("the bid approved by the quorum expected, got %s", got.Status)
+	}
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusClosed {
+		t.Fatalf("the tender must close with the approval, got %s", status)
+	}
+	if env.metrics.awarded != 1 || env.metrics.decisions[models.BidDecisionApproved] != 3 {
+		t.Fatalf("one award after three approvals expected, got %+v", env.metrics)
+	}
+}
+
+func TestApplyBidDecisionSmallQuorum(t *testing.T) {
+	env := newTestEnv(t)
+
+	// The supplier has two responsibles only, so two approvals suffice.
+	tender := env.tender(t, func(c *models.TenderCreate) {
+		c.OrganizationID, c.CreatorUsername = supplierOrg, bob
+	}, false)
+	_, err := env.srv.RefreshTenderStatus(context.Background(), tender.ID, bob, models.TenderStatusPublished)
+	noErr(t, err)
+
+	bid := env.publishedBid(t, tender.ID, aliceID, alice)
+	if got := env.decide(t, bid.ID, bob, models.BidDecisionApproved); got.Status != models.BidStatusPublished {
+		t.Fatalf("the bid must wait for the second approval, got %s", got.Status)
+	}
+	if got := env.decide(t, bid.ID, bella, models.BidDecisionApproved); got.Status != models.BidStatusApproved {
+		t.Fatalf("the bid approved by both responsibles expected, got %s", got.Status)
+	}
+}
+
+func TestApplyBidDecisionRejection(t *testing.T) {
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	bid := env.publishedBid(t, tender.ID, bobID, bob)
+
+	env.decide(t, bid.ID, alice, models.BidDecisionApproved)
+	if got := env.decide(t, bid.ID, anna, models.BidDecisionRejected); got.Status != models.BidStatusCanceled {
+		t.Fatalf("a single rejection must cancel the bid, got %s", got.Status)
+	}
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusPublished {
+		t.Fatalf("the tender must stay open for other bids, got %s", status)
+	}
+	if env.metrics.awarded != 0 {
+		t.Fatalf("no award expected, got %+v", env.metrics)
+	}
+}
+
+// TestConstructBidCheckOrder breaks two rules at a time, the earlier check
+// has to win.
+func TestConstructBidCheckOrder(t *testing.T) {
+	env := newTestEnv(t)
+
+	draft := env.tender(t, nil, false)
+	published := env.tender(t, nil, true)
+	internal := env.tender(t, func(c *models.TenderCreate) { c.Visibility = models.TenderVisibilityInternal }, true)
+	auction := env.tender(t, func(c *models.TenderCreate) {
+		c.ServiceType = models.TenderServiceTypeDelivery
+		c.SubmissionDeadline = ptr(time.Now().Add(time.Hour))
+		c.Auction = &models.Auction{Start: time.Now().Add(-time.Minute), Step: 1}
+	}, true)
+
+	_, err := env.bid(published.ID, bellaID, nil)
+	noErr(t, err)
+
+	tests := []struct {
+		name           string
+		tenderID       string
+		authorID       string
+		organizationID *models.OrganizationID
+		want           error
+	}{
+		{"draft tender before unknown author", draft.ID, nobody, nil, repository.ErrTenderClosed},
+		{"missing tender before unknown author", nobody, nobody, nil, repository.ErrTenderNotFound},
+		{"unknown author", published.ID, nobody, nil, repository.ErrUserNotExist},
+		{"author without organization", published.ID, carlID, nil, repository.ErrRelationNotExist},
+		{"invisible tender", internal.ID, bobID, ptr(supplierOrg), repository.ErrRelationNotExist},
+		{"foreign organization", published.ID, bobID, ptr(buyerOrg), repository.ErrRelationNotExist},
+		{"own tender", published.ID, aliceID, ptr(buyerOrg), repository.ErrBidOwnTender},
+		{"live bid of the author", published.ID, bellaID, nil, repository.ErrBidUnique},
+		{"auction bid without price", auction.ID, bobID, nil, repository.ErrPriceRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.bid(tt.tenderID, tt.authorID, tt.organizationID)
+			expectErr(t, err, tt.want)
+		})
+	}
+
+	if env.metrics.created != 1 {
+		t.Fatalf("only the first bid must be created, got %d", env.metrics.created)
+	}
+}
+
+func TestRenewStatusOfBidAfterFinalDecision(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	rejected := env.publishedBid(t, tender.ID, bellaID, bella)
+	env.decide(t, rejected.ID, alice, models.BidDecisionRejected)
+
+	_, err := env.srv.RenewStatusOfBid(ctx, rejected.ID, bella, &models.BidStatusPublished)
+	expectErr(t, err, repository.ErrBidStatusFinal)
+
+	approved := env.publishedBid(t, tender.ID, bobID, bob)
+	for _, username := range []string{alice, anna, amy} {
+		env.decide(t, approved.ID, username, models.BidDecisionApproved)
+	}
+
+	_, err = env.srv.RenewStatusOfBid(ctx, approved.ID, bob, &models.BidStatusCreated)
+	expectErr(t, err, repository.ErrBidStatusFinal)
+
+	bid, err := env.repo.GetBidsWithID(ctx, approved.ID)
+	noErr(t, err)
+	if bid.Status != models.BidStatusApproved {
+		t.Fatalf("the approval must stand, got %s", bid.Status)
+	}
+}
+
+func TestResubmissionPolicies(t *testing.T) {
+	tests := []struct {
+		policy         string
+		afterWithdrawn error
+		afterRejected  error
+	}{
+		{config.ResubmissionNever, repository.ErrBidResubmissionForbidden, repository.ErrBidResubmissionForbidden},
+		{config.ResubmissionAfterWithdrawal, nil, repository.ErrBidResubmissionForbidden},
+		{config.ResubmissionAlways, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t, func(s *Service) { s.bids.Resubmission = tt.policy })
+			tender := env.tender(t, nil, true)
+
+			withdrawn := env.publishedBid(t, tender.ID, bobID, bob)
+			_, err := env.srv.WithdrawBid(ctx, withdrawn.ID, bob, "Wrong price")
+			noErr(t, err)
+			_, err = env.bid(tender.ID, bobID, nil)
+			expectErr(t, err, tt.afterWithdrawn)
+
+			rejected := env.publishedBid(t, tender.ID, bellaID, bella)
+			env.decide(t, rejected.ID, alice, models.BidDecisionRejected)
+			_, err = env.bid(tender.ID, bellaID, nil)
+			expectErr(t, err, tt.afterRejected)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// DebarSupplier blocks a supplier from bidding. An organization debars
+// suppliers from its own tenders, a platform admin from all of them.
+func (s *Service) DebarSupplier(ctx context.Context, username string, create *models.DebarmentCreate) (_ *models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "DebarSupplier")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.controlDebarmentScope(ctx, username, create.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if create.OrganizationID != nil && *create.OrganizationID == create.SupplierID {
+		return nil, repository.ErrSelfDebarment
+	}
+
+	validFrom := time.Now()
+	if create.ValidFrom != nil {
+		validFrom = *create.ValidFrom
+	}
+
+	if err := checkDebarmentPeriod(validFrom, create.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	debarment, err := s.repo.CreateDebarment(ctx, userID, create,
+		fmt.Sprintf("supplier %s debarred (%s) %s: %s",
+			create.SupplierID, create.Scope, describePeriod(validFrom, create.ValidUntil), create.Reason))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"debarment_id": debarment.ID,
+		"supplier_id":  debarment.SupplierID,
+		"scope":        debarment.Scope,
+	}).Info("supplier debarred")
+
+	return debarment, nil
+}
+
+// GetDebarment shows a debarment to those who manage it and to the
+// responsibles of the debarred supplier, who should know why they cannot
+// bid.
+func (s *Service) GetDebarment(ctx context.Context, debarmentID, username string) (_ *models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "GetDebarment")
+	defer func() { endSpan(span, err) }()
+
+	debarment, err := s.repo.GetDebarment(ctx, debarmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.controlDebarmentScope(ctx, username, debarment.OrganizationID)
+	switch {
+	case err == nil:
+		return debarment, nil
+	case !errors.Is(err, repository.ErrRelationNotExist) && !errors.Is(err, repository.ErrPlatformAdminRequired):
+		return nil, err
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, &debarment.SupplierID, userID); err != nil {
+		return nil, err
+	}
+
+	return debarment, nil
+}
+
+// GetDebarments lists the debarments that apply to the tenders of an
+// organization, the global ones included, the latest first. Without an
+// organization a platform admin gets all of them.
+func (s *Service) GetDebarments(ctx context.Context, username string, organizationID *models.OrganizationID, limit, offset int32) (_ []*models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "GetDebarments")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.controlDebarmentScope(ctx, username, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDebarments(ctx, organizationID, limit, offset)
+}
+
+// GetMyDebarments lists the debarments the organizations of the user are
+// under now or will be.
+func (s *Service) GetMyDebarments(ctx context.Context, username string, limit, offset int32) (_ []*models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "GetMyDebarments")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDebarmentsOfUser(ctx, userID, limit, offset)
+}
+
+// ChangeDebarment corrects the reason or moves the end of a debarment. A
+// debarment that is lifted stays as it was.
+func (s *Service) ChangeDebarment(ctx context.Context, debarmentID, username string, edit *models.DebarmentEdit) (_ *models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "ChangeDebarment")
+	defer func() { endSpan(span, err) }()
+
+	debarment, userID, err := s.controlDebarmentOwner(ctx, debarmentID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []string{}
+	if edit.ValidUntil != nil {
+		if err := checkDebarmentPeriod(debarment.ValidFrom, edit.ValidUntil); err != nil {
+			return nil, err
+		}
+		changes = append(changes, "valid until "+edit.ValidUntil.UTC().Format(time.RFC3339))
+	}
+	if edit.Reason != nil {
+		changes = append(changes, "reason: "+*edit.Reason)
+	}
+
+	if len(changes) == 0 {
+		return debarment, nil
+	}
+
+	debarment, err = s.repo.UpdateDebarment(ctx, debarmentID, userID, edit, strings.Join(changes, ", "))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"debarment_id": debarmentID,
+	}).Info("debarment changed")
+
+	return debarment, nil
+}
+
+// LiftDebarment ends a debarment early, for instance after an appeal.
+func (s *Service) LiftDebarment(ctx context.Context, debarmentID, username, reason string) (_ *models.Debarment, err error) {
+	ctx, span := startSpan(ctx, "LiftDebarment")
+	defer func() { endSpan(span, err) }()
+
+	_, userID, err := s.controlDebarmentOwner(ctx, debarmentID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	debarment, err := s.repo.LiftDebarment(ctx, debarmentID, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"debarment_id": debarmentID,
+		"supplier_id":  debarment.SupplierID,
+	}).Info("debarment lifted")
+
+	return debarment, nil
+}
+
+// GetDebarmentAudit lists the changes of a debarment, the oldest first.
+func (s *Service) GetDebarmentAudit(ctx context.Context, debarmentID, username string, limit, offset int32) (_ []*models.AuditRecord, err error) {
+	ctx, span := startSpan(ctx, "GetDebarmentAudit")
+	defer func() { endSpan(span, err) }()
+
+	if _, _, err := s.controlDebarmentOwner(ctx, debarmentID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDebarmentAudit(ctx, debarmentID, limit, offset)
+}
+
+// controlDebarmentScope returns the user id of a responsible of the
+// organization, or of a platform admin when there is no organization.
+func (s *Service) controlDebarmentScope(ctx context.Context, username string, organizationID *models.OrganizationID) (string, error) {
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	if organizationID == nil {
+		if !slices.Contains(s.platform.Admins, username) {
+			return "", repository.ErrPlatformAdminRequired
+		}
+		return userID, nil
+	}
+
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, organizationID, userID); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+func (s *Service) controlDebarmentOwner(ctx context.Context, debarmentID, username string) (*models.Debarment, string, error) {
+	debarment, err := s.repo.GetDebarment(ctx, debarmentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	userID, err := s.controlDebarmentScope(ctx, username, debarment.OrganizationID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return debarment, userID, nil
+}
+
+// checkDebarment keeps a debarred supplier from bidding on a tender. An
+// organization bid is checked against that organization, a personal one
+// against every organization of the author.
+func (s *Service) checkDebarment(ctx context.Context, tenderID string, bid *models.BidCreate) error {
+	var organizationID *models.OrganizationID
+	if bid.AuthorType == models.BidAuthorTypeOrganization {
+		organizationID = bid.OrganizationID
+	}
+
+	return s.repo.ControlDebarment(ctx, tenderID, bid.AuthorId, organizationID)
+}
+
+// checkWinnerNotDebarred keeps a bid made before its supplier was debarred
+// from winning. A debarred supplier never wins: the rankings of AwardTender
+// and of the auction close pass its bids over, while approving one of them
+// by hand fails.
+func (s *Service) checkWinnerNotDebarred(ctx context.Context, bid *models.BidResponse) error {
+	return s.repo.ControlDebarment(ctx, bid.TenderID, bid.AuthorID, bid.OrganizationID)
+}
+
+// withoutDebarred drops the bids of debarred suppliers from a ranking. The
+// ranks of the bids left stay as they were.
+func (s *Service) withoutDebarred(ctx context.Context, tenderID string, ranking []*models.BidEvaluation) ([]*models.BidEvaluation, error) {
+	bids, err := s.repo.GetBidsByTenderIDs(ctx, []string{tenderID})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.BidResponse, len(bids))
+	for _, bid := range bids {
+		byID[bid.ID] = bid
+	}
+
+	eligible := make([]*models.BidEvaluation, 0, len(ranking))
+	for _, ranked := range ranking {
+		err := s.checkWinnerNotDebarred(ctx, byID[ranked.BidID])
+		switch {
+		case errors.Is(err, repository.ErrSupplierDebarred):
+			s.logger(ctx).WithFields(logrus.Fields{
+				"bid_id":    ranked.BidID,
+				"tender_id": tenderID,
+			}).Info("bid of a debarred supplier passed over")
+		case err != nil:
+			return nil, err
+		default:
+			eligible = append(eligible, ranked)
+		}
+	}
+
+	return eligible, nil
+}
+
+func checkDebarmentPeriod(validFrom time.Time, validUntil *time.Time) error {
+	if validUntil != nil && (!validUntil.After(validFrom) || !validUntil.After(time.Now())) {
+		return repository.ErrInvalidDebarmentPeriod
+	}
+
+	return nil
+}
+
+func describePeriod(validFrom time.Time, validUntil *time.Time) string {
+	period := "from " + validFrom.UTC().Format(time.RFC3339)
+	if validUntil == nil {
+		return period + " indefinitely"
+	}
+
+	return period + " until " + validUntil.UTC().Format(time.RFC3339)
+}
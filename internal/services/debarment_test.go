@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// debar has alice debar the supplier from the tenders of the buyer.
+func (e *testEnv) debar(t *testing.T, supplierID models.OrganizationID) *models.Debarment {
+	t.Helper()
+
+	debarment, err := e.srv.DebarSupplier(context.Background(), alice, &models.DebarmentCreate{
+		SupplierID: supplierID, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(buyerOrg),
+		Reason: "Missed the delivery",
+	})
+	noErr(t, err)
+
+	return debarment
+}
+
+func TestConstructBidDebarment(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t, func(s *Service) { s.platform.Admins = []string{carl} })
+
+	buyers := env.tender(t, nil, true)
+	thirds, err := env.srv.BuildTender(ctx, &models.TenderCreate{
+		Name: "Warehouse", Description: "Warehouse", ServiceType: models.TenderServiceTypeConstruction,
+		OrganizationID: thirdOrg, CreatorUsername: olga,
+	})
+	noErr(t, err)
+	_, err = env.srv.RefreshTenderStatus(ctx, thirds.ID, olga, models.TenderStatusPublished)
+	noErr(t, err)
+	debarment := env.debar(t, supplierOrg)
+
+	tests := []struct {
+		name           string
+		tenderID       string
+		authorID       string
+		organizationID *models.OrganizationID
+		want           error
+	}{
+		{"the debarred organization", buyers.ID, bobID, ptr(supplierOrg), repository.ErrSupplierDebarred},
+		{"a responsible of the debarred organization", buyers.ID, bobID, nil, repository.ErrSupplierDebarred},
+		// bella is a responsible of the debarred supplier as well.
+		{"a personal bid of a responsible of both", buyers.ID, bellaID, nil, repository.ErrSupplierDebarred},
+		{"another organization of that responsible", buyers.ID, bellaID, ptr(thirdOrg), nil},
+		{"an outsider", buyers.ID, olgaID, nil, nil},
+		{"the tender of another organization", thirds.ID, bobID, ptr(supplierOrg), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.bid(tt.tenderID, tt.authorID, tt.organizationID)
+			expectErr(t, err, tt.want)
+		})
+	}
+
+	t.Run("global", func(t *testing.T) {
+		_, err := env.srv.DebarSupplier(ctx, carl, &models.DebarmentCreate{
+			SupplierID: thirdOrg, Scope: models.DebarmentScopeGlobal, Reason: "Fraud",
+		})
+		noErr(t, err)
+
+		_, err = env.bid(env.tender(t, nil, true).ID, olgaID, ptr(thirdOrg))
+		expectErr(t, err, repository.ErrSupplierDebarred)
+	})
+
+	t.Run("not yet in force", func(t *testing.T) {
+		validFrom := time.Now().Add(time.Hour)
+		_, err := env.srv.DebarSupplier(ctx, olga, &models.DebarmentCreate{
+			SupplierID: supplierOrg, Scope: models.DebarmentScopeOrganization, OrganizationID: ptr(thirdOrg),
+			Reason: "Missed the delivery", ValidFrom: &validFrom,
+		})
+		noErr(t, err)
+
+		_, err = env.bid(thirds.ID, bobID, nil)
+		noErr(t, err)
+	})
+
+	t.Run("lifted", func(t *testing.T) {
+		_, err := env.srv.LiftDebarment(ctx, debarment.ID, alice, "Appeal granted")
+		noErr(t, err)
+
+		_, err = env.bid(buyers.ID, bobID, ptr(supplierOrg))
+		noErr(t, err)
+	})
+}
+
+func TestAwardTenderPassesOverDebarred(t *testing.T) {
+	env := newTestEnv(t)
+
+	tender, criteria := env.scoredTender(t)
+	best := env.publishedBid(t, tender.ID, bobID, bob)
+	other := env.publishedBid(t, tender.ID, olgaID, olga)
+	for _, username := range []string{alice, anna, amy} {
+		env.score(t, best.ID, username, criteria, 9, 9)
+		env.score(t, other.ID, username, criteria, 5, 5)
+	}
+
+	// The supplier is debarred after its bid was scored.
+	env.debar(t, supplierOrg)
+
+	bid, err := env.srv.AwardTender(context.Background(), tender.ID, alice)
+	noErr(t, err)
+	if bid.ID != other.ID {
+		t.Fatalf("the next bid must win over the debarred one, got %s", bid.ID)
+	}
+
+	passed, err := env.repo.GetBidsWithID(context.Background(), best.ID)
+	noErr(t, err)
+	if passed.Status != models.BidStatusPublished {
+		t.Fatalf("the debarred bid must be left as it was, got %s", passed.Status)
+	}
+}
+
+func TestAwardTenderOnlyDebarred(t *testing.T) {
+	env := newTestEnv(t)
+
+	tender, criteria := env.scoredTender(t)
+	bid := env.publishedBid(t, tender.ID, bobID, bob)
+	for _, username := range []string{alice, anna, amy} {
+		env.score(t, bid.ID, username, criteria, 9, 9)
+	}
+	env.debar(t, supplierOrg)
+
+	_, err := env.srv.AwardTender(context.Background(), tender.ID, alice)
+	expectErr(t, err, repository.ErrEvaluationIncomplete)
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusPublished {
+		t.Fatalf("the tender must stay open, got %s", status)
+	}
+}
+
+// TestApplyBidDecisionDebarred approves by hand, so there is no ranking to
+// pass the debarred bid over and the approval fails.
+func TestApplyBidDecisionDebarred(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	bid := env.publishedBid(t, tender.ID, bobID, bob)
+	env.debar(t, supplierOrg)
+
+	_, err := env.srv.ApplyBidDecision(ctx, bid.ID, alice, ptr(models.BidDecisionApproved))
+	expectErr(t, err, repository.ErrSupplierDebarred)
+	if env.metrics.decisions[models.BidDecisionApproved] != 0 {
+		t.Fatal("a refused approval must not count")
+	}
+
+	env.decide(t, bid.ID, alice, models.BidDecisionRejected)
+}
+
+func TestCloseEndedAuctionsPassesOverDebarred(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	deadline := time.Now().Add(300 * time.Millisecond)
+	tender := env.auction(t, deadline, 5, 0)
+	lowest := env.auctionBid(t, tender.ID, bobID, bob, 90)
+	next := env.auctionBid(t, tender.ID, olgaID, olga, 100)
+	env.debar(t, supplierOrg)
+
+	time.Sleep(time.Until(deadline))
+	noErr(t, env.srv.CloseEndedAuctions(ctx))
+
+	for id, want := range map[string]models.BidStatus{
+		lowest.ID: models.BidStatusPublished,
+		next.ID:   models.BidStatusApproved,
+	} {
+		bid, err := env.repo.GetBidsWithID(ctx, id)
+		noErr(t, err)
+		if bid.Status != want {
+			t.Fatalf("bid %s: got %s, want %s", id, bid.Status, want)
+		}
+	}
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusClosed {
+		t.Fatalf("the auction must be closed, got %s", status)
+	}
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SetTenderCriteria replaces the evaluation criteria of a tender. Once a
+// tender has criteria it is awarded by the weighted ranking of its bids
+// instead of by approval votes.
+func (s *Service) SetTenderCriteria(ctx context.Context, tenderID, username string, criteria *models.TenderCriteria) (_ []*models.Criterion, err error) {
+	ctx, span := startSpan(ctx, "SetTenderCriteria")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tender.Status == models.TenderStatusCancelled:
+		return nil, repository.ErrTenderCancelled
+	case tender.Status == models.TenderStatusClosed:
+		return nil, repository.ErrTenderClosed
+	case tender.Auction != nil:
+		return nil, repository.ErrAuctionDecision
+	}
+
+	if !validCriteria(criteria.Criteria) {
+		return nil, repository.ErrInvalidCriteria
+	}
+
+	return s.repo.SetTenderCriteria(ctx, tenderID, criteria.Criteria)
+}
+
+// GetTenderCriteria shows the evaluation criteria to those who may read the
+// tender, so that suppliers know how their bids are scored.
+func (s *Service) GetTenderCriteria(ctx context.Context, tenderID, username string) (_ []*models.Criterion, err error) {
+	ctx, span := startSpan(ctx, "GetTenderCriteria")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.getTender(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.checkTenderReadable(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetCriteriaByTenderIDs(ctx, []string{tenderID})
+}
+
+// ScoreBid records the scores a responsible of the tender gives a bid.
+// Scoring a criterion again replaces the earlier score.
+func (s *Service) ScoreBid(ctx context.Context, bidID, username string, scores *models.BidScores) (_ []*models.BidScore, err error) {
+	ctx, span := startSpan(ctx, "ScoreBid")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlUserResponsibilityForTenderByBidID(ctx, bidID, username); err != nil {
+		return nil, err
+	}
+
+	bid, tender, err := s.checkBidNotFrozen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotSealed(tender); err != nil {
+		return nil, err
+	}
+
+	if bid.Status != models.BidStatusPublished || tender.Status != models.TenderStatusPublished {
+		return nil, repository.ErrBidNotScorable
+	}
+
+	// The last score given for a criterion wins.
+	byCriterion := make(map[string]*models.CriterionScore, len(scores.Scores))
+	unique := make([]*models.CriterionScore, 0, len(scores.Scores))
+	for _, score := range scores.Scores {
+		if existing, ok := byCriterion[score.CriterionID]; ok {
+			existing.Score = score.Score
+			continue
+		}
+
+		byCriterion[score.CriterionID] = score
+		unique = append(unique, score)
+	}
+
+	return s.repo.ScoreBid(ctx, bidID, userID, unique)
+}
+
+// GetTenderEvaluation ranks the published bids on a tender by their
+// weighted score.
+func (s *Service) GetTenderEvaluation(ctx context.Context, tenderID, username string) (_ *models.Evaluation, err error) {
+	ctx, span := startSpan(ctx, "GetTenderEvaluation")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getTender(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.evaluateTender(ctx, tenderID)
+}
+
+// AwardTender approves the bid at the top of the weighted ranking and closes
+// the tender. Every published bid has to be fully scored by a quorum of
+// responsibles first, and the top has to be unique. Bids of debarred
+// suppliers are passed over, as on the close of an auction.
+func (s *Service) AwardTender(ctx context.Context, tenderID, username string) (_ *models.BidResponse, err error) {
+	ctx, span := startSpan(ctx, "AwardTender")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tender.Status == models.TenderStatusCancelled:
+		return nil, repository.ErrTenderCancelled
+	case tender.Status != models.TenderStatusPublished:
+		return nil, repository.ErrTenderClosed
+	}
+
+	if err := checkNotSealed(tender); err != nil {
+		return nil, err
+	}
+
+	evaluation, err := s.evaluateTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(evaluation.Criteria) == 0 {
+		return nil, repository.ErrNoCriteria
+	}
+
+	if evaluation.Bids, err = s.withoutDebarred(ctx, tenderID, evaluation.Bids); err != nil {
+		return nil, err
+	}
+
+	if len(evaluation.Bids) == 0 {
+		return nil, repository.ErrEvaluationIncomplete
+	}
+
+	quorum, err := s.getQuorum(ctx, evaluation.Bids[0].BidID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bid := range evaluation.Bids {
+		if bid.Consensus.Evaluators < quorum || bid.Consensus.Evaluators == 0 {
+			return nil, repository.ErrEvaluationIncomplete
+		}
+	}
+
+	if len(evaluation.Bids) > 1 && evaluation.Bids[1].Rank == evaluation.Bids[0].Rank {
+		return nil, repository.ErrEvaluationTie
+	}
+
+	winner := evaluation.Bids[0]
+	bid, err := s.repo.AwardBid(ctx, winner.BidID, username,
+		"weighted score: "+strconv.FormatFloat(*winner.Consensus.Total, 'f', 2, 64))
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.TenderAwarded()
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"bid_id":    bid.ID,
+		"tender_id": bid.TenderID,
+	}).Info("bid awarded by weighted ranking, tender closed")
+
+	return bid, nil
+}
+
+func (s *Service) evaluateTender(ctx context.Context, tenderID string) (*models.Evaluation, error) {
+	criteria, err := s.repo.GetCriteriaByTenderIDs(ctx, []string{tenderID})
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := s.repo.GetBidsByTenderIDs(ctx, []string{tenderID})
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.repo.GetScoresOfTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	seen := map[string]bool{}
+	for _, score := range scores {
+		if !seen[score.UserID] {
+			seen[score.UserID] = true
+			userIDs = append(userIDs, score.UserID)
+		}
+	}
+
+	employees, err := s.repo.GetEmployeesByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make(map[string]string, len(employees))
+	for _, employee := range employees {
+		usernames[employee.ID] = employee.Username
+	}
+
+	return evaluate(tenderID, criteria, bids, scores, usernames), nil
+}
+
+// evaluate ranks the published bids by the mean of their complete
+// scorecards. Incomplete scorecards are shown but do not count. Tied bids
+// share a rank.
+func evaluate(tenderID string, criteria []*models.Criterion, bids []*models.BidResponse, scores []*models.BidScore, usernames map[string]string) *models.Evaluation {
+	evaluation := &models.Evaluation{TenderID: tenderID, Criteria: criteria, Bids: []*models.BidEvaluation{}}
+
+	weights := make(map[string]int32, len(criteria))
+	for _, criterion := range criteria {
+		weights[criterion.ID] = criterion.Weight
+	}
+
+	scorecards := map[string]map[string]*models.EvaluatorScorecard{}
+	for _, score := range scores {
+		if _, ok := weights[score.CriterionID]; !ok {
+			continue
+		}
+
+		byEvaluator := scorecards[score.BidID]
+		if byEvaluator == nil {
+			byEvaluator = map[string]*models.EvaluatorScorecard{}
+			scorecards[score.BidID] = byEvaluator
+		}
+
+		scorecard := byEvaluator[score.UserID]
+		if scorecard == nil {
+			scorecard = &models.EvaluatorScorecard{
+				UserID:   score.UserID,
+				Username: usernames[score.UserID],
+				Scores:   map[string]int32{},
+			}
+			byEvaluator[score.UserID] = scorecard
+		}
+		scorecard.Scores[score.CriterionID] = score.Score
+	}
+
+	// Consensus totals are compared as fractions of integer sums, so that
+	// ties are exact.
+	tallies := map[string]tally{}
+	for _, bid := range bids {
+		if bid.Status != models.BidStatusPublished {
+			continue
+		}
+
+		result := &models.BidEvaluation{
+			BidID:      bid.ID,
+			BidName:    bid.Name,
+			Consensus:  &models.Consensus{Scores: map[string]float64{}},
+			Evaluators: []*models.EvaluatorScorecard{},
+		}
+
+		sums := map[string]int64{}
+		var sum int64
+		for _, scorecard := range scorecards[bid.ID] {
+			result.Evaluators = append(result.Evaluators, scorecard)
+			if len(criteria) == 0 || len(scorecard.Scores) != len(criteria) {
+				continue
+			}
+
+			var total int64
+			for criterionID, score := range scorecard.Scores {
+				sums[criterionID] += int64(score)
+				total += int64(weights[criterionID]) * int64(score)
+			}
+			scorecard.Total = weightedTotal(total, 1)
+			sum += total
+			result.Consensus.Evaluators++
+		}
+		sort.Slice(result.Evaluators, func(i, j int) bool {
+			return result.Evaluators[i].Username < result.Evaluators[j].Username
+		})
+
+		if n := result.Consensus.Evaluators; n != 0 {
+			for criterionID, s := range sums {
+				result.Consensus.Scores[criterionID] = float64(s) / float64(n)
+			}
+			result.Consensus.Total = weightedTotal(sum, n)
+			tallies[bid.ID] = tally{sum: sum, n: int64(n)}
+		}
+
+		evaluation.Bids = append(evaluation.Bids, result)
+	}
+
+	ranked := func(b *models.BidEvaluation) bool { return b.Consensus.Total != nil }
+	sort.SliceStable(evaluation.Bids, func(i, j int) bool {
+		a, b := evaluation.Bids[i], evaluation.Bids[j]
+		if ranked(a) != ranked(b) {
+			return ranked(a)
+		}
+		return tallies[a.BidID].greater(tallies[b.BidID])
+	})
+
+	for i, bid := range evaluation.Bids {
+		switch {
+		case !ranked(bid):
+		case i > 0 && !tallies[evaluation.Bids[i-1].BidID].greater(tallies[bid.BidID]):
+			bid.Rank = evaluation.Bids[i-1].Rank
+		default:
+			bid.Rank = i + 1
+		}
+	}
+
+	return evaluation
+}
+
+// tally is the sum of the weighted totals of n complete scorecards.
+type tally struct {
+	sum, n int64
+}
+
+func (t tally) greater(o tally) bool {
+	return t.sum*o.n > o.sum*t.n
+}
+
+// weightedTotal turns a sum of weight times score over n scorecards into a
+// score from 0 to 10.
+func weightedTotal(sum int64, n int) *float64 {
+	total := float64(sum) / float64(100*n)
+	return &total
+}
+
+func validCriteria(criteria []*models.CriterionCreate) bool {
+	names := make(map[string]bool, len(criteria))
+	var weights int32
+	for _, criterion := range criteria {
+		if names[criterion.Name] {
+			return false
+		}
+		names[criterion.Name] = true
+		weights += criterion.Weight
+	}
+
+	return weights == 100
+}
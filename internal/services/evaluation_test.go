@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	criteria := []*models.Criterion{{ID: "price", Weight: 60}, {ID: "time", Weight: 40}}
+	bids := []*models.BidResponse{
+		{ID: "b1", Status: models.BidStatusPublished},
+		{ID: "b2", Status: models.BidStatusPublished},
+		{ID: "b3", Status: models.BidStatusPublished},
+		{ID: "canceled", Status: models.BidStatusCanceled},
+		{ID: "approved", Status: models.BidStatusApproved},
+	}
+
+	// scorecard scores the bid on price and time, a negative score is left
+	// out.
+	scorecard := func(bidID, userID string, price, time int32) []*models.BidScore {
+		scores := []*models.BidScore{}
+		for criterionID, score := range map[string]int32{"price": price, "time": time} {
+			if score >= 0 {
+				scores = append(scores, &models.BidScore{BidID: bidID, UserID: userID, CriterionID: criterionID, Score: score})
+			}
+		}
+		return scores
+	}
+	scorecards := func(cards ...[]*models.BidScore) []*models.BidScore {
+		scores := []*models.BidScore{}
+		for _, card := range cards {
+			scores = append(scores, card...)
+		}
+		return scores
+	}
+
+	type ranked struct {
+		bidID      string
+		rank       int
+		total      float64 // -1 when unranked
+		evaluators int
+	}
+
+	tests := []struct {
+		name     string
+		criteria []*models.Criterion
+		scores   []*models.BidScore
+		want     []ranked
+	}{
+		{
+			name: "weights decide",
+			scores: scorecards(
+				scorecard("b1", "u1", 10, 0),
+				scorecard("b2", "u1", 0, 10),
+				scorecard("b3", "u1", 5, 5),
+			),
+			want: []ranked{{"b1", 1, 6, 1}, {"b3", 2, 5, 1}, {"b2", 3, 4, 1}},
+		},
+		{
+			name: "mean of the evaluators",
+			scores: scorecards(
+				scorecard("b1", "u1", 10, 10), scorecard("b1", "u2", 4, 4),
+				scorecard("b2", "u1", 8, 8),
+			),
+			want: []ranked{{"b2", 1, 8, 1}, {"b1", 2, 7, 2}, {"b3", 0, -1, 0}},
+		},
+		{
+			name: "tie over different evaluator counts",
+			scores: scorecards(
+				scorecard("b1", "u1", 7, 7),
+				scorecard("b2", "u1", 6, 6), scorecard("b2", "u2", 8, 8),
+				scorecard("b3", "u1", 3, 9), scorecard("b3", "u2", 3, 9), scorecard("b3", "u3", 3, 9),
+			),
+			want: []ranked{{"b1", 1, 7, 1}, {"b2", 1, 7, 2}, {"b3", 3, 5.4, 3}},
+		},
+		{
+			name: "tie below the top",
+			scores: scorecards(
+				scorecard("b1", "u1", 9, 9),
+				scorecard("b2", "u1", 5, 5),
+				scorecard("b3", "u1", 5, 5),
+			),
+			want: []ranked{{"b1", 1, 9, 1}, {"b2", 2, 5, 1}, {"b3", 2, 5, 1}},
+		},
+		{
+			name: "partial scorecards do not count",
+			scores: scorecards(
+				scorecard("b1", "u1", 10, 10), scorecard("b1", "u2", 0, -1),
+				scorecard("b2", "u1", 10, -1),
+			),
+			want: []ranked{{"b1", 1, 10, 1}, {"b2", 0, -1, 0}, {"b3", 0, -1, 0}},
+		},
+		{
+			name: "scores of removed criteria are ignored",
+			scores: scorecards(
+				scorecard("b1", "u1", 2, 2),
+				[]*models.BidScore{{BidID: "b2", UserID: "u1", CriterionID: "removed", Score: 10}},
+				scorecard("b2", "u1", 1, 1),
+			),
+			want: []ranked{{"b1", 1, 2, 1}, {"b2", 2, 1, 1}, {"b3", 0, -1, 0}},
+		},
+		{
+			name: "only published bids are ranked",
+			scores: scorecards(
+				scorecard("canceled", "u1", 10, 10),
+				scorecard("approved", "u1", 10, 10),
+				scorecard("b3", "u1", 1, 1),
+			),
+			want: []ranked{{"b3", 1, 1, 1}, {"b1", 0, -1, 0}, {"b2", 0, -1, 0}},
+		},
+		{
+			name:     "no criteria",
+			criteria: []*models.Criterion{},
+			scores:   scorecard("b1", "u1", 10, 10),
+			want:     []ranked{{"b1", 0, -1, 0}, {"b2", 0, -1, 0}, {"b3", 0, -1, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.criteria == nil {
+				tt.criteria = criteria
+			}
+
+			evaluation := evaluate("tender", tt.criteria, bids, tt.scores, map[string]string{"u1": "anna", "u2": "alice"})
+			if len(evaluation.Bids) != len(tt.want) {
+				t.Fatalf("got %d bids, want %d", len(evaluation.Bids), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				got := evaluation.Bids[i]
+				total := -1.0
+				if got.Consensus.Total != nil {
+					total = *got.Consensus.Total
+				}
+				if got.BidID != want.bidID || got.Rank != want.rank || total != want.total || got.Consensus.Evaluators != want.evaluators {
+					t.Fatalf("place %d: got %s rank %d total %v by %d, want %+v",
+						i, got.BidID, got.Rank, total, got.Consensus.Evaluators, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateScorecards(t *testing.T) {
+	criteria := []*models.Criterion{{ID: "price", Weight: 70}, {ID: "time", Weight: 30}}
+	bids := []*models.BidResponse{{ID: "b1", Status: models.BidStatusPublished}}
+	scores := []*models.BidScore{
+		{BidID: "b1", UserID: "u1", CriterionID: "price", Score: 10},
+		{BidID: "b1", UserID: "u1", CriterionID: "time", Score: 5},
+		{BidID: "b1", UserID: "u2", CriterionID: "price", Score: 6},
+		{BidID: "b1", UserID: "u2", CriterionID: "time", Score: 9},
+		{BidID: "b1", UserID: "u3", CriterionID: "price", Score: 1},
+	}
+
+	bid := evaluate("tender", criteria, bids, scores, map[string]string{"u1": "bob", "u2": "anna", "u3": "carl"}).Bids[0]
+
+	if bid.Consensus.Scores["price"] != 8 || bid.Consensus.Scores["time"] != 7 {
+		t.Fatalf("the consensus must average the complete scorecards, got %v", bid.Consensus.Scores)
+	}
+
+	usernames := []string{}
+	for _, scorecard := range bid.Evaluators {
+		usernames = append(usernames, scorecard.Username)
+	}
+	if len(usernames) != 3 || usernames[0] != "anna" || usernames[1] != "bob" || usernames[2] != "carl" {
+		t.Fatalf("every scorecard sorted by username expected, got %v", usernames)
+	}
+
+	if total := bid.Evaluators[0].Total; total == nil || *total != 6.9 {
+		t.Fatalf("a complete scorecard must carry its weighted total, got %v", total)
+	}
+	if bid.Evaluators[2].Total != nil {
+		t.Fatalf("an incomplete scorecard has no total, got %v", *bid.Evaluators[2].Total)
+	}
+}
+
+func TestValidCriteria(t *testing.T) {
+	criterion := func(name string, weight int32) *models.CriterionCreate {
+		return &models.CriterionCreate{Name: name, Weight: weight}
+	}
+
+	tests := []struct {
+		name     string
+		criteria []*models.CriterionCreate
+		want     bool
+	}{
+		{"weights add up to 100", []*models.CriterionCreate{criterion("price", 60), criterion("time", 40)}, true},
+		{"single criterion", []*models.CriterionCreate{criterion("price", 100)}, true},
+		{"weights short of 100", []*models.CriterionCreate{criterion("price", 60), criterion("time", 39)}, false},
+		{"weights over 100", []*models.CriterionCreate{criterion("price", 60), criterion("time", 41)}, false},
+		{"duplicate name", []*models.CriterionCreate{criterion("price", 50), criterion("price", 50)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validCriteria(tt.criteria); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// scoredTender is a published tender of the buyer with a price and a time
+// criterion.
+func (e *testEnv) scoredTender(t *testing.T) (*models.TenderResponse, []*models.Criterion) {
+	t.Helper()
+
+	tender := e.tender(t, nil, true)
+	criteria, err := e.srv.SetTenderCriteria(context.Background(), tender.ID, alice, &models.TenderCriteria{
+		Criteria: []*models.CriterionCreate{{Name: "Price", Weight: 60}, {Name: "Time", Weight: 40}},
+	})
+	noErr(t, err)
+
+	return tender, criteria
+}
+
+func (e *testEnv) score(t *testing.T, bidID, username string, criteria []*models.Criterion, scores ...int32) {
+	t.Helper()
+
+	card := &models.BidScores{}
+	for i, score := range scores {
+		card.Scores = append(card.Scores, &models.CriterionScore{CriterionID: criteria[i].ID, Score: score})
+	}
+
+	_, err := e.srv.ScoreBid(context.Background(), bidID, username, card)
+	noErr(t, err)
+}
+
+func TestAwardTender(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender, criteria := env.scoredTender(t)
+
+	_, err := env.srv.AwardTender(ctx, tender.ID, alice)
+	expectErr(t, err, repository.ErrEvaluationIncomplete)
+
+	best := env.publishedBid(t, tender.ID, bobID, bob)
+	other := env.publishedBid(t, tender.ID, bellaID, bella)
+
+	// Two of the three responsibles are short of the quorum.
+	for _, username := range []string{alice, anna} {
+		env.score(t, best.ID, username, criteria, 9, 9)
+		env.score(t, other.ID, username, criteria, 5, 5)
+	}
+	_, err = env.srv.AwardTender(ctx, tender.ID, alice)
+	expectErr(t, err, repository.ErrEvaluationIncomplete)
+
+	// A partial scorecard does not count towards it.
+	env.score(t, best.ID, amy, criteria, 9)
+	env.score(t, other.ID, amy, criteria, 5, 5)
+	_, err = env.srv.AwardTender(ctx, tender.ID, alice)
+	expectErr(t, err, repository.ErrEvaluationIncomplete)
+
+	env.score(t, best.ID, amy, criteria, 9, 9)
+	_, err = env.srv.AwardTender(ctx, tender.ID, bob)
+	expectErr(t, err, repository.ErrRelationNotExist)
+
+	bid, err := env.srv.AwardTender(ctx, tender.ID, anna)
+	noErr(t, err)
+	if bid.ID != best.ID || bid.Status != models.BidStatusApproved {
+		t.Fatalf("the top of the ranking must be approved, got %s %s", bid.ID, bid.Status)
+	}
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusClosed {
+		t.Fatalf("the award must close the tender, got %s", status)
+	}
+	if env.metrics.awarded != 1 {
+		t.Fatalf("one award expected, got %d", env.metrics.awarded)
+	}
+
+	_, err = env.srv.AwardTender(ctx, tender.ID, anna)
+	expectErr(t, err, repository.ErrTenderClosed)
+}
+
+func TestAwardTenderTie(t *testing.T) {
+	env := newTestEnv(t)
+
+	tender, criteria := env.scoredTender(t)
+	first := env.publishedBid(t, tender.ID, bobID, bob)
+	second := env.publishedBid(t, tender.ID, bellaID, bella)
+
+	// Equal totals from different scores are a tie all the same.
+	for _, username := range []string{alice, anna, amy} {
+		env.score(t, first.ID, username, criteria, 6, 9)
+		env.score(t, second.ID, username, criteria, 8, 6)
+	}
+
+	_, err := env.srv.AwardTender(context.Background(), tender.ID, alice)
+	expectErr(t, err, repository.ErrEvaluationTie)
+	if status := env.tenderStatus(t, tender.ID); status != models.TenderStatusPublished {
+		t.Fatalf("a tie must leave the tender open, got %s", status)
+	}
+}
+
+func TestAwardTenderWithoutCriteria(t *testing.T) {
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	env.publishedBid(t, tender.ID, bobID, bob)
+
+	_, err := env.srv.AwardTender(context.Background(), tender.ID, alice)
+	expectErr(t, err, repository.ErrNoCriteria)
+}
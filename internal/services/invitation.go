@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// GetInvitedTenders lists the published tenders the organizations of the
+// user are invited to.
+func (s *Service) GetInvitedTenders(ctx context.Context, username string, limit, offset int32) (_ []*models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "GetInvitedTenders")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetInvitedTenders(ctx, userID, limit, offset)
+}
+
+// InviteOrganizations invites more organizations to an invitation-only
+// tender. Invitations cannot be taken back: the invited organizations may
+// have made their offers already.
+func (s *Service) InviteOrganizations(ctx context.Context, tenderID, username string, invitation *models.InvitationCreate) (_ []*models.TenderInvitation, err error) {
+	ctx, span := startSpan(ctx, "InviteOrganizations")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkInvitations(tender.Visibility, tender.OrganizationID, invitation.OrganizationIDs); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.InviteOrganizations(ctx, tenderID, invitation.OrganizationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"tender_id":     tenderID,
+		"organizations": len(invitation.OrganizationIDs),
+	}).Info("organizations invited")
+
+	return invitations, nil
+}
+
+func (s *Service) GetTenderInvitations(ctx context.Context, tenderID, username string) (_ []*models.TenderInvitation, err error) {
+	ctx, span := startSpan(ctx, "GetTenderInvitations")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTenderInvitations(ctx, tenderID)
+}
+
+// checkInvited makes sure an offer on behalf of an organization is made by
+// an invited one. A responsible of an invited organization sees the tender,
+// but may speak for other organizations too.
+func (s *Service) checkInvited(ctx context.Context, tenderID string, organizationID models.OrganizationID) error {
+	invitations, err := s.repo.GetTenderInvitations(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	for _, invitation := range invitations {
+		if invitation.OrganizationID == organizationID {
+			return nil
+		}
+	}
+
+	return repository.ErrBidNotInvited
+}
+
+// checkInvitations allows invitations on invitation-only tenders only, and
+// never of the organization of the tender itself.
+func checkInvitations(visibility models.TenderVisibility, organizationID models.OrganizationID, invited []models.OrganizationID) error {
+	if len(invited) == 0 {
+		return nil
+	}
+
+	if visibility != models.TenderVisibilityInvitation {
+		return repository.ErrInvalidInvitations
+	}
+
+	for _, id := range invited {
+		if id == organizationID {
+			return repository.ErrInvalidInvitations
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func TestConstructBidVisibility(t *testing.T) {
+	env := newTestEnv(t)
+
+	public := env.tender(t, nil, true)
+	internal := env.tender(t, func(c *models.TenderCreate) { c.Visibility = models.TenderVisibilityInternal }, true)
+	invitation := env.tender(t, func(c *models.TenderCreate) {
+		c.Visibility, c.InvitedOrganizations = models.TenderVisibilityInvitation, []models.OrganizationID{supplierOrg}
+	}, true)
+
+	tests := []struct {
+		name           string
+		tenderID       string
+		authorID       string
+		organizationID *models.OrganizationID
+		want           error
+	}{
+		{"public to anybody", public.ID, olgaID, nil, nil},
+		{"internal to an outsider", internal.ID, bobID, nil, repository.ErrRelationNotExist},
+		{"internal to an outsider's organization", internal.ID, olgaID, ptr(thirdOrg), repository.ErrRelationNotExist},
+		{"invitation to an invited responsible", invitation.ID, bobID, nil, nil},
+		{"invitation to the invited organization", invitation.ID, bobID, ptr(supplierOrg), nil},
+		{"invitation to an outsider", invitation.ID, olgaID, nil, repository.ErrRelationNotExist},
+		{"invitation to an outsider's organization", invitation.ID, olgaID, ptr(thirdOrg), repository.ErrRelationNotExist},
+		// bella sees the tender through the supplier, but speaks for the
+		// third organization here.
+		{"invitation to an organization not invited", invitation.ID, bellaID, ptr(thirdOrg), repository.ErrBidNotInvited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.bid(tt.tenderID, tt.authorID, tt.organizationID)
+			expectErr(t, err, tt.want)
+		})
+	}
+}
+
+func TestInviteOrganizations(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	invitation := env.tender(t, func(c *models.TenderCreate) {
+		c.Visibility, c.InvitedOrganizations = models.TenderVisibilityInvitation, []models.OrganizationID{supplierOrg}
+	}, true)
+
+	_, err := env.srv.GetStatusOfTender(ctx, invitation.ID, olga)
+	expectErr(t, err, repository.ErrRelationNotExist)
+	_, err = env.bid(invitation.ID, olgaID, ptr(thirdOrg))
+	expectErr(t, err, repository.ErrRelationNotExist)
+
+	_, err = env.srv.InviteOrganizations(ctx, invitation.ID, bob, &models.InvitationCreate{OrganizationIDs: []models.OrganizationID{thirdOrg}})
+	expectErr(t, err, repository.ErrRelationNotExist)
+	_, err = env.srv.InviteOrganizations(ctx, invitation.ID, alice, &models.InvitationCreate{OrganizationIDs: []models.OrganizationID{buyerOrg}})
+	expectErr(t, err, repository.ErrInvalidInvitations)
+
+	_, err = env.srv.InviteOrganizations(ctx, invitation.ID, alice, &models.InvitationCreate{OrganizationIDs: []models.OrganizationID{thirdOrg}})
+	noErr(t, err)
+
+	status, err := env.srv.GetStatusOfTender(ctx, invitation.ID, olga)
+	noErr(t, err)
+	if *status != models.TenderStatusPublished {
+		t.Fatalf("the invited organization must see the tender, got %s", *status)
+	}
+
+	invited, err := env.srv.GetInvitedTenders(ctx, olga, 10, 0)
+	noErr(t, err)
+	if len(invited) != 1 || invited[0].ID != invitation.ID {
+		t.Fatalf("the tender must be listed among the invitations, got %+v", invited)
+	}
+
+	_, err = env.bid(invitation.ID, olgaID, ptr(thirdOrg))
+	noErr(t, err)
+
+	public := env.tender(t, nil, true)
+	_, err = env.srv.InviteOrganizations(ctx, public.ID, alice, &models.InvitationCreate{OrganizationIDs: []models.OrganizationID{thirdOrg}})
+	expectErr(t, err, repository.ErrInvalidInvitations)
+}
+
+func TestAuctionRankingVisibility(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	auction := func(visibility models.TenderVisibility, published bool) *models.TenderResponse {
+		return env.tender(t, func(c *models.TenderCreate) {
+			deadline := time.Now().Add(time.Hour)
+			c.ServiceType, c.SubmissionDeadline, c.Visibility = models.TenderServiceTypeDelivery, &deadline, visibility
+			c.Auction = &models.Auction{Start: time.Now().Add(-time.Minute), Step: 5}
+			if visibility == models.TenderVisibilityInvitation {
+				c.InvitedOrganizations = []models.OrganizationID{supplierOrg}
+			}
+		}, published)
+	}
+
+	invitation := auction(models.TenderVisibilityInvitation, true)
+	env.auctionBid(t, invitation.ID, bobID, bob, 100)
+	draft := auction(models.TenderVisibilityPublic, false)
+
+	tests := []struct {
+		name     string
+		tenderID string
+		username string
+		want     error
+	}{
+		{"invitation to an invited responsible", invitation.ID, bob, nil},
+		{"invitation to the buyer", invitation.ID, alice, nil},
+		{"invitation to an outsider", invitation.ID, olga, repository.ErrRelationNotExist},
+		{"draft to the buyer", draft.ID, alice, nil},
+		{"draft to a supplier", draft.ID, bob, repository.ErrRelationNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.srv.GetAuctionRanking(ctx, tt.tenderID, tt.username)
+			expectErr(t, err, tt.want)
+		})
+	}
+
+	_, err := env.srv.InviteOrganizations(ctx, invitation.ID, alice, &models.InvitationCreate{OrganizationIDs: []models.OrganizationID{thirdOrg}})
+	noErr(t, err)
+
+	ranking, err := env.srv.GetAuctionRanking(ctx, invitation.ID, olga)
+	noErr(t, err)
+	if len(ranking) != 1 || ranking[0].Own {
+		t.Fatalf("the invited organization must see the ranking without owning a bid, got %+v", ranking)
+	}
+}
+
+func TestTenderCriteriaVisibility(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	withCriteria := func(tender *models.TenderResponse) string {
+		_, err := env.srv.SetTenderCriteria(ctx, tender.ID, alice, &models.TenderCriteria{
+			Criteria: []*models.CriterionCreate{{Name: "Price", Weight: 100}},
+		})
+		noErr(t, err)
+
+		return tender.ID
+	}
+
+	public := withCriteria(env.tender(t, nil, true))
+	internal := withCriteria(env.tender(t, func(c *models.TenderCreate) { c.Visibility = models.TenderVisibilityInternal }, true))
+	draft := withCriteria(env.tender(t, nil, false))
+
+	tests := []struct {
+		name     string
+		tenderID string
+		username string
+		want     error
+	}{
+		{"public to anybody", public, olga, nil},
+		{"internal to the buyer", internal, anna, nil},
+		{"internal to an outsider", internal, olga, repository.ErrRelationNotExist},
+		{"draft to the buyer", draft, alice, nil},
+		{"draft to a supplier", draft, bob, repository.ErrRelationNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria, err := env.srv.GetTenderCriteria(ctx, tt.tenderID, tt.username)
+			expectErr(t, err, tt.want)
+			if err == nil && len(criteria) != 1 {
+				t.Fatalf("got %d criteria, want 1", len(criteria))
+			}
+		})
+	}
+}
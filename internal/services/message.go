@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// PostBidMessage adds a message to the negotiation thread of a bid, on the
+// side of the bid the user speaks for.
+func (s *Service) PostBidMessage(ctx context.Context, bidID, username string, message *models.BidMessageCreate) (_ *models.BidMessage, err error) {
+	ctx, span := startSpan(ctx, "PostBidMessage")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, _, err := s.checkBidNotFrozen(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.bidThreadRole(ctx, bid, username)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.PostBidMessage(ctx, bidID, userID, role, message)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"bid_id":     bidID,
+		"message_id": result.ID,
+		"role":       role,
+	}).Info("bid message posted")
+
+	return result, nil
+}
+
+// GetBidMessages lists the negotiation thread of a bid for both of its sides.
+func (s *Service) GetBidMessages(ctx context.Context, bidID, username string, limit, offset int32) (_ []*models.BidMessage, err error) {
+	ctx, span := startSpan(ctx, "GetBidMessages")
+	defer func() { endSpan(span, err) }()
+
+	bid, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bidThreadRole(ctx, bid, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBidMessages(ctx, bidID, limit, offset)
+}
+
+// MarkBidMessagesRead marks the thread of a bid as read by the user.
+func (s *Service) MarkBidMessagesRead(ctx context.Context, bidID, username string) (_ *models.MessagesRead, err error) {
+	ctx, span := startSpan(ctx, "MarkBidMessagesRead")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.repo.GetBidsWithID(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bidThreadRole(ctx, bid, username); err != nil {
+		return nil, err
+	}
+
+	read, err := s.repo.MarkBidMessagesRead(ctx, bidID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MessagesRead{Read: read}, nil
+}
+
+// bidThreadRole tells on which side of the thread of a bid the user is. The
+// responsibles of the tender join the thread under the same conditions
+// under which they see the bid.
+func (s *Service) bidThreadRole(ctx context.Context, bid *models.BidResponse, username string) (models.MessageRole, error) {
+	err := s.repo.ControlUserResponsibilityForAuthorBid(ctx, bid.ID, username)
+	switch {
+	case err == nil:
+		return models.MessageRoleBidder, nil
+	case !errors.Is(err, repository.ErrRelationNotExist):
+		return "", err
+	}
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, bid.TenderID, username); err != nil {
+		return "", err
+	}
+
+	if bid.Status == models.BidStatusCreated {
+		return "", repository.ErrRelationNotExist
+	}
+
+	tender, err := s.getTender(ctx, bid.TenderID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkNotSealed(tender); err != nil {
+		return "", err
+	}
+
+	return models.MessageRoleBuyer, nil
+}
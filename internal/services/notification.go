@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/DarRo9/Tenders/models"
+)
+
+func (s *Service) GetNotifications(ctx context.Context, username string, limit, offset int32) (_ []*models.Notification, err error) {
+	ctx, span := startSpan(ctx, "GetNotifications")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetNotifications(ctx, userID, limit, offset)
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SetQuestionnaire replaces the questions an organization asks the
+// suppliers of a service type. The questions are frozen once somebody has
+// applied, since the answers refer to them.
+func (s *Service) SetQuestionnaire(ctx context.Context, username string, set *models.QuestionnaireSet) (_ *models.Questionnaire, err error) {
+	ctx, span := startSpan(ctx, "SetQuestionnaire")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlOrganizationPermission(ctx, &set.OrganizationID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.SetQuestionnaire(ctx, set)
+}
+
+// GetQuestionnaire shows a questionnaire to every user, so that suppliers
+// can prepare their answers.
+func (s *Service) GetQuestionnaire(ctx context.Context, questionnaireID, username string) (_ *models.Questionnaire, err error) {
+	ctx, span := startSpan(ctx, "GetQuestionnaire")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.repo.ControlBidCreationByID(ctx, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetQuestionnaire(ctx, questionnaireID)
+}
+
+// SubmitApplication answers a questionnaire on behalf of a supplier
+// organization. Licenses are attached to the application while it is under
+// review.
+func (s *Service) SubmitApplication(ctx context.Context, questionnaireID, username string, create *models.ApplicationCreate) (_ *models.Application, err error) {
+	ctx, span := startSpan(ctx, "SubmitApplication")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, &create.OrganizationID, userID); err != nil {
+		return nil, err
+	}
+
+	questionnaire, err := s.repo.GetQuestionnaire(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	if questionnaire.OrganizationID == create.OrganizationID {
+		return nil, repository.ErrApplicationOwnQuestionnaire
+	}
+
+	if err := checkAnswers(questionnaire.Questions, create.Answers); err != nil {
+		return nil, err
+	}
+
+	application, err := s.repo.SubmitApplication(ctx, questionnaireID, userID, create)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"questionnaire_id": questionnaireID,
+		"application_id":   application.ID,
+		"organization_id":  create.OrganizationID,
+	}).Info("prequalification application submitted")
+
+	return application, nil
+}
+
+// GetApplication shows an application with its licenses to both the
+// applicant and the organization of the questionnaire.
+func (s *Service) GetApplication(ctx context.Context, applicationID, username string) (_ *models.Application, err error) {
+	ctx, span := startSpan(ctx, "GetApplication")
+	defer func() { endSpan(span, err) }()
+
+	application, err := s.readApplication(ctx, applicationID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.Licenses, err = s.repo.GetApplicationLicenses(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// GetApplicationsOfQuestionnaire lists the applications to a questionnaire,
+// the latest first, to the responsibles of its organization.
+func (s *Service) GetApplicationsOfQuestionnaire(ctx context.Context, questionnaireID, username string, limit, offset int32) (_ []*models.Application, err error) {
+	ctx, span := startSpan(ctx, "GetApplicationsOfQuestionnaire")
+	defer func() { endSpan(span, err) }()
+
+	questionnaire, err := s.repo.GetQuestionnaire(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlOrganizationPermission(ctx, &questionnaire.OrganizationID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetApplicationsOfQuestionnaire(ctx, questionnaireID, limit, offset)
+}
+
+// GetMyApplications lists the applications of the organizations of the
+// user, the latest first.
+func (s *Service) GetMyApplications(ctx context.Context, username string, limit, offset int32) (_ []*models.Application, err error) {
+	ctx, span := startSpan(ctx, "GetMyApplications")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetApplicationsOfUser(ctx, userID, limit, offset)
+}
+
+// ReviewApplication approves an application until the given moment or
+// rejects it. A rejected supplier may apply again.
+func (s *Service) ReviewApplication(ctx context.Context, applicationID, username string, review *models.ApplicationReview) (_ *models.Application, err error) {
+	ctx, span := startSpan(ctx, "ReviewApplication")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.controlQuestionnaireOwner(ctx, applicationID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Decision == models.ApplicationDecisionApproved && !review.ValidUntil.After(time.Now()) {
+		return nil, repository.ErrInvalidValidity
+	}
+
+	application, err := s.repo.ReviewApplication(ctx, applicationID, userID, review)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"application_id": applicationID,
+		"decision":       review.Decision,
+	}).Info("prequalification application reviewed")
+
+	return application, nil
+}
+
+// RevokeApplication withdraws an approval before it expires. The supplier
+// cannot bid on prequalified tenders from then on, but keeps its bids.
+func (s *Service) RevokeApplication(ctx context.Context, applicationID, username, reason string) (_ *models.Application, err error) {
+	ctx, span := startSpan(ctx, "RevokeApplication")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.controlQuestionnaireOwner(ctx, applicationID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	application, err := s.repo.RevokeApplication(ctx, applicationID, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithField("application_id", applicationID).Info("prequalification approval revoked")
+
+	return application, nil
+}
+
+// AttachLicense streams a license into the blob store and adds it to an
+// application under review. Only the applicant attaches licenses.
+func (s *Service) AttachLicense(ctx context.Context, applicationID, username string, upload *models.AttachmentUpload, body io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := startSpan(ctx, "AttachLicense")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, repository.ErrAttachmentsDisabled
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	application, err := s.repo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, &application.OrganizationID, userID); err != nil {
+		return nil, err
+	}
+
+	if application.Status != models.ApplicationStatusSubmitted {
+		return nil, repository.ErrApplicationNotPending
+	}
+
+	attachment, err := s.storeFile(ctx, upload, body)
+	if err != nil {
+		return nil, err
+	}
+	attachment.ApplicationID = &application.ID
+	attachment.UploadedBy = userID
+
+	result, err := s.repo.AttachToApplication(ctx, attachment)
+	if err != nil {
+		s.deleteFile(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"application_id": applicationID,
+		"attachment_id":  result.ID,
+		"size":           result.Size,
+	}).Info("license attached to application")
+
+	return result, nil
+}
+
+// OpenLicense opens a license of an application for download. The caller
+// closes the reader.
+func (s *Service) OpenLicense(ctx context.Context, applicationID, attachmentID, username string) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := startSpan(ctx, "OpenLicense")
+	defer func() { endSpan(span, err) }()
+
+	if s.store == nil {
+		return nil, nil, repository.ErrAttachmentsDisabled
+	}
+
+	if _, err := s.readApplication(ctx, applicationID, username); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.ApplicationID == nil || *attachment.ApplicationID != applicationID {
+		return nil, nil, repository.ErrAttachmentNotFound
+	}
+
+	return s.openFile(ctx, attachment)
+}
+
+// readApplication returns the application if the user is a responsible of
+// either the applicant or the organization of the questionnaire.
+func (s *Service) readApplication(ctx context.Context, applicationID, username string) (*models.Application, error) {
+	application, err := s.repo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.ControlOrganizationPermission(ctx, &application.OrganizationID, username)
+	if !errors.Is(err, repository.ErrRelationNotExist) {
+		return application, err
+	}
+
+	questionnaire, err := s.repo.GetQuestionnaire(ctx, application.QuestionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlOrganizationPermission(ctx, &questionnaire.OrganizationID, username); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// controlQuestionnaireOwner makes sure the user speaks for the organization
+// of the questionnaire the application answers and returns the user's ID.
+func (s *Service) controlQuestionnaireOwner(ctx context.Context, applicationID, username string) (string, error) {
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	application, err := s.repo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return "", err
+	}
+
+	questionnaire, err := s.repo.GetQuestionnaire(ctx, application.QuestionnaireID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.ControlOrganizationPermissionByID(ctx, &questionnaire.OrganizationID, userID); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// checkPrequalification makes sure a bid on a tender that requires
+// prequalification comes from an approved supplier. The approval belongs to
+// the organization, so a personal bid is refused: it would ride on the
+// approval of whichever organization its author is a responsible of.
+func (s *Service) checkPrequalification(ctx context.Context, tender *models.TenderResponse, bid *models.BidCreate) error {
+	if !tender.Prequalification {
+		return nil
+	}
+
+	if bid.AuthorType != models.BidAuthorTypeOrganization {
+		return repository.ErrPrequalifiedOrganizationRequired
+	}
+
+	return s.repo.ControlPrequalification(ctx, tender.ID, *bid.OrganizationID)
+}
+
+// checkQuestionnaire makes sure suppliers have a questionnaire to answer
+// before a tender requires prequalification.
+func (s *Service) checkQuestionnaire(ctx context.Context, organizationID models.OrganizationID, serviceType models.TenderServiceType) error {
+	_, err := s.repo.FindQuestionnaire(ctx, organizationID, serviceType)
+	return err
+}
+
+// checkAnswers requires an answer of the right type to every required
+// question and accepts answers to the questions of the questionnaire only.
+func checkAnswers(questions []*models.PrequalificationQuestion, answers []*models.Answer) error {
+	byID := make(map[string]*models.PrequalificationQuestion, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	answered := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		question, ok := byID[answer.QuestionID]
+		if !ok || answered[answer.QuestionID] || !validAnswer(question.AnswerType, answer.Value) {
+			return repository.ErrInvalidAnswers
+		}
+
+		answered[answer.QuestionID] = true
+	}
+
+	for _, question := range questions {
+		if question.Required && !answered[question.ID] {
+			return repository.ErrInvalidAnswers
+		}
+	}
+
+	return nil
+}
+
+func validAnswer(answerType models.AnswerType, value string) bool {
+	var err error
+	switch answerType {
+	case models.AnswerTypeNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case models.AnswerTypeBoolean:
+		_, err = strconv.ParseBool(value)
+	case models.AnswerTypeDate:
+		_, err = time.Parse(time.DateOnly, value)
+	default:
+		return strings.TrimSpace(value) != ""
+	}
+
+	return err == nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+// approve has the supplier organization apply to the questionnaire and
+// alice approve the application until the given moment.
+func (e *testEnv) approve(t *testing.T, questionnaire *models.Questionnaire, username string, organizationID models.OrganizationID, validUntil time.Time) *models.Application {
+	t.Helper()
+	ctx := context.Background()
+
+	application, err := e.srv.SubmitApplication(ctx, questionnaire.ID, username, &models.ApplicationCreate{
+		OrganizationID: organizationID,
+		Answers:        []*models.Answer{{QuestionID: questionnaire.Questions[0].ID, Value: "true"}},
+	})
+	noErr(t, err)
+
+	application, err = e.srv.ReviewApplication(ctx, application.ID, alice, &models.ApplicationReview{
+		Decision: models.ApplicationDecisionApproved, ValidUntil: &validUntil,
+	})
+	noErr(t, err)
+
+	return application
+}
+
+func TestConstructBidPrequalification(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	questionnaire, err := env.srv.SetQuestionnaire(ctx, alice, &models.QuestionnaireSet{
+		OrganizationID: buyerOrg, ServiceType: models.TenderServiceTypeConstruction,
+		Questions: []*models.PrequalificationQuestionCreate{{Text: "Licensed?", AnswerType: models.AnswerTypeBoolean, Required: true}},
+	})
+	noErr(t, err)
+
+	tender := env.tender(t, func(c *models.TenderCreate) { c.Prequalification = true }, true)
+	application := env.approve(t, questionnaire, bob, supplierOrg, time.Now().Add(24*time.Hour))
+
+	_, err = env.bid(tender.ID, bobID, ptr(supplierOrg))
+	noErr(t, err)
+	// The approval belongs to the supplier, a personal bid of its
+	// responsible does not ride on it.
+	_, err = env.bid(tender.ID, bobID, nil)
+	expectErr(t, err, repository.ErrPrequalifiedOrganizationRequired)
+	// bella speaks for the approved supplier too, but not here.
+	_, err = env.bid(tender.ID, bellaID, ptr(thirdOrg))
+	expectErr(t, err, repository.ErrNotPrequalified)
+
+	// A tender without prequalification takes anybody.
+	open := env.tender(t, nil, true)
+	_, err = env.bid(open.ID, olgaID, nil)
+	noErr(t, err)
+
+	// The approval is checked at bid time, the supplier offers once per
+	// tender, so every check below takes a tender of its own.
+	prequalified := func(t *testing.T) *models.TenderResponse {
+		return env.tender(t, func(c *models.TenderCreate) { c.Prequalification = true }, true)
+	}
+
+	t.Run("revoked", func(t *testing.T) {
+		_, err := env.srv.RevokeApplication(ctx, application.ID, alice, "License withdrawn")
+		noErr(t, err)
+
+		_, err = env.bid(prequalified(t).ID, bellaID, ptr(supplierOrg))
+		expectErr(t, err, repository.ErrNotPrequalified)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		env.approve(t, questionnaire, olga, thirdOrg, time.Now().Add(300*time.Millisecond))
+
+		_, err := env.bid(tender.ID, olgaID, ptr(thirdOrg))
+		noErr(t, err)
+
+		time.Sleep(400 * time.Millisecond)
+
+		_, err = env.bid(prequalified(t).ID, olgaID, ptr(thirdOrg))
+		expectErr(t, err, repository.ErrNotPrequalified)
+	})
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// AskQuestion posts a clarification question about a published tender.
+func (s *Service) AskQuestion(ctx context.Context, tenderID, username string, question *models.QuestionCreate) (_ *models.Question, err error) {
+	ctx, span := startSpan(ctx, "AskQuestion")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	responsible, err := s.isTenderResponsible(ctx, tenderID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if responsible {
+		return nil, repository.ErrQuestionOwnTender
+	}
+
+	if err := s.repo.IsTenderPudlished(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlTenderVisibility(ctx, tenderID, userID); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+
+	return s.repo.AskQuestion(ctx, tenderID, userID, question.Question)
+}
+
+// GetTenderQuestions lists the questions on a tender. The responsibles of the
+// tender see every question, everybody else the public answers, with the
+// asker anonymized, and their own questions.
+func (s *Service) GetTenderQuestions(ctx context.Context, tenderID, username string, limit, offset int32) (_ []*models.Question, err error) {
+	ctx, span := startSpan(ctx, "GetTenderQuestions")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	responsible, err := s.checkTenderReadable(ctx, tenderID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.repo.GetQuestions(ctx, tenderID, userID, responsible, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if !responsible {
+		for _, question := range questions {
+			if question.AskerID != userID {
+				question.AskerID = ""
+			}
+		}
+	}
+
+	return questions, nil
+}
+
+// AnswerQuestion answers a question once. An amendment in the answer is
+// applied to the tender first, the same way ChangeTender applies it.
+func (s *Service) AnswerQuestion(ctx context.Context, tenderID, questionID, username string, answer *models.QuestionAnswer) (_ *models.Question, err error) {
+	ctx, span := startSpan(ctx, "AnswerQuestion")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetQuestion(ctx, tenderID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Answer != nil {
+		return nil, repository.ErrQuestionAnswered
+	}
+
+	var amendedVersion *int32
+	if answer.Amendment != nil {
+		tender, err := s.ChangeTender(ctx, tenderID, username, answer.Amendment)
+		if err != nil {
+			return nil, err
+		}
+
+		version := int32(tender.Version)
+		amendedVersion = &version
+	}
+
+	question, err := s.repo.AnswerQuestion(ctx, tenderID, questionID, userID, answer, amendedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"tender_id":   tenderID,
+		"question_id": questionID,
+		"visibility":  answer.Visibility,
+		"amended":     amendedVersion != nil,
+	}).Info("question answered")
+
+	return question, nil
+}
+
+// isTenderResponsible reports whether the user is a responsible of the
+// organization of the tender.
+func (s *Service) isTenderResponsible(ctx context.Context, tenderID, username string) (bool, error) {
+	err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username)
+	switch {
+	case errors.Is(err, repository.ErrRelationNotExist):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkTenderReadable lets the responsibles of a tender read it in any
+// status, everybody else who sees it once it is published. It reports
+// whether the user is a responsible.
+func (s *Service) checkTenderReadable(ctx context.Context, tenderID, username string) (bool, error) {
+	responsible, err := s.isTenderResponsible(ctx, tenderID, username)
+	if err != nil || responsible {
+		return responsible, err
+	}
+
+	status, _, err := s.repo.GetStatusOfTender(ctx, tenderID)
+	if err != nil {
+		return false, err
+	}
+
+	if *status != models.TenderStatusPublished && *status != models.TenderStatusClosed {
+		return false, repository.ErrRelationNotExist
+	}
+
+	userID, err := s.repo.ControlBidCreationByID(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	return false, s.repo.ControlTenderVisibility(ctx, tenderID, userID)
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// sealedBid is what gets encrypted for a bid on a sealed tender.
+type sealedBid struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (s *Service) RevealTender(ctx context.Context, tenderID, username string) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "RevealTender")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tender.Sealed || tender.RevealedAt != nil || tender.SubmissionDeadline.After(time.Now()) {
+		return nil, repository.ErrTenderNotRevealable
+	}
+
+	if s.sealer == nil {
+		return nil, repository.ErrSealingDisabled
+	}
+
+	wrappedKey, err := s.repo.GetTenderKey(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := s.repo.GetSealedBidContents(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bids := make([]*models.RevealedBid, 0, len(contents))
+	for _, content := range contents {
+		bid, err := s.openBid(wrappedKey, tenderID, content.BidID, content.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		bids = append(bids, &models.RevealedBid{
+			BidID:       content.BidID,
+			Version:     content.Version,
+			Name:        bid.Name,
+			Description: bid.Description,
+		})
+	}
+
+	tender, err = s.repo.RevealTender(ctx, tenderID, username, bids)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger(ctx).WithFields(logrus.Fields{
+		"tender_id": tenderID,
+		"versions":  len(bids),
+	}).Info("sealed tender revealed")
+
+	return tender, nil
+}
+
+func (s *Service) GetTenderAudit(ctx context.Context, tenderID, username string, limit, offset int32) (_ []*models.AuditRecord, err error) {
+	ctx, span := startSpan(ctx, "GetTenderAudit")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlUserResponsibilityForTender(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAuditLog(ctx, tenderID, limit, offset)
+}
+
+func (s *Service) getTender(ctx context.Context, tenderID string) (*models.TenderResponse, error) {
+	tenders, err := s.repo.GetTendersByIDs(ctx, []string{tenderID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenders) == 0 {
+		return nil, repository.ErrTenderNotFound
+	}
+
+	return tenders[0], nil
+}
+
+// checkSubmissionOpen rejects new bids and changes to bids once the
+// submission deadline of the tender has passed.
+func checkSubmissionOpen(tender *models.TenderResponse) error {
+	if tender.SubmissionDeadline != nil && !tender.SubmissionDeadline.After(time.Now()) {
+		return repository.ErrSubmissionClosed
+	}
+
+	return nil
+}
+
+// checkNotSealed keeps the buyer from judging bids that nobody can read yet.
+func checkNotSealed(tender *models.TenderResponse) error {
+	if tender.Sealed && tender.RevealedAt == nil {
+		return repository.ErrTenderSealed
+	}
+
+	return nil
+}
+
+// sealBid encrypts the bid with the key of its tender. The tender, the bid
+// and the version the content is sealed for are authenticated along with it,
+// so a sealed bid cannot be replayed on another tender or passed off as
+// another bid. The version travels in the clear ahead of the ciphertext, as
+// a status change or a rollback carries the content on to later versions.
+func (s *Service) sealBid(ctx context.Context, tenderID, bidID string, version int, bid *sealedBid) ([]byte, error) {
+	if s.sealer == nil {
+		return nil, repository.ErrSealingDisabled
+	}
+
+	wrappedKey, err := s.repo.GetTenderKey(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(bid)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := s.sealer.Seal(wrappedKey, plaintext, sealedBidAD(tenderID, bidID, uint32(version)))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(binary.BigEndian.AppendUint32(nil, uint32(version)), ciphertext...), nil
+}
+
+func (s *Service) openBid(wrappedKey []byte, tenderID, bidID string, content []byte) (*sealedBid, error) {
+	if len(content) < 4 {
+		return nil, fmt.Errorf("sealed content of bid %s is malformed", bidID)
+	}
+
+	version := binary.BigEndian.Uint32(content)
+	plaintext, err := s.sealer.Open(wrappedKey, content[4:], sealedBidAD(tenderID, bidID, version))
+	if err != nil {
+		return nil, err
+	}
+
+	bid := &sealedBid{}
+	return bid, json.Unmarshal(plaintext, bid)
+}
+
+func sealedBidAD(tenderID, bidID string, version uint32) []byte {
+	return fmt.Appendf(nil, "tender:%s/bid:%s/version:%d", tenderID, bidID, version)
+}
+
+// resealBid applies the edit to the sealed content of the current version of
+// the bid and seals the result for the next version.
+func (s *Service) resealBid(ctx context.Context, bid *models.BidResponse, tenderID string, edit *models.BidEdit) ([]byte, error) {
+	if s.sealer == nil {
+		return nil, repository.ErrSealingDisabled
+	}
+
+	wrappedKey, err := s.repo.GetTenderKey(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := s.repo.GetSealedBidContents(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []byte
+	for _, content := range contents {
+		if content.BidID == bid.ID && content.Version == bid.Version {
+			stored = content.Content
+		}
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("sealed content of bid %s version %d is missing", bid.ID, bid.Version)
+	}
+
+	sealed, err := s.openBid(wrappedKey, tenderID, bid.ID, stored)
+	if err != nil {
+		return nil, err
+	}
+
+	if edit.Name != nil {
+		sealed.Name = *edit.Name
+	}
+	if edit.Description != nil {
+		sealed.Description = *edit.Description
+	}
+
+	return s.sealBid(ctx, tenderID, bid.ID, bid.Version+1, sealed)
+}
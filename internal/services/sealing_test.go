@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/sealing"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func TestSealedBidBinding(t *testing.T) {
+	ctx := context.Background()
+
+	sealer, err := sealing.New(&config.SealingConfig{MasterKey: base64.StdEncoding.EncodeToString(make([]byte, 32))})
+	noErr(t, err)
+	env := newTestEnv(t, func(s *Service) { s.sealer = sealer })
+
+	tender := env.tender(t, func(c *models.TenderCreate) {
+		c.Sealed, c.SubmissionDeadline = true, ptr(time.Now().Add(time.Hour))
+	}, true)
+
+	bid, err := env.bid(tender.ID, bobID, nil)
+	noErr(t, err)
+	_, err = env.srv.ChangeBid(ctx, bid.ID, bob, &models.BidEdit{Name: ptr("Better offer")})
+	noErr(t, err)
+
+	wrappedKey, err := env.repo.GetTenderKey(ctx, tender.ID)
+	noErr(t, err)
+	contents, err := env.repo.GetSealedBidContents(ctx, tender.ID)
+	noErr(t, err)
+	if len(contents) != 2 {
+		t.Fatalf("two sealed versions expected, got %d", len(contents))
+	}
+
+	for _, content := range contents {
+		opened, err := env.srv.openBid(wrappedKey, tender.ID, bid.ID, content.Content)
+		noErr(t, err)
+		if want := map[int]string{1: "Offer", 2: "Better offer"}[content.Version]; opened.Name != want {
+			t.Fatalf("version %d: got %q, want %q", content.Version, opened.Name, want)
+		}
+	}
+
+	first := contents[0].Content
+	if contents[0].Version != 1 {
+		first = contents[1].Content
+	}
+	otherVersion := append([]byte{0, 0, 0, 2}, first[4:]...)
+
+	tests := []struct {
+		name     string
+		tenderID string
+		bidID    string
+		content  []byte
+	}{
+		{"another bid", tender.ID, nobody, first},
+		{"another tender", nobody, bid.ID, first},
+		{"another version", tender.ID, bid.ID, otherVersion},
+		{"no version", tender.ID, bid.ID, first[:3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.srv.openBid(wrappedKey, tt.tenderID, tt.bidID, tt.content); err == nil {
+				t.Fatal("a sealed bid must not open out of its place")
+			}
+		})
+	}
+}
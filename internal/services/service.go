@@ -2,15 +2,43 @@ package service
 
 import (
 	"context"
+	"io"
 
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/logger"
 	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/internal/scanning"
+	"github.com/DarRo9/Tenders/internal/sealing"
+	"github.com/DarRo9/Tenders/internal/storage"
 	"github.com/DarRo9/Tenders/models"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/DarRo9/Tenders/internal/services")
+
 type Service struct {
-	repo repository.Repository
-	log  *logrus.Logger
+	repo        repository.Repository
+	metrics     Metrics
+	bids        *config.BidsConfig
+	platform    *config.PlatformConfig
+	sealer      *sealing.Sealer
+	store       storage.BlobStore
+	attachments *config.StorageConfig
+	scanner     scanning.Scanner
+	log         *logrus.Logger
+}
+
+// Metrics receives the business events of the service.
+type Metrics interface {
+	TenderPublished()
+	BidCreated()
+	DecisionApplied(decision models.BidDecision)
+	TenderAwarded()
+	TenderCancelled()
+	BidWithdrawn()
 }
 
 type BidService interface {
@@ -24,21 +52,117 @@ type BidService interface {
 	ApplyBidFeedback(ctx context.Context, bidID, username string, feedback *models.BidFeedback) (*models.BidResponse, error)
 	CancelChangesOfBid(ctx context.Context, bidID, username string, version int32) (*models.BidResponse, error)
 	GetCommentsOfBid(ctx context.Context, tenderID, authorUsername, requesterUsername string, limit, offset int32) ([]*models.BidReviewResponse, error)
+	WithdrawBid(ctx context.Context, bidID, username, reason string) (*models.BidResponse, error)
+	SubmitBidPrice(ctx context.Context, bidID, username string, price float64) (*models.BidResponse, error)
+	ScoreBid(ctx context.Context, bidID, username string, scores *models.BidScores) ([]*models.BidScore, error)
+	PostBidMessage(ctx context.Context, bidID, username string, message *models.BidMessageCreate) (*models.BidMessage, error)
+	GetBidMessages(ctx context.Context, bidID, username string, limit, offset int32) ([]*models.BidMessage, error)
+	MarkBidMessagesRead(ctx context.Context, bidID, username string) (*models.MessagesRead, error)
+	AttachToBid(ctx context.Context, bidID, username string, upload *models.AttachmentUpload, body io.Reader) (*models.Attachment, error)
+	DetachFromBid(ctx context.Context, bidID, attachmentID, username string) (*models.BidResponse, error)
+	GetBidAttachments(ctx context.Context, bidID, username string) ([]*models.Attachment, error)
+	OpenBidAttachment(ctx context.Context, bidID, attachmentID, username string) (*models.Attachment, io.ReadCloser, error)
 }
 
 type TenderService interface {
-	GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, limit, offset int32) ([]*models.TenderResponse, error)
+	GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, username string, limit, offset int32) ([]*models.TenderResponse, error)
+	GetInvitedTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error)
 	BuildTender(ctx context.Context, tender *models.TenderCreate) (*models.TenderResponse, error)
 	GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error)
 	GetStatusOfTender(ctx context.Context, tenderID, username string) (*models.TenderStatus, error)
 	RefreshTenderStatus(ctx context.Context, tenderID, username string, status models.TenderStatus) (*models.TenderResponse, error)
 	ChangeTender(ctx context.Context, tenderID string, username string, tender *models.TenderEdit) (*models.TenderResponse, error)
 	RollbackTender(ctx context.Context, tenderID string, version int32, username string) (*models.TenderResponse, error)
+	CancelTender(ctx context.Context, tenderID, username, reason string) (*models.TenderResponse, error)
+	RevealTender(ctx context.Context, tenderID, username string) (*models.TenderResponse, error)
+	GetTenderAudit(ctx context.Context, tenderID, username string, limit, offset int32) ([]*models.AuditRecord, error)
+	GetAuctionRanking(ctx context.Context, tenderID, username string) ([]*models.AuctionRank, error)
+	SetTenderCriteria(ctx context.Context, tenderID, username string, criteria *models.TenderCriteria) ([]*models.Criterion, error)
+	GetTenderCriteria(ctx context.Context, tenderID, username string) ([]*models.Criterion, error)
+	GetTenderEvaluation(ctx context.Context, tenderID, username string) (*models.Evaluation, error)
+	AwardTender(ctx context.Context, tenderID, username string) (*models.BidResponse, error)
+	AskQuestion(ctx context.Context, tenderID, username string, question *models.QuestionCreate) (*models.Question, error)
+	GetTenderQuestions(ctx context.Context, tenderID, username string, limit, offset int32) ([]*models.Question, error)
+	AnswerQuestion(ctx context.Context, tenderID, questionID, username string, answer *models.QuestionAnswer) (*models.Question, error)
+	AttachToTender(ctx context.Context, tenderID, username string, upload *models.AttachmentUpload, body io.Reader) (*models.Attachment, error)
+	DetachFromTender(ctx context.Context, tenderID, attachmentID, username string) (*models.TenderResponse, error)
+	GetTenderAttachments(ctx context.Context, tenderID, username string) ([]*models.Attachment, error)
+	OpenTenderAttachment(ctx context.Context, tenderID, attachmentID, username string) (*models.Attachment, io.ReadCloser, error)
+	InviteOrganizations(ctx context.Context, tenderID, username string, invitation *models.InvitationCreate) ([]*models.TenderInvitation, error)
+	GetTenderInvitations(ctx context.Context, tenderID, username string) ([]*models.TenderInvitation, error)
 }
 
-func New(repo repository.Repository, log *logrus.Logger) *Service {
+type PrequalificationService interface {
+	SetQuestionnaire(ctx context.Context, username string, set *models.QuestionnaireSet) (*models.Questionnaire, error)
+	GetQuestionnaire(ctx context.Context, questionnaireID, username string) (*models.Questionnaire, error)
+	SubmitApplication(ctx context.Context, questionnaireID, username string, create *models.ApplicationCreate) (*models.Application, error)
+	GetApplication(ctx context.Context, applicationID, username string) (*models.Application, error)
+	GetApplicationsOfQuestionnaire(ctx context.Context, questionnaireID, username string, limit, offset int32) ([]*models.Application, error)
+	GetMyApplications(ctx context.Context, username string, limit, offset int32) ([]*models.Application, error)
+	ReviewApplication(ctx context.Context, applicationID, username string, review *models.ApplicationReview) (*models.Application, error)
+	RevokeApplication(ctx context.Context, applicationID, username, reason string) (*models.Application, error)
+	AttachLicense(ctx context.Context, applicationID, username string, upload *models.AttachmentUpload, body io.Reader) (*models.Attachment, error)
+	OpenLicense(ctx context.Context, applicationID, attachmentID, username string) (*models.Attachment, io.ReadCloser, error)
+}
+
+type DebarmentService interface {
+	DebarSupplier(ctx context.Context, username string, create *models.DebarmentCreate) (*models.Debarment, error)
+	GetDebarment(ctx context.Context, debarmentID, username string) (*models.Debarment, error)
+	GetDebarments(ctx context.Context, username string, organizationID *models.OrganizationID, limit, offset int32) ([]*models.Debarment, error)
+	GetMyDebarments(ctx context.Context, username string, limit, offset int32) ([]*models.Debarment, error)
+	ChangeDebarment(ctx context.Context, debarmentID, username string, edit *models.DebarmentEdit) (*models.Debarment, error)
+	LiftDebarment(ctx context.Context, debarmentID, username, reason string) (*models.Debarment, error)
+	GetDebarmentAudit(ctx context.Context, debarmentID, username string, limit, offset int32) ([]*models.AuditRecord, error)
+}
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, username string, limit, offset int32) ([]*models.Notification, error)
+}
+
+// nopMetrics is used when metrics are disabled.
+type nopMetrics struct{}
+
+func (nopMetrics) TenderPublished()                   {}
+func (nopMetrics) BidCreated()                        {}
+func (nopMetrics) DecisionApplied(models.BidDecision) {}
+func (nopMetrics) TenderAwarded()                     {}
+func (nopMetrics) TenderCancelled()                   {}
+func (nopMetrics) BidWithdrawn()                      {}
+
+// New takes a nil sealer when sealed tenders are disabled and a nil store
+// when attachments are.
+func New(repo repository.Repository, metrics Metrics, bids *config.BidsConfig, platform *config.PlatformConfig, sealer *sealing.Sealer,
+	store storage.BlobStore, attachments *config.StorageConfig, scanner scanning.Scanner, log *logrus.Logger) *Service {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+
 	return &Service{
-		repo: repo,
-		log:  log,
+		repo:        repo,
+		metrics:     metrics,
+		bids:        bids,
+		platform:    platform,
+		sealer:      sealer,
+		store:       store,
+		attachments: attachments,
+		scanner:     scanner,
+		log:         log,
+	}
+}
+
+func (s *Service) logger(ctx context.Context) *logrus.Entry {
+	return logger.FromContext(ctx, s.log)
+}
+
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "Service."+method)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+
+	span.End()
 }
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"github.com/DarRo9/Tenders/internal/repository/memory"
+	"github.com/DarRo9/Tenders/internal/seed"
+	"github.com/DarRo9/Tenders/models"
+	"github.com/sirupsen/logrus"
+)
+
+// The fixture has a buyer organization with three responsibles, so that an
+// approval needs the full quorum of three, a supplier organization with two
+// responsibles, a third organization bella speaks for along with olga and an
+// employee who is responsible nowhere.
+const (
+	buyerOrg    models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-000000000001"
+	supplierOrg models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-000000000002"
+	thirdOrg    models.OrganizationID = "0b8f4a52-2f1e-4c1c-9f0e-000000000003"
+
+	aliceID = "1c9e5b63-3a2f-4d2d-8a1f-000000000001"
+	annaID  = "1c9e5b63-3a2f-4d2d-8a1f-000000000002"
+	amyID   = "1c9e5b63-3a2f-4d2d-8a1f-000000000003"
+	bobID   = "1c9e5b63-3a2f-4d2d-8a1f-000000000004"
+	bellaID = "1c9e5b63-3a2f-4d2d-8a1f-000000000005"
+	carlID  = "1c9e5b63-3a2f-4d2d-8a1f-000000000006"
+	olgaID  = "1c9e5b63-3a2f-4d2d-8a1f-000000000007"
+	nobody  = "1c9e5b63-3a2f-4d2d-8a1f-0000000000ff"
+
+	alice = "alice"
+	anna  = "anna"
+	amy   = "amy"
+	bob   = "bob"
+	bella = "bella"
+	carl  = "carl"
+	olga  = "olga"
+)
+
+type testEnv struct {
+	srv     *Service
+	repo    *memory.Memory
+	metrics *countingMetrics
+}
+
+// newTestEnv builds a service on a seeded memory repository. The options
+// adjust the service before use, e.g. its configuration.
+func newTestEnv(t *testing.T, options ...func(*Service)) *testEnv {
+	t.Helper()
+
+	repo := memory.New()
+	if err := repo.Seed(context.Background(), fixture(), true); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	metrics := &countingMetrics{decisions: map[models.BidDecision]int{}}
+	srv := New(repo, metrics, &config.BidsConfig{Resubmission: config.ResubmissionNever}, &config.PlatformConfig{},
+		nil, nil, &config.StorageConfig{}, nil, log)
+	for _, option := range options {
+		option(srv)
+	}
+
+	return &testEnv{srv: srv, repo: repo, metrics: metrics}
+}
+
+func fixture() *seed.Dataset {
+	at := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+
+	employee := func(id, username string) models.Employee {
+		return models.Employee{ID: id, Username: username, CreatedAt: at, UpdatedAt: at}
+	}
+	responsible := func(id string, organizationID models.OrganizationID, userID string) seed.Responsible {
+		return seed.Responsible{ID: id, OrganizationID: organizationID, UserID: userID}
+	}
+
+	return &seed.Dataset{
+		Organizations: []models.Organization{
+			{ID: buyerOrg, Name: "Buyer", Type: models.OrganizationTypeLLC, CreatedAt: at, UpdatedAt: at},
+			{ID: supplierOrg, Name: "Supplier", Type: models.OrganizationTypeIE, CreatedAt: at, UpdatedAt: at},
+			{ID: thirdOrg, Name: "Third", Type: models.OrganizationTypeJSC, CreatedAt: at, UpdatedAt: at},
+		},
+		Employees: []models.Employee{
+			employee(aliceID, alice), employee(annaID, anna), employee(amyID, amy),
+			employee(bobID, bob), employee(bellaID, bella), employee(carlID, carl), employee(olgaID, olga),
+		},
+		Responsibles: []seed.Responsible{
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000001", buyerOrg, aliceID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000002", buyerOrg, annaID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000003", buyerOrg, amyID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000004", supplierOrg, bobID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000005", supplierOrg, bellaID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000006", thirdOrg, olgaID),
+			responsible("4f2b8e96-6d5c-4a5a-9d4c-000000000007", thirdOrg, bellaID),
+		},
+	}
+}
+
+// tender builds a tender of the buyer created by alice. It stays a draft
+// unless published.
+func (e *testEnv) tender(t *testing.T, edit func(*models.TenderCreate), published bool) *models.TenderResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	create := &models.TenderCreate{
+		Name: "Roof repair", Description: "Roof", ServiceType: models.TenderServiceTypeConstruction,
+		OrganizationID: buyerOrg, CreatorUsername: alice,
+	}
+	if edit != nil {
+		edit(create)
+	}
+
+	tender, err := e.srv.BuildTender(ctx, create)
+	noErr(t, err)
+
+	if published {
+		tender, err = e.srv.RefreshTenderStatus(ctx, tender.ID, alice, models.TenderStatusPublished)
+		noErr(t, err)
+	}
+
+	return tender
+}
+
+// bid makes a personal bid of the author, or one on behalf of the
+// organization when it is given.
+func (e *testEnv) bid(tenderID, authorID string, organizationID *models.OrganizationID) (*models.BidResponse, error) {
+	create := &models.BidCreate{
+		Name: "Offer", Description: "We do it", TenderID: tenderID,
+		AuthorType: models.BidAuthorTypeUser, AuthorId: authorID,
+	}
+	if organizationID != nil {
+		create.AuthorType, create.OrganizationID = models.BidAuthorTypeOrganization, organizationID
+	}
+
+	return e.srv.ConstructBid(context.Background(), create)
+}
+
+// publishedBid makes a personal bid and publishes it.
+func (e *testEnv) publishedBid(t *testing.T, tenderID, authorID, author string) *models.BidResponse {
+	t.Helper()
+
+	bid, err := e.bid(tenderID, authorID, nil)
+	noErr(t, err)
+
+	bid, err = e.srv.RenewStatusOfBid(context.Background(), bid.ID, author, &models.BidStatusPublished)
+	noErr(t, err)
+
+	return bid
+}
+
+func (e *testEnv) decide(t *testing.T, bidID, username string, decision models.BidDecision) *models.BidResponse {
+	t.Helper()
+
+	bid, err := e.srv.ApplyBidDecision(context.Background(), bidID, username, &decision)
+	noErr(t, err)
+
+	return bid
+}
+
+func (e *testEnv) tenderStatus(t *testing.T, tenderID string) models.TenderStatus {
+	t.Helper()
+
+	tender, err := e.srv.getTender(context.Background(), tenderID)
+	noErr(t, err)
+
+	return tender.Status
+}
+
+type countingMetrics struct {
+	published, created, awarded, cancelled, withdrawn int
+	decisions                                         map[models.BidDecision]int
+}
+
+func (m *countingMetrics) TenderPublished()                     { m.published++ }
+func (m *countingMetrics) BidCreated()                          { m.created++ }
+func (m *countingMetrics) DecisionApplied(d models.BidDecision) { m.decisions[d]++ }
+func (m *countingMetrics) TenderAwarded()                       { m.awarded++ }
+func (m *countingMetrics) TenderCancelled()                     { m.cancelled++ }
+func (m *countingMetrics) BidWithdrawn()                        { m.withdrawn++ }
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func expectErr(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -2,63 +2,207 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/DarRo9/Tenders/internal/repository"
 	"github.com/DarRo9/Tenders/models"
 )
 
-func (s *Service) RefreshTenderStatus(ctx context.Context, tenderID, username string, status models.TenderStatus) (*models.TenderResponse, error) {
+func (s *Service) RefreshTenderStatus(ctx context.Context, tenderID, username string, status models.TenderStatus) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "RefreshTenderStatus")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
 		return nil, err
 	}
 
-	return s.repo.RefreshTenderStatus(ctx, tenderID, status)
+	current, _, err := s.repo.GetStatusOfTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *current == models.TenderStatusCancelled {
+		return nil, repository.ErrTenderCancelled
+	}
+
+	tender, err := s.repo.RefreshTenderStatus(ctx, tenderID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publishing a published tender again is not another publication.
+	if status == models.TenderStatusPublished && *current != models.TenderStatusPublished {
+		s.metrics.TenderPublished()
+	}
+
+	return tender, nil
 }
 
-func (s *Service) ChangeTender(ctx context.Context, tenderID string, username string, tender *models.TenderEdit) (*models.TenderResponse, error) {
+func (s *Service) ChangeTender(ctx context.Context, tenderID string, username string, tender *models.TenderEdit) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "ChangeTender")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
 		return nil, err
 	}
 
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
+	if tender.ServiceType != nil {
+		current, err := s.getTender(ctx, tenderID)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.Auction != nil && *tender.ServiceType != models.TenderServiceTypeDelivery {
+			return nil, repository.ErrInvalidAuction
+		}
+
+		if current.Prequalification {
+			if err := s.checkQuestionnaire(ctx, current.OrganizationID, *tender.ServiceType); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return s.repo.UpdateTender(ctx, tenderID, tender)
 }
 
-func (s *Service) RollbackTender(ctx context.Context, tenderID string, version int32, username string) (*models.TenderResponse, error) {
+func (s *Service) RollbackTender(ctx context.Context, tenderID string, version int32, username string) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "RollbackTender")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
 		return nil, err
 	}
 
+	if err := s.checkTenderNotCancelled(ctx, tenderID); err != nil {
+		return nil, err
+	}
+
 	return s.repo.RollbackTender(ctx, tenderID, version)
 }
 
-func (s *Service) GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, limit, offset int32) ([]*models.TenderResponse, error) {
-	return s.repo.GetAllTenders(ctx, serviceType, limit, offset)
+// GetAllTenders lists the published tenders the user sees. Without a
+// username only the public ones are listed.
+func (s *Service) GetAllTenders(ctx context.Context, serviceType []models.TenderServiceType, username string, limit, offset int32) (_ []*models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "GetAllTenders")
+	defer func() { endSpan(span, err) }()
+
+	var userID string
+	if username != "" {
+		if userID, err = s.repo.ControlBidCreationByID(ctx, username); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetAllTenders(ctx, serviceType, userID, limit, offset)
 }
 
-func (s *Service) BuildTender(ctx context.Context, tender *models.TenderCreate) (*models.TenderResponse, error) {
-	err := s.repo.ControlOrganizationPermission(ctx, &tender.OrganizationID, tender.CreatorUsername)
-	if err != nil {
+func (s *Service) BuildTender(ctx context.Context, tender *models.TenderCreate) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "BuildTender")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlOrganizationPermission(ctx, &tender.OrganizationID, tender.CreatorUsername); err != nil {
 		return nil, err
 	}
 
-	return s.repo.BuildTender(ctx, tender)
+	if tender.SubmissionDeadline != nil && !tender.SubmissionDeadline.After(time.Now()) {
+		return nil, repository.ErrInvalidDeadline
+	}
+
+	if tender.Auction != nil && !validAuction(tender) {
+		return nil, repository.ErrInvalidAuction
+	}
+
+	if tender.Visibility == "" {
+		tender.Visibility = models.TenderVisibilityPublic
+	}
+
+	if err := checkInvitations(tender.Visibility, tender.OrganizationID, tender.InvitedOrganizations); err != nil {
+		return nil, err
+	}
+
+	if tender.Prequalification {
+		if err := s.checkQuestionnaire(ctx, tender.OrganizationID, tender.ServiceType); err != nil {
+			return nil, err
+		}
+	}
+
+	var wrappedKey []byte
+	if tender.Sealed {
+		if s.sealer == nil {
+			return nil, repository.ErrSealingDisabled
+		}
+
+		if wrappedKey, err = s.sealer.NewKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.BuildTender(ctx, tender, wrappedKey)
 }
 
-func (s *Service) GetUserTenders(ctx context.Context, username string, limit, offset int32) ([]*models.TenderResponse, error) {
-	_, err := s.repo.ControlBidCreationByID(ctx, username)
-	if err != nil {
+func (s *Service) GetUserTenders(ctx context.Context, username string, limit, offset int32) (_ []*models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "GetUserTenders")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.repo.ControlBidCreationByID(ctx, username); err != nil {
 		return nil, err
 	}
 	return s.repo.GetUserTenders(ctx, username, limit, offset)
 }
 
-func (s *Service) GetStatusOfTender(ctx context.Context, tenderID, username string) (*models.TenderStatus, error) {
-	status, organizationID, err := s.repo.GetStatusOfTender(ctx, tenderID)
+// GetStatusOfTender follows checkTenderReadable: the responsibles read the
+// status in any state, everybody who sees the tender once it is published.
+func (s *Service) GetStatusOfTender(ctx context.Context, tenderID, username string) (_ *models.TenderStatus, err error) {
+	ctx, span := startSpan(ctx, "GetStatusOfTender")
+	defer func() { endSpan(span, err) }()
+
+	status, _, err := s.repo.GetStatusOfTender(ctx, tenderID)
 	if err != nil {
 		return nil, err
 	}
-	if err := s.repo.ControlOrganizationPermission(ctx, organizationID, username); err != nil {
+	if _, err := s.checkTenderReadable(ctx, tenderID, username); err != nil {
 		return nil, err
 	}
 
 	return status, nil
-}
\ No newline at end of file
+}
+
+// CancelTender ends a tender without an award. Open bids on it move to
+// TenderCancelled and their authors are notified.
+func (s *Service) CancelTender(ctx context.Context, tenderID, username, reason string) (_ *models.TenderResponse, err error) {
+	ctx, span := startSpan(ctx, "CancelTender")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.ControlTendersCreationByName(ctx, tenderID, username); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.repo.CancelTender(ctx, tenderID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.TenderCancelled()
+
+	return tender, nil
+}
+
+// checkTenderNotCancelled keeps a cancelled tender final: it can be neither
+// reopened, edited nor rolled back to a version from before the cancellation.
+func (s *Service) checkTenderNotCancelled(ctx context.Context, tenderID string) error {
+	status, _, err := s.repo.GetStatusOfTender(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	if *status == models.TenderStatusCancelled {
+		return repository.ErrTenderCancelled
+	}
+
+	return nil
+}
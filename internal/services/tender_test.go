@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DarRo9/Tenders/internal/repository"
+	"github.com/DarRo9/Tenders/models"
+)
+
+func TestRefreshTenderStatusCountsPublications(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	tender := env.tender(t, nil, true)
+	if env.metrics.published != 1 {
+		t.Fatalf("one publication expected, got %d", env.metrics.published)
+	}
+
+	_, err := env.srv.RefreshTenderStatus(ctx, tender.ID, alice, models.TenderStatusPublished)
+	noErr(t, err)
+	if env.metrics.published != 1 {
+		t.Fatalf("publishing again must not count, got %d", env.metrics.published)
+	}
+
+	_, err = env.srv.RefreshTenderStatus(ctx, tender.ID, alice, models.TenderStatusClosed)
+	noErr(t, err)
+	_, err = env.srv.RefreshTenderStatus(ctx, tender.ID, alice, models.TenderStatusPublished)
+	noErr(t, err)
+	if env.metrics.published != 2 {
+		t.Fatalf("a reopened tender counts again, got %d", env.metrics.published)
+	}
+
+	_, err = env.srv.CancelTender(ctx, tender.ID, alice, "Budget cut")
+	noErr(t, err)
+	_, err = env.srv.RefreshTenderStatus(ctx, tender.ID, alice, models.TenderStatusPublished)
+	expectErr(t, err, repository.ErrTenderCancelled)
+	if env.metrics.published != 2 {
+		t.Fatalf("a cancelled tender is not published, got %d", env.metrics.published)
+	}
+}
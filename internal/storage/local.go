@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local keeps every blob in a file of its own below a directory.
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("storage: create %s: %w", dir, err)
+	}
+
+	return &Local{dir: dir}, nil
+}
+
+// Put writes to a temporary file first, so that a failed upload never
+// leaves a partial blob behind.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	written, err := io.Copy(f, r)
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("storage: got %d bytes, want %d", written, size)
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path spreads the blobs over subdirectories named after the first
+// characters of their keys.
+func (l *Local) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+
+	return filepath.Join(l.dir, key[:2], key), nil
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newLocal(t *testing.T) *Local {
+	t.Helper()
+
+	local, err := NewLocal(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+
+	return local
+}
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local := newLocal(t)
+	const key, content = "f3a9c2e1", "%PDF-1.7 offer"
+
+	if err := local.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(local.dir, "f3", key)); err != nil {
+		t.Fatalf("the blob must be stored below the directory of its prefix: %v", err)
+	}
+
+	r, err := local.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(got) != content {
+		t.Fatalf("got %q, %v, want %q", got, err, content)
+	}
+
+	if err := local.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := local.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("a deleted blob must be gone, got %v", err)
+	}
+	if err := local.Delete(ctx, key); err != nil {
+		t.Fatalf("deleting a missing blob must succeed, got %v", err)
+	}
+}
+
+func TestLocalPutShortBody(t *testing.T) {
+	ctx := context.Background()
+	local := newLocal(t)
+
+	if err := local.Put(ctx, "c0ffee", strings.NewReader("short"), 100, "text/plain"); err == nil {
+		t.Fatal("a body shorter than its size must fail")
+	}
+
+	if _, err := local.Get(ctx, "c0ffee"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("a failed upload must leave no blob, got %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(local.dir, "c0"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("a failed upload must leave no temporary file, got %v", entries)
+	}
+}
+
+func TestLocalGetError(t *testing.T) {
+	local := newLocal(t)
+
+	// A file where the directory of the prefix belongs fails with something
+	// other than not found.
+	if err := os.WriteFile(filepath.Join(local.dir, "ab"), nil, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	r, err := local.Get(context.Background(), "abcdef")
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want an error other than not found", err)
+	}
+	if r != nil {
+		t.Fatalf("no reader expected along with an error, got %#v", r)
+	}
+}
+
+func TestLocalInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	local := newLocal(t)
+	outside := filepath.Join(filepath.Dir(local.dir), "outside")
+
+	for _, key := range []string{"", "ab", "../outside", "..outside", "ab/../../outside", `ab\..\outside`, "abc.def", "/etc/passwd"} {
+		t.Run(key, func(t *testing.T) {
+			if err := local.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Fatal("put must reject the key")
+			}
+			if _, err := local.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+				t.Fatalf("get must reject the key, got %v", err)
+			}
+			if err := local.Delete(ctx, key); err == nil {
+				t.Fatal("delete must reject the key")
+			}
+		})
+	}
+
+	if _, err := os.Stat(outside); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("nothing may be written outside the directory, got %v", err)
+	}
+}
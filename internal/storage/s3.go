@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+// unsignedPayload lets uploads stream: the body is not part of the
+// signature, its integrity is checked against the SHA-256 kept with the
+// attachment.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3 keeps the blobs as objects of a bucket on an S3-compatible service.
+// Requests are signed with AWS Signature Version 4.
+type S3 struct {
+	client    *http.Client
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+}
+
+func NewS3(cfg *config.S3Config) (*S3, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage: parse s3 endpoint: %w", err)
+	}
+
+	return &S3{
+		client:    &http.Client{},
+		endpoint:  endpoint,
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		pathStyle: cfg.PathStyle,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.request(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends the request. Any status but 2xx is an error, 404 is
+// ErrNotFound.
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, fmt.Errorf("storage: s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, message)
+}
+
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	scope, signature := s.signature(amzDate, canonicalRequest(req, amzDate))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+
+func canonicalRequest(req *http.Request, amzDate string) string {
+	return strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+}
+
+// signature signs the canonical request with the key derived for the day of
+// amzDate and returns the credential scope along with it.
+func (s *S3) signature(amzDate, canonicalRequest string) (scope, signature string) {
+	date := amzDate[:8]
+	scope = date + "/" + s.region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	return scope, hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,36 @@
+// Package storage keeps the contents of attachments outside the database.
+// The files are addressed by a key the caller chooses and never changes.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/DarRo9/Tenders/internal/config"
+)
+
+var ErrNotFound = errors.New("storage: blob not found")
+
+type BlobStore interface {
+	// Put streams size bytes from r into the blob under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob under key, ErrNotFound when there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns nil with the none driver, which disables attachments.
+func New(cfg *config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case config.StorageNone:
+		return nil, nil
+	case config.StorageLocal:
+		return NewLocal(cfg.Dir)
+	case config.StorageS3:
+		return NewS3(&cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
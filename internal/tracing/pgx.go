@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const pgxInstrumentation = "github.com/DarRo9/Tenders/internal/repository/postgres"
+
+// QueryTracer is a pgx hook that wraps every query in a span carrying the
+// SQL text.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer(pgxInstrumentation)}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
+
+	ctx, _ = t.tracer.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(data.SQL),
+			semconv.DBOperationName(operation),
+		),
+	)
+
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+}
+
+// queryOperation returns the first keyword of the statement, which is enough
+// to tell reads from writes in the span name.
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/DarRo9/Tenders/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const ServiceName = "tenders"
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,28 @@
+DROP TABLE IF EXISTS notification;
+
+ALTER TABLE tender_version DROP COLUMN IF EXISTS cancellation_reason;
+ALTER TABLE tender DROP COLUMN IF EXISTS cancellation_reason;
+
+-- Enum values cannot be dropped, the types are recreated without them.
+UPDATE tender SET status = 'Closed' WHERE status = 'Cancelled';
+UPDATE tender_version SET status = 'Closed' WHERE status = 'Cancelled';
+UPDATE bid SET status = 'Canceled' WHERE status = 'TenderCancelled';
+UPDATE bid_version SET status = 'Canceled' WHERE status = 'TenderCancelled';
+
+ALTER TYPE tender_status RENAME TO tender_status_old;
+CREATE TYPE tender_status AS ENUM ('Created', 'Published', 'Closed');
+ALTER TABLE tender ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tender ALTER COLUMN status TYPE tender_status USING status::text::tender_status;
+ALTER TABLE tender ALTER COLUMN status SET DEFAULT 'Created';
+ALTER TABLE tender_version ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tender_version ALTER COLUMN status TYPE tender_status USING status::text::tender_status;
+ALTER TABLE tender_version ALTER COLUMN status SET DEFAULT 'Created';
+DROP TYPE tender_status_old;
+
+ALTER TYPE bid_status RENAME TO bid_status_old;
+CREATE TYPE bid_status AS ENUM ('Created', 'Published', 'Canceled', 'Approved', 'Rejected');
+ALTER TABLE bid ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE bid ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+ALTER TABLE bid ALTER COLUMN status SET DEFAULT 'Created';
+ALTER TABLE bid_version ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+DROP TYPE bid_status_old;
@@ -0,0 +1,20 @@
+ALTER TYPE tender_status ADD VALUE IF NOT EXISTS 'Cancelled';
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'TenderCancelled';
+
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT CHECK (LENGTH(cancellation_reason) <= 500);
+
+ALTER TABLE tender_version
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT CHECK (LENGTH(cancellation_reason) <= 500);
+
+CREATE TABLE IF NOT EXISTS notification (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipient_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    tender_id UUID REFERENCES tender(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notification_recipient_idx ON notification (recipient_id, created_at);
@@ -0,0 +1,30 @@
+DROP INDEX IF EXISTS bid_live_author_idx;
+
+-- Resubmitted bids cannot coexist with the unique constraint, only the latest
+-- bid of every author is kept.
+DELETE FROM bid b
+WHERE EXISTS (
+    SELECT 1
+    FROM bid newer
+    WHERE newer.tender_id = b.tender_id
+        AND newer.author_id = b.author_id
+        AND newer.created_at > b.created_at
+);
+
+ALTER TABLE bid ADD CONSTRAINT bid_tender_id_author_id_key UNIQUE (tender_id, author_id);
+
+ALTER TABLE bid_decision DROP COLUMN IF EXISTS invalidated_at;
+ALTER TABLE bid_version DROP COLUMN IF EXISTS withdrawal_reason;
+ALTER TABLE bid DROP COLUMN IF EXISTS withdrawal_reason;
+
+-- Enum values cannot be dropped, the type is recreated without it.
+UPDATE bid SET status = 'Canceled' WHERE status = 'Withdrawn';
+UPDATE bid_version SET status = 'Canceled' WHERE status = 'Withdrawn';
+
+ALTER TYPE bid_status RENAME TO bid_status_old;
+CREATE TYPE bid_status AS ENUM ('Created', 'Published', 'Canceled', 'Approved', 'Rejected', 'TenderCancelled');
+ALTER TABLE bid ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE bid ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+ALTER TABLE bid ALTER COLUMN status SET DEFAULT 'Created';
+ALTER TABLE bid_version ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+DROP TYPE bid_status_old;
@@ -0,0 +1,17 @@
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Withdrawn';
+
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS withdrawal_reason TEXT CHECK (LENGTH(withdrawal_reason) <= 500);
+
+ALTER TABLE bid_version
+    ADD COLUMN IF NOT EXISTS withdrawal_reason TEXT CHECK (LENGTH(withdrawal_reason) <= 500);
+
+ALTER TABLE bid_decision
+    ADD COLUMN IF NOT EXISTS invalidated_at TIMESTAMPTZ;
+
+-- An author keeps at most one live bid per tender, closed bids no longer
+-- block a resubmission. Whether one is allowed is decided by the service.
+ALTER TABLE bid DROP CONSTRAINT IF EXISTS bid_tender_id_author_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_live_author_idx ON bid (tender_id, author_id)
+    WHERE status IN ('Created', 'Published', 'Approved');
@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS bid_live_organization_idx;
+DROP INDEX IF EXISTS bid_live_author_idx;
+
+ALTER TABLE bid DROP CONSTRAINT IF EXISTS bid_organization_author_check;
+
+ALTER TABLE bid_version DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE bid DROP COLUMN IF EXISTS organization_id;
+
+-- An author may hold a user bid and an organization bid on the same tender,
+-- only the oldest of them is kept.
+DELETE FROM bid b
+WHERE status IN ('Created', 'Published', 'Approved')
+    AND EXISTS (
+        SELECT 1
+        FROM bid older
+        WHERE older.tender_id = b.tender_id
+            AND older.author_id = b.author_id
+            AND older.status IN ('Created', 'Published', 'Approved')
+            AND (older.created_at, older.id) < (b.created_at, b.id)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_live_author_idx ON bid (tender_id, author_id)
+    WHERE status IN ('Created', 'Published', 'Approved');
@@ -0,0 +1,48 @@
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organization(id) ON DELETE CASCADE;
+
+ALTER TABLE bid_version
+    ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organization(id) ON DELETE CASCADE;
+
+-- Organization bids used to reference the author only. They are attributed
+-- to an organization of the author, the rest become user bids.
+UPDATE bid b
+SET organization_id = (
+    SELECT orr.organization_id
+    FROM organization_responsible orr
+    WHERE orr.user_id = b.author_id
+    ORDER BY orr.organization_id
+    LIMIT 1
+)
+WHERE author_type = 'Organization';
+
+UPDATE bid b
+SET author_type = 'User', organization_id = NULL
+WHERE organization_id IS NULL
+    OR status IN ('Created', 'Published', 'Approved')
+    AND EXISTS (
+        SELECT 1
+        FROM bid older
+        WHERE older.tender_id = b.tender_id
+            AND older.organization_id = b.organization_id
+            AND older.status IN ('Created', 'Published', 'Approved')
+            AND (older.created_at, older.id) < (b.created_at, b.id)
+    );
+
+UPDATE bid_version bv
+SET author_type = b.author_type, organization_id = b.organization_id
+FROM bid b
+WHERE b.id = bv.bid_id;
+
+ALTER TABLE bid ADD CONSTRAINT bid_organization_author_check
+    CHECK ((author_type = 'Organization') = (organization_id IS NOT NULL));
+
+-- A live bid is unique per author for user bids and per organization for
+-- organization bids.
+DROP INDEX IF EXISTS bid_live_author_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_live_author_idx ON bid (tender_id, author_id)
+    WHERE organization_id IS NULL AND status IN ('Created', 'Published', 'Approved');
+
+CREATE UNIQUE INDEX IF NOT EXISTS bid_live_organization_idx ON bid (tender_id, organization_id)
+    WHERE organization_id IS NOT NULL AND status IN ('Created', 'Published', 'Approved');
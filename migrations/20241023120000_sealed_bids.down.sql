@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS audit_log;
+
+-- Bids of tenders that were never revealed lose their contents.
+ALTER TABLE bid_version DROP COLUMN IF EXISTS sealed_content;
+ALTER TABLE bid DROP COLUMN IF EXISTS sealed_content;
+
+DROP TABLE IF EXISTS tender_key;
+
+ALTER TABLE tender_version
+    DROP COLUMN IF EXISTS submission_deadline,
+    DROP COLUMN IF EXISTS sealed;
+
+ALTER TABLE tender DROP CONSTRAINT IF EXISTS tender_sealed_deadline_check;
+
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS revealed_at,
+    DROP COLUMN IF EXISTS submission_deadline,
+    DROP COLUMN IF EXISTS sealed;
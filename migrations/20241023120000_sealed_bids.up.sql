@@ -0,0 +1,35 @@
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS revealed_at TIMESTAMPTZ;
+
+ALTER TABLE tender ADD CONSTRAINT tender_sealed_deadline_check
+    CHECK (NOT sealed OR submission_deadline IS NOT NULL);
+
+ALTER TABLE tender_version
+    ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ;
+
+-- The data key of a sealed tender, wrapped by the master key.
+CREATE TABLE IF NOT EXISTS tender_key (
+    tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The encrypted name and description of a bid on a sealed tender. The plain
+-- columns stay empty until the reveal.
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS sealed_content BYTEA;
+ALTER TABLE bid_version ADD COLUMN IF NOT EXISTS sealed_content BYTEA;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    tender_id UUID REFERENCES tender(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_tender_idx ON audit_log (tender_id, created_at);
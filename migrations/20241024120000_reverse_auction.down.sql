@@ -0,0 +1,16 @@
+ALTER TABLE bid_version
+    DROP COLUMN IF EXISTS priced_at,
+    DROP COLUMN IF EXISTS price;
+
+ALTER TABLE bid
+    DROP COLUMN IF EXISTS priced_at,
+    DROP COLUMN IF EXISTS price;
+
+DROP INDEX IF EXISTS tender_auction_deadline_idx;
+
+ALTER TABLE tender DROP CONSTRAINT IF EXISTS tender_auction_check;
+
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS auction_extension_seconds,
+    DROP COLUMN IF EXISTS auction_step,
+    DROP COLUMN IF EXISTS auction_start;
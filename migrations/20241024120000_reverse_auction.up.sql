@@ -0,0 +1,25 @@
+-- A reverse auction runs from auction_start until submission_deadline, which
+-- late price drops push back by auction_extension_seconds.
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS auction_start TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS auction_step NUMERIC(14, 2) CHECK (auction_step > 0),
+    ADD COLUMN IF NOT EXISTS auction_extension_seconds INT CHECK (auction_extension_seconds >= 0);
+
+ALTER TABLE tender ADD CONSTRAINT tender_auction_check
+    CHECK (
+        (auction_start IS NULL AND auction_step IS NULL AND auction_extension_seconds IS NULL)
+        OR (auction_start IS NOT NULL AND auction_step IS NOT NULL AND auction_extension_seconds IS NOT NULL
+            AND submission_deadline > auction_start AND NOT sealed)
+    );
+
+CREATE INDEX IF NOT EXISTS tender_auction_deadline_idx ON tender (submission_deadline)
+    WHERE auction_start IS NOT NULL AND status = 'Published';
+
+-- Every price drop is a new version of the bid.
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS price NUMERIC(14, 2) CHECK (price > 0),
+    ADD COLUMN IF NOT EXISTS priced_at TIMESTAMPTZ;
+
+ALTER TABLE bid_version
+    ADD COLUMN IF NOT EXISTS price NUMERIC(14, 2),
+    ADD COLUMN IF NOT EXISTS priced_at TIMESTAMPTZ;
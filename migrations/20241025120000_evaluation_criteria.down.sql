@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bid_score;
+
+DROP TABLE IF EXISTS tender_criterion;
@@ -0,0 +1,23 @@
+-- The criteria a tender is evaluated on. The weights of a tender add up to
+-- 100 percent, which the service enforces.
+CREATE TABLE IF NOT EXISTS tender_criterion (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    weight INT NOT NULL CHECK (weight BETWEEN 1 AND 100),
+    position INT NOT NULL,
+    UNIQUE (tender_id, name),
+    UNIQUE (tender_id, position)
+);
+
+-- The score, from 0 to 10, a responsible gives a bid on one criterion.
+CREATE TABLE IF NOT EXISTS bid_score (
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES tender_criterion(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    score INT NOT NULL CHECK (score BETWEEN 0 AND 10),
+    scored_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bid_id, criterion_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS bid_score_criterion_idx ON bid_score (criterion_id);
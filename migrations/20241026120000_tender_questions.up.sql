@@ -0,0 +1,18 @@
+-- Clarification questions suppliers ask about a tender. An answer is either
+-- public, with the asker kept anonymous, or private to the asker.
+CREATE TABLE IF NOT EXISTS tender_question (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    asker_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    question TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    answer TEXT,
+    visibility VARCHAR(10) CHECK (visibility IN ('Public', 'Private')),
+    answered_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    answered_at TIMESTAMPTZ,
+    -- The tender version an answer amended the tender to.
+    amended_version INT,
+    CHECK ((answer IS NULL) = (visibility IS NULL) AND (answer IS NULL) = (answered_at IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS tender_question_tender_idx ON tender_question (tender_id, created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS bid_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    description TEXT CHECK (LENGTH(description) <= 500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Only the buyer side of a thread fits the old one-way feedback.
+INSERT INTO bid_feedback (id, bid_id, description, created_at)
+SELECT id, bid_id, LEFT(body, 500), created_at
+FROM bid_message
+    WHERE role = 'Buyer';
+
+DROP TABLE IF EXISTS bid_message_read;
+
+DROP TABLE IF EXISTS bid_message_attachment;
+
+DROP TABLE IF EXISTS bid_message;
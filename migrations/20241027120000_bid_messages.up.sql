@@ -0,0 +1,36 @@
+-- A negotiation thread per bid between the responsibles of the buyer and the
+-- author of the bid. It replaces bid_feedback, whose entries become buyer
+-- messages without an author.
+CREATE TABLE IF NOT EXISTS bid_message (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('Buyer', 'Bidder')),
+    reply_to UUID REFERENCES bid_message(id) ON DELETE SET NULL,
+    body TEXT NOT NULL CHECK (LENGTH(body) <= 2000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS bid_message_bid_idx ON bid_message (bid_id, created_at);
+
+CREATE TABLE IF NOT EXISTS bid_message_attachment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL REFERENCES bid_message(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    position INT NOT NULL,
+    UNIQUE (message_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS bid_message_read (
+    message_id UUID NOT NULL REFERENCES bid_message(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    read_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+INSERT INTO bid_message (id, bid_id, role, body, created_at)
+SELECT id, bid_id, 'Buyer', COALESCE(description, ''), created_at
+FROM bid_feedback;
+
+DROP TABLE IF EXISTS bid_feedback;
@@ -0,0 +1,6 @@
+ALTER TABLE bid_version DROP COLUMN IF EXISTS attachment_ids;
+ALTER TABLE bid DROP COLUMN IF EXISTS attachment_ids;
+ALTER TABLE tender_version DROP COLUMN IF EXISTS attachment_ids;
+ALTER TABLE tender DROP COLUMN IF EXISTS attachment_ids;
+
+DROP TABLE IF EXISTS attachment;
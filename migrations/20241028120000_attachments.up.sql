@@ -0,0 +1,23 @@
+-- Files of tenders and bids. The contents live in the blob store under
+-- storage_key, rows are never deleted so that older versions keep their
+-- files.
+CREATE TABLE IF NOT EXISTS attachment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    -- NULL for the attachments of the tender itself.
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    uploaded_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The attachments of every version, so that a rollback restores them with
+-- the rest of the version.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS attachment_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE tender_version ADD COLUMN IF NOT EXISTS attachment_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS attachment_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE bid_version ADD COLUMN IF NOT EXISTS attachment_ids UUID[] NOT NULL DEFAULT '{}';
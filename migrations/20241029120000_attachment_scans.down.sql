@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS attachment_quarantined_idx;
+
+ALTER TABLE attachment DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE attachment DROP COLUMN IF EXISTS scan_signature;
+ALTER TABLE attachment DROP COLUMN IF EXISTS scan_status;
@@ -0,0 +1,9 @@
+-- Attachments stay in quarantine until a malware scan declares them clean.
+-- The files uploaded before scanning existed are scanned as well.
+ALTER TABLE attachment ADD COLUMN IF NOT EXISTS scan_status VARCHAR(11) NOT NULL DEFAULT 'Quarantined'
+    CHECK (scan_status IN ('Quarantined', 'Clean', 'Infected', 'Failed'));
+-- The name of the threat found, or why the file could not be scanned.
+ALTER TABLE attachment ADD COLUMN IF NOT EXISTS scan_signature TEXT NOT NULL DEFAULT '';
+ALTER TABLE attachment ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS attachment_quarantined_idx ON attachment (created_at) WHERE scan_status = 'Quarantined';
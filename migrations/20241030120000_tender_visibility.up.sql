@@ -0,0 +1,13 @@
+-- Who besides the responsibles of its organization sees a tender and may
+-- bid on it: everybody, the invited organizations or nobody.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS visibility VARCHAR(10) NOT NULL DEFAULT 'Public'
+    CHECK (visibility IN ('Public', 'Invitation', 'Internal'));
+
+CREATE TABLE IF NOT EXISTS tender_invitation (
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    invited_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tender_id, organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS tender_invitation_organization_idx ON tender_invitation (organization_id);
@@ -0,0 +1,14 @@
+DELETE FROM attachment WHERE application_id IS NOT NULL;
+ALTER TABLE attachment DROP CONSTRAINT IF EXISTS attachment_owner_check;
+ALTER TABLE attachment DROP COLUMN IF EXISTS application_id;
+ALTER TABLE attachment ALTER COLUMN tender_id SET NOT NULL;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS prequalification;
+
+DROP TABLE IF EXISTS prequalification_answer;
+
+DROP TABLE IF EXISTS prequalification_application;
+
+DROP TABLE IF EXISTS prequalification_question;
+
+DROP TABLE IF EXISTS prequalification_questionnaire;
@@ -0,0 +1,61 @@
+-- The questionnaire an organization has suppliers answer before they may bid
+-- on its tenders of one service type.
+CREATE TABLE IF NOT EXISTS prequalification_questionnaire (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    service_type service_type NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, service_type)
+);
+
+CREATE TABLE IF NOT EXISTS prequalification_question (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    questionnaire_id UUID NOT NULL REFERENCES prequalification_questionnaire(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    text TEXT NOT NULL,
+    answer_type VARCHAR(7) NOT NULL CHECK (answer_type IN ('Text', 'Number', 'Boolean', 'Date')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (questionnaire_id, position)
+);
+
+-- An application of a supplier organization. An approval holds until
+-- valid_until unless it is revoked before.
+CREATE TABLE IF NOT EXISTS prequalification_application (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    questionnaire_id UUID NOT NULL REFERENCES prequalification_questionnaire(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    submitted_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(9) NOT NULL DEFAULT 'Submitted'
+        CHECK (status IN ('Submitted', 'Approved', 'Rejected', 'Revoked')),
+    reviewed_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    review_comment TEXT NOT NULL DEFAULT '',
+    valid_until TIMESTAMPTZ,
+    revoked_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ,
+    revocation_reason TEXT NOT NULL DEFAULT ''
+);
+
+-- One application under review per organization and questionnaire.
+CREATE UNIQUE INDEX IF NOT EXISTS prequalification_application_pending_idx
+    ON prequalification_application (questionnaire_id, organization_id) WHERE status = 'Submitted';
+
+CREATE INDEX IF NOT EXISTS prequalification_application_organization_idx ON prequalification_application (organization_id);
+
+CREATE TABLE IF NOT EXISTS prequalification_answer (
+    application_id UUID NOT NULL REFERENCES prequalification_application(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES prequalification_question(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    PRIMARY KEY (application_id, question_id)
+);
+
+-- Tenders of a service type the organization has a questionnaire for may
+-- admit approved suppliers only.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS prequalification BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The licenses of an application are attachments without a tender.
+ALTER TABLE attachment ALTER COLUMN tender_id DROP NOT NULL;
+ALTER TABLE attachment ADD COLUMN IF NOT EXISTS application_id UUID REFERENCES prequalification_application(id) ON DELETE CASCADE;
+ALTER TABLE attachment ADD CONSTRAINT attachment_owner_check CHECK ((tender_id IS NULL) <> (application_id IS NULL));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS audit_log_debarment_idx;
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS debarment_id;
+
+DROP TABLE IF EXISTS debarment;
@@ -0,0 +1,30 @@
+-- A debarment blocks a supplier organization from bidding, either on the
+-- tenders of one organization or, without an organization, on all tenders.
+-- It is in force from valid_from until valid_until, or for good without one,
+-- unless it is lifted before.
+CREATE TABLE IF NOT EXISTS debarment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    scope VARCHAR(12) NOT NULL CHECK (scope IN ('Organization', 'Global')),
+    organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_until TIMESTAMPTZ,
+    created_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lifted_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+    lifted_at TIMESTAMPTZ,
+    lift_reason TEXT NOT NULL DEFAULT '',
+    CONSTRAINT debarment_scope_check CHECK ((scope = 'Global') = (organization_id IS NULL)),
+    CONSTRAINT debarment_period_check CHECK (valid_until IS NULL OR valid_until > valid_from),
+    CONSTRAINT debarment_supplier_check CHECK (supplier_id <> organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS debarment_supplier_idx ON debarment (supplier_id);
+CREATE INDEX IF NOT EXISTS debarment_organization_idx ON debarment (organization_id);
+
+-- Every change of a debarment is audited.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS debarment_id UUID REFERENCES debarment(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS audit_log_debarment_idx ON audit_log (debarment_id, created_at);
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations into the binary, so that the
+// service does not depend on the migrations directory being present at runtime.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// ScanStatus is the outcome of the malware scan of an attachment.
+type ScanStatus string
+
+const (
+	// ScanStatusQuarantined files wait for their scan and cannot be
+	// downloaded yet.
+	ScanStatusQuarantined ScanStatus = "Quarantined"
+	ScanStatusClean       ScanStatus = "Clean"
+	ScanStatusInfected    ScanStatus = "Infected"
+	// ScanStatusFailed files could not be scanned, e.g. because their
+	// contents are gone from the blob store.
+	ScanStatusFailed ScanStatus = "Failed"
+)
+
+// Attachment is a file of a tender, or of a bid when BidID is set, or a
+// license of a prequalification application when ApplicationID is. The
+// contents are kept in the blob store under StorageKey.
+type Attachment struct {
+	ID            string     `json:"id"`
+	TenderID      string     `json:"tenderId,omitempty"`
+	BidID         *string    `json:"bidId,omitempty"`
+	ApplicationID *string    `json:"applicationId,omitempty"`
+	Name          string     `json:"name"`
+	ContentType   string     `json:"contentType"`
+	Size          int64      `json:"size"`
+	SHA256        string     `json:"sha256"`
+	StorageKey    string     `json:"-"`
+	UploadedBy    string     `json:"uploadedBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ScanStatus    ScanStatus `json:"scanStatus"`
+	// ScanSignature names the threat of an infected file or the reason the
+	// scan failed.
+	ScanSignature string     `json:"scanSignature,omitempty"`
+	ScannedAt     *time.Time `json:"scannedAt,omitempty"`
+}
+
+// AttachmentScan is the outcome of a scan recorded on an attachment.
+type AttachmentScan struct {
+	Status    ScanStatus
+	Signature string
+}
+
+// AttachmentUpload describes a file streamed in the body of an upload.
+type AttachmentUpload struct {
+	Name        string
+	ContentType string
+	Size        int64
+}
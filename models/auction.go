@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Auction runs a tender as a timed reverse auction. It ends at the
+// SubmissionDeadline of the tender, which a price drop within
+// ExtensionSeconds of the end pushes back by ExtensionSeconds.
+type Auction struct {
+	Start time.Time `json:"start" binding:"required"`
+	// Step is the minimum amount a bid has to lower its price by.
+	Step             float64 `json:"step" binding:"required,gt=0"`
+	ExtensionSeconds int32   `json:"extensionSeconds" binding:"min=0,max=3600"`
+}
+
+type BidPrice struct {
+	Price float64 `json:"price" binding:"required,gt=0"`
+}
+
+// AuctionRank is a position in the current ranking of an auction. Rankings
+// are anonymous, only the bids of the requester are marked as own.
+type AuctionRank struct {
+	Rank     int       `json:"rank"`
+	Price    float64   `json:"price"`
+	PricedAt time.Time `json:"pricedAt"`
+	Own      bool      `json:"own"`
+}
+
+// AuctionResult is an auction closed by the closer. BidID is empty when
+// nobody bid.
+type AuctionResult struct {
+	TenderID string
+	BidID    string
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionTenderRevealed AuditAction = "TenderRevealed"
+	AuditActionAuctionClosed  AuditAction = "AuctionClosed"
+	AuditActionTenderAwarded  AuditAction = "TenderAwarded"
+
+	AuditActionDebarmentCreated AuditAction = "DebarmentCreated"
+	AuditActionDebarmentChanged AuditAction = "DebarmentChanged"
+	AuditActionDebarmentLifted  AuditAction = "DebarmentLifted"
+)
+
+type AuditRecord struct {
+	ID          string      `json:"id"`
+	ActorID     *string     `json:"actorId,omitempty"`
+	Action      AuditAction `json:"action"`
+	TenderID    string      `json:"tenderId,omitempty"`
+	BidID       string      `json:"bidId,omitempty"`
+	DebarmentID string      `json:"debarmentId,omitempty"`
+	Details     string      `json:"details,omitempty"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
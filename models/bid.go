@@ -21,6 +21,12 @@ var (
 	BidStatusCanceled  BidStatus = "Canceled"
 	BidStatusApproved  BidStatus = "Approved"
 	BidStatusRejected  BidStatus = "Rejected"
+	// BidStatusTenderCancelled is the outcome of the bids that were still
+	// open when their tender was cancelled.
+	BidStatusTenderCancelled BidStatus = "TenderCancelled"
+	// BidStatusWithdrawn is set by the author who takes the bid back before
+	// the buyer has made a final decision on it.
+	BidStatusWithdrawn BidStatus = "Withdrawn"
 )
 
 type BidDecision string
@@ -30,6 +36,16 @@ var (
 	BidDecisionRejected BidDecision = "Rejected"
 )
 
+type BidDecisionResponse struct {
+	ID       string      `json:"id"`
+	BidID    string      `json:"bidId"`
+	UserID   string      `json:"userId"`
+	Decision BidDecision `json:"decision"`
+	// InvalidatedAt is set when the bid was withdrawn after the decision had
+	// been cast, such a decision no longer counts towards the quorum.
+	InvalidatedAt *time.Time `json:"invalidatedAt,omitempty"`
+}
+
 type BidAuthorType string
 
 const (
@@ -43,6 +59,11 @@ type BidCreate struct {
 	TenderID    string        `json:"tenderId" binding:"required,max=100,uuid"`
 	AuthorType  BidAuthorType `json:"authorType" binding:"required,oneof=Organization User"`
 	AuthorId    string        `json:"authorId" binding:"required,max=100,uuid"`
+	// OrganizationID is the organization the bid is made on behalf of, the
+	// author must be one of its responsibles.
+	OrganizationID *OrganizationID `json:"organizationId" binding:"required_if=AuthorType Organization,excluded_if=AuthorType User,omitempty,max=100,uuid"`
+	// Price is required on auction tenders.
+	Price *float64 `json:"price" binding:"omitempty,gt=0"`
 }
 
 type BidEdit struct {
@@ -58,8 +79,41 @@ type BidResponse struct {
 	TenderID    string        `json:"tenderId"`
 	AuthorType  BidAuthorType `json:"authorType"`
 	AuthorID    string        `json:"authorId"`
+	// OrganizationID is set for the bids with the Organization author type.
+	OrganizationID *OrganizationID `json:"organizationId,omitempty"`
 	Version     int           `json:"version"`
 	CreatedAt   time.Time     `json:"createdAt"`
+	// WithdrawalReason is set once the bid is Withdrawn.
+	WithdrawalReason string `json:"withdrawalReason,omitempty"`
+	// Sealed bids have an empty name and description until their tender is
+	// revealed.
+	Sealed bool `json:"sealed,omitempty"`
+	// Price is lowered in steps on auction tenders, PricedAt is when it was
+	// set last.
+	Price    *float64   `json:"price,omitempty"`
+	PricedAt *time.Time `json:"pricedAt,omitempty"`
+	// AttachmentIDs are the files of this version of the bid.
+	AttachmentIDs []string `json:"attachmentIds,omitempty"`
+}
+
+// SealedContent is the encrypted name and description of a bid, or of one
+// of its versions, on a sealed tender.
+type SealedContent struct {
+	BidID   string
+	Version int
+	Content []byte
+}
+
+// RevealedBid is the decrypted SealedContent.
+type RevealedBid struct {
+	BidID       string
+	Version     int
+	Name        string
+	Description string
+}
+
+type BidWithdraw struct {
+	Reason string `json:"reason" binding:"required,max=500"`
 }
 
 
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+type DebarmentScope string
+
+const (
+	// DebarmentScopeOrganization blocks the supplier from the tenders of the
+	// organization that debarred it.
+	DebarmentScopeOrganization DebarmentScope = "Organization"
+	// DebarmentScopeGlobal blocks the supplier from all tenders. Only
+	// platform admins debar globally.
+	DebarmentScopeGlobal DebarmentScope = "Global"
+)
+
+// Debarment blocks a supplier organization from bidding. It is in force from
+// ValidFrom until ValidUntil, or for good without one, unless it is lifted
+// before.
+type Debarment struct {
+	ID         string         `json:"id"`
+	SupplierID OrganizationID `json:"supplierId"`
+	Scope      DebarmentScope `json:"scope"`
+	// OrganizationID is the debarring organization of an Organization scope
+	// debarment.
+	OrganizationID *OrganizationID `json:"organizationId,omitempty"`
+	Reason         string          `json:"reason"`
+	ValidFrom      time.Time       `json:"validFrom"`
+	ValidUntil     *time.Time      `json:"validUntil,omitempty"`
+	CreatedBy      string          `json:"createdBy"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	LiftedBy       string          `json:"liftedBy,omitempty"`
+	LiftedAt       *time.Time      `json:"liftedAt,omitempty"`
+	LiftReason     string          `json:"liftReason,omitempty"`
+}
+
+// InForce reports whether the debarment blocks the supplier at the moment.
+func (d *Debarment) InForce(at time.Time) bool {
+	return d.LiftedAt == nil && !d.ValidFrom.After(at) && (d.ValidUntil == nil || d.ValidUntil.After(at))
+}
+
+type DebarmentCreate struct {
+	SupplierID     OrganizationID  `json:"supplierId" binding:"required,uuid"`
+	Scope          DebarmentScope  `json:"scope" binding:"required,oneof=Organization Global"`
+	OrganizationID *OrganizationID `json:"organizationId" binding:"required_if=Scope Organization,excluded_if=Scope Global,omitempty,uuid"`
+	Reason         string          `json:"reason" binding:"required,max=1000"`
+	// ValidFrom defaults to now.
+	ValidFrom  *time.Time `json:"validFrom"`
+	ValidUntil *time.Time `json:"validUntil"`
+}
+
+// DebarmentEdit changes the reason or shortens or extends the validity of a
+// debarment in force.
+type DebarmentEdit struct {
+	Reason     *string    `json:"reason" binding:"omitempty,min=1,max=1000"`
+	ValidUntil *time.Time `json:"validUntil"`
+}
+
+type DebarmentLift struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Criterion is a weighted aspect, such as price or delivery time, the bids
+// on a tender are scored on. The weights of a tender add up to 100.
+type Criterion struct {
+	ID       string `json:"id"`
+	TenderID string `json:"tenderId"`
+	Name     string `json:"name"`
+	Weight   int32  `json:"weight"`
+}
+
+type CriterionCreate struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	Weight int32  `json:"weight" binding:"required,min=1,max=100"`
+}
+
+type TenderCriteria struct {
+	Criteria []*CriterionCreate `json:"criteria" binding:"required,min=1,max=10,dive"`
+}
+
+type CriterionScore struct {
+	CriterionID string `json:"criterionId" binding:"required,uuid"`
+	Score       int32  `json:"score" binding:"min=0,max=10"`
+}
+
+type BidScores struct {
+	Scores []*CriterionScore `json:"scores" binding:"required,min=1,dive"`
+}
+
+// BidScore is the score an evaluator gave a bid on one criterion.
+type BidScore struct {
+	BidID       string    `json:"bidId"`
+	CriterionID string    `json:"criterionId"`
+	UserID      string    `json:"userId"`
+	Score       int32     `json:"score"`
+	ScoredAt    time.Time `json:"scoredAt"`
+}
+
+// Evaluation is the weighted ranking of the published bids on a tender, best
+// first.
+type Evaluation struct {
+	TenderID string           `json:"tenderId"`
+	Criteria []*Criterion     `json:"criteria"`
+	Bids     []*BidEvaluation `json:"bids"`
+}
+
+// BidEvaluation is the place of a bid in an Evaluation. Rank is 0 until at
+// least one evaluator has scored the bid on every criterion.
+type BidEvaluation struct {
+	Rank       int                   `json:"rank"`
+	BidID      string                `json:"bidId"`
+	BidName    string                `json:"bidName"`
+	Consensus  *Consensus            `json:"consensus"`
+	Evaluators []*EvaluatorScorecard `json:"evaluators"`
+}
+
+// EvaluatorScorecard holds the scores of one evaluator by criterion ID. Total
+// is the weighted score, set once every criterion is scored.
+type EvaluatorScorecard struct {
+	UserID   string           `json:"userId"`
+	Username string           `json:"username"`
+	Scores   map[string]int32 `json:"scores"`
+	Total    *float64         `json:"total"`
+}
+
+// Consensus averages the complete scorecards of a bid by criterion ID.
+type Consensus struct {
+	Evaluators int                `json:"evaluators"`
+	Scores     map[string]float64 `json:"scores"`
+	Total      *float64           `json:"total"`
+}
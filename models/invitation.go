@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TenderInvitation lets the responsibles of an organization see an
+// invitation-only tender and bid on it.
+type TenderInvitation struct {
+	TenderID       string         `json:"tenderId"`
+	OrganizationID OrganizationID `json:"organizationId"`
+	InvitedAt      time.Time      `json:"invitedAt"`
+}
+
+type InvitationCreate struct {
+	OrganizationIDs []OrganizationID `json:"organizationIds" binding:"required,min=1,max=100,dive,uuid"`
+}
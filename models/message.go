@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// MessageRole is the side of the negotiation a message comes from.
+type MessageRole string
+
+const (
+	MessageRoleBuyer  MessageRole = "Buyer"
+	MessageRoleBidder MessageRole = "Bidder"
+)
+
+// BidMessage is a message in the negotiation thread of a bid. AuthorID is
+// nil for the feedback left before threads existed.
+type BidMessage struct {
+	ID          string               `json:"id"`
+	BidID       string               `json:"bidId"`
+	AuthorID    *string              `json:"authorId"`
+	Role        MessageRole          `json:"role"`
+	ReplyTo     *string              `json:"replyTo,omitempty"`
+	Body        string               `json:"body"`
+	Attachments []*MessageAttachment `json:"attachments"`
+	ReadBy      []*ReadReceipt       `json:"readBy"`
+	CreatedAt   time.Time            `json:"createdAt"`
+}
+
+// MessageAttachment links a file to a message.
+type MessageAttachment struct {
+	Name string `json:"name" binding:"required,max=255"`
+	URL  string `json:"url" binding:"required,url,max=2000"`
+}
+
+type ReadReceipt struct {
+	UserID string    `json:"userId"`
+	ReadAt time.Time `json:"readAt"`
+}
+
+type BidMessageCreate struct {
+	Body        string               `json:"body" binding:"required,max=2000"`
+	ReplyTo     *string              `json:"replyTo" binding:"omitempty,uuid"`
+	Attachments []*MessageAttachment `json:"attachments" binding:"omitempty,max=5,dive"`
+}
+
+// MessagesRead is the number of messages a read receipt was added to.
+type MessagesRead struct {
+	Read int `json:"read"`
+}
+
+// Review is the legacy view of a buyer message, as the feedback endpoints
+// return it.
+func (m *BidMessage) Review() *BidReviewResponse {
+	return &BidReviewResponse{
+		ID:          m.ID,
+		BidID:       m.BidID,
+		Description: BidFeedback(m.Body),
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type NotificationKind string
+
+const (
+	NotificationKindTenderCancelled  NotificationKind = "TenderCancelled"
+	NotificationKindQuestionAnswered NotificationKind = "QuestionAnswered"
+)
+
+type Notification struct {
+	ID          string           `json:"id"`
+	RecipientID string           `json:"-"`
+	Kind        NotificationKind `json:"kind"`
+	TenderID    string           `json:"tenderId,omitempty"`
+	BidID       string           `json:"bidId,omitempty"`
+	Message     string           `json:"message"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
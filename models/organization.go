@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type OrganizationType string
+
+const (
+	OrganizationTypeIE  OrganizationType = "IE"
+	OrganizationTypeLLC OrganizationType = "LLC"
+	OrganizationTypeJSC OrganizationType = "JSC"
+)
+
+type Organization struct {
+	ID          OrganizationID   `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Type        OrganizationType `json:"type"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+type Employee struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
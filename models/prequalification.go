@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// AnswerType is the type the answer to a prequalification question must
+// parse as.
+type AnswerType string
+
+const (
+	AnswerTypeText    AnswerType = "Text"
+	AnswerTypeNumber  AnswerType = "Number"
+	AnswerTypeBoolean AnswerType = "Boolean"
+	// AnswerTypeDate answers are dates in the YYYY-MM-DD format.
+	AnswerTypeDate AnswerType = "Date"
+)
+
+// Questionnaire is what an organization asks suppliers before they may bid
+// on its tenders of one service type.
+type Questionnaire struct {
+	ID             string                      `json:"id"`
+	OrganizationID OrganizationID              `json:"organizationId"`
+	ServiceType    TenderServiceType           `json:"serviceType"`
+	Questions      []*PrequalificationQuestion `json:"questions"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	UpdatedAt      time.Time                   `json:"updatedAt"`
+}
+
+type PrequalificationQuestion struct {
+	ID         string     `json:"id"`
+	Text       string     `json:"text"`
+	AnswerType AnswerType `json:"answerType"`
+	Required   bool       `json:"required"`
+}
+
+type PrequalificationQuestionCreate struct {
+	Text       string     `json:"text" binding:"required,max=1000"`
+	AnswerType AnswerType `json:"answerType" binding:"required,oneof=Text Number Boolean Date"`
+	Required   bool       `json:"required"`
+}
+
+// QuestionnaireSet replaces the questions of the questionnaire of the
+// organization for the service type, creating it if needed.
+type QuestionnaireSet struct {
+	OrganizationID OrganizationID                    `json:"organizationId" binding:"required,uuid"`
+	ServiceType    TenderServiceType                 `json:"serviceType" binding:"required,oneof=Construction Delivery Manufacture"`
+	Questions      []*PrequalificationQuestionCreate `json:"questions" binding:"required,min=1,max=50,dive"`
+}
+
+type ApplicationStatus string
+
+const (
+	ApplicationStatusSubmitted ApplicationStatus = "Submitted"
+	ApplicationStatusApproved  ApplicationStatus = "Approved"
+	ApplicationStatusRejected  ApplicationStatus = "Rejected"
+	ApplicationStatusRevoked   ApplicationStatus = "Revoked"
+)
+
+// Application is the answer of a supplier organization to a questionnaire.
+// An approval is valid until ValidUntil unless it is revoked.
+type Application struct {
+	ID               string            `json:"id"`
+	QuestionnaireID  string            `json:"questionnaireId"`
+	OrganizationID   OrganizationID    `json:"organizationId"`
+	SubmittedBy      string            `json:"submittedBy"`
+	SubmittedAt      time.Time         `json:"submittedAt"`
+	Status           ApplicationStatus `json:"status"`
+	Answers          []*Answer         `json:"answers"`
+	ReviewedBy       string            `json:"reviewedBy,omitempty"`
+	ReviewedAt       *time.Time        `json:"reviewedAt,omitempty"`
+	ReviewComment    string            `json:"reviewComment,omitempty"`
+	ValidUntil       *time.Time        `json:"validUntil,omitempty"`
+	RevokedBy        string            `json:"revokedBy,omitempty"`
+	RevokedAt        *time.Time        `json:"revokedAt,omitempty"`
+	RevocationReason string            `json:"revocationReason,omitempty"`
+	// Licenses are the files attached to the application. They are only
+	// listed when a single application is read.
+	Licenses []*Attachment `json:"licenses,omitempty"`
+}
+
+type Answer struct {
+	QuestionID string `json:"questionId" binding:"required,uuid"`
+	Value      string `json:"value" binding:"max=1000"`
+}
+
+type ApplicationCreate struct {
+	OrganizationID OrganizationID `json:"organizationId" binding:"required,uuid"`
+	Answers        []*Answer      `json:"answers" binding:"required,max=50,dive"`
+}
+
+type ApplicationDecision string
+
+const (
+	ApplicationDecisionApproved ApplicationDecision = "Approved"
+	ApplicationDecisionRejected ApplicationDecision = "Rejected"
+)
+
+type ApplicationReview struct {
+	Decision   ApplicationDecision `json:"decision" binding:"required,oneof=Approved Rejected"`
+	ValidUntil *time.Time          `json:"validUntil" binding:"required_if=Decision Approved"`
+	Comment    string              `json:"comment" binding:"max=1000"`
+}
+
+type ApplicationRevocation struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type QuestionVisibility string
+
+const (
+	QuestionVisibilityPublic  QuestionVisibility = "Public"
+	QuestionVisibilityPrivate QuestionVisibility = "Private"
+)
+
+// Question is a clarification question about a tender. AskerID is only
+// shown to the asker and to the responsibles of the tender.
+type Question struct {
+	ID             string              `json:"id"`
+	TenderID       string              `json:"tenderId"`
+	AskerID        string              `json:"askerId,omitempty"`
+	Question       string              `json:"question"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	Answer         *string             `json:"answer,omitempty"`
+	Visibility     *QuestionVisibility `json:"visibility,omitempty"`
+	AnsweredAt     *time.Time          `json:"answeredAt,omitempty"`
+	AmendedVersion *int32              `json:"amendedVersion,omitempty"`
+}
+
+type QuestionCreate struct {
+	Question string `json:"question" binding:"required,max=1000"`
+}
+
+// QuestionAnswer answers a question. An Amendment edits the tender along
+// with the answer, which bumps its version.
+type QuestionAnswer struct {
+	Answer     string             `json:"answer" binding:"required,max=1000"`
+	Visibility QuestionVisibility `json:"visibility" binding:"required,oneof=Public Private"`
+	Amendment  *TenderEdit        `json:"amendment" binding:"omitempty"`
+}
@@ -16,6 +16,24 @@ type TenderResponse struct {
 	Version         int               `json:"version"`
 	CreatedAt       time.Time         `json:"createdAt"`
 	CreatorUsername string            `json:"-"`
+	// CancellationReason is set once the tender is Cancelled.
+	CancellationReason string `json:"cancellationReason,omitempty"`
+	// SubmissionDeadline closes the tender for new bids and bid changes.
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	// Sealed tenders hide the contents of their bids from everyone until
+	// the deadline has passed and the bids are revealed.
+	Sealed     bool       `json:"sealed"`
+	RevealedAt *time.Time `json:"revealedAt,omitempty"`
+	// Auction is set on tenders run as a reverse auction.
+	Auction *Auction `json:"auction,omitempty"`
+	// AttachmentIDs are the files of this version of the tender.
+	AttachmentIDs []string `json:"attachmentIds,omitempty"`
+	// Visibility is not tracked by the versions, it cannot be edited.
+	Visibility TenderVisibility `json:"visibility,omitempty"`
+	// Prequalification admits the suppliers approved by the questionnaire of
+	// the organization for the service type only, and only offers made on
+	// behalf of such a supplier. Not tracked by the versions either.
+	Prequalification bool `json:"prequalification,omitempty"`
 }
 
 type TenderEdit struct {
@@ -28,6 +46,20 @@ const (
 	TenderStatusCreated   TenderStatus = "Created"
 	TenderStatusPublished TenderStatus = "Published"
 	TenderStatusClosed    TenderStatus = "Closed"
+	TenderStatusCancelled TenderStatus = "Cancelled"
+)
+
+// TenderVisibility decides who, besides the responsibles of its
+// organization, sees a published tender and may bid on it.
+type TenderVisibility string
+
+const (
+	TenderVisibilityPublic TenderVisibility = "Public"
+	// TenderVisibilityInvitation limits the tender to the responsibles of
+	// the invited organizations.
+	TenderVisibilityInvitation TenderVisibility = "Invitation"
+	// TenderVisibilityInternal keeps the tender within its organization.
+	TenderVisibilityInternal TenderVisibility = "Internal"
 )
 
 type TenderServiceType string
@@ -41,11 +73,23 @@ const (
 type OrganizationID string
 
 type TenderCreate struct {
-	Name            string            `json:"name" binding:"required,max=100"`
-	Description     string            `json:"description" binding:"required,max=500"`
-	ServiceType     TenderServiceType `json:"serviceType" binding:"required,oneof=Construction Delivery Manufacture"`
-	OrganizationID  OrganizationID    `json:"organizationId" binding:"required,max=100,uuid"`
-	CreatorUsername string            `json:"creatorUsername" binding:"required,max=50"`
+	Name               string            `json:"name" binding:"required,max=100"`
+	Description        string            `json:"description" binding:"required,max=500"`
+	ServiceType        TenderServiceType `json:"serviceType" binding:"required,oneof=Construction Delivery Manufacture"`
+	OrganizationID     OrganizationID    `json:"organizationId" binding:"required,max=100,uuid"`
+	CreatorUsername    string            `json:"creatorUsername" binding:"required,max=50"`
+	SubmissionDeadline *time.Time        `json:"submissionDeadline" binding:"required_if=Sealed true"`
+	Sealed             bool              `json:"sealed"`
+	Auction            *Auction          `json:"auction" binding:"omitempty"`
+	// Visibility defaults to Public. Only an Invitation tender has
+	// InvitedOrganizations.
+	Visibility           TenderVisibility `json:"visibility" binding:"omitempty,oneof=Public Invitation Internal"`
+	InvitedOrganizations []OrganizationID `json:"invitedOrganizations" binding:"omitempty,dive,uuid"`
+	Prequalification     bool             `json:"prequalification"`
+}
+
+type TenderCancel struct {
+	Reason string `json:"reason" binding:"required,max=500"`
 }
 
 func (t *TenderEdit) IsEmpty() bool {